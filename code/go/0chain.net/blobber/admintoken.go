@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/admintoken"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+const adminTokenCommand = "admin-token"
+
+// runAdminToken issues, revokes and lists the admin tokens of the blobber. It
+// only needs the config and the metadata database. The secret of an issued
+// token is printed once, it cannot be retrieved later on.
+//
+//	blobber admin-token issue --name <name> --scopes read-stats,debug [--expires 720h]
+//	blobber admin-token revoke --name <name>
+//	blobber admin-token list
+func runAdminToken(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: blobber admin-token <issue|revoke|list> [flags]")
+		return 2
+	}
+	verb := args[0]
+	if verb != "issue" && verb != "revoke" && verb != "list" {
+		fmt.Fprintf(os.Stderr, "Unknown admin-token verb %q\n", verb)
+		return 2
+	}
+
+	var (
+		name    string
+		scopes  string
+		expires time.Duration
+	)
+
+	fs := flag.NewFlagSet(adminTokenCommand+" "+verb, flag.ContinueOnError)
+	fs.StringVar(&name, "name", "", "name of the token, e.g. the operator it is issued to")
+	fs.StringVar(&scopes, "scopes", "", "comma separated scopes of the token: "+strings.Join(admintoken.Scopes, ", "))
+	fs.DurationVar(&expires, "expires", 30*24*time.Hour, "validity of the token")
+	fs.StringVar(&configDir, "config_dir", "./config", "config_dir")
+	fs.IntVar(&deploymentMode, "deployment_mode", 2, "deployment mode: 0=dev,1=test, 2=mainnet")
+	fs.StringVar(&mountPoint, "files_dir", "", "Mounted partition where all files will be stored")
+	fs.StringVar(&logDir, "log_dir", os.TempDir(), "log_dir")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if verb != "list" && name == "" {
+		fmt.Fprintln(os.Stderr, "Please specify --name which is the name of the token")
+		return 2
+	}
+
+	// keep the setup progress output off the token
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	setupConfig(configDir, deploymentMode)
+	setupLogging()
+	err := datastore.GetStore().Open()
+	os.Stdout = stdout
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error connecting to the data store: "+err.Error())
+		return 1
+	}
+	defer datastore.GetStore().Close()
+
+	ctx := context.Background()
+	switch verb {
+	case "issue":
+		var list []string
+		for _, s := range strings.Split(scopes, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+		secret, token, err := admintoken.Issue(ctx, name, list, expires)
+		recordAdminToken(ctx, name, fmt.Sprintf("issue admin token, scopes %s, valid for %s",
+			strings.Join(list, ","), expires), err)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error issuing the token: "+err.Error())
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Token %q expires at %s, keep it now, it is not shown again:\n",
+			token.Name, common.ToTime(token.ExpiresAt).Format(time.RFC3339))
+		fmt.Println(secret)
+	case "revoke":
+		err := admintoken.Revoke(ctx, name)
+		recordAdminToken(ctx, name, "revoke admin token", err)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error revoking the token: "+err.Error())
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Token %q revoked\n", name)
+	case "list":
+		tokens, err := admintoken.List(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error listing the tokens: "+err.Error())
+			return 1
+		}
+		now := common.Now()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSCOPES\tSTATUS\tEXPIRES\tLAST USED")
+		for _, t := range tokens {
+			lastUsed := "never"
+			if t.LastUsedAt > 0 {
+				lastUsed = common.ToTime(t.LastUsedAt).Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.Name, strings.Join(t.Scopes, ","), t.Status(now),
+				common.ToTime(t.ExpiresAt).Format(time.RFC3339), lastUsed)
+		}
+		w.Flush()
+	}
+	return 0
+}
+
+// recordAdminToken records the management of the token in the audit log of the
+// blobber.
+func recordAdminToken(ctx context.Context, name, message string, err error) {
+	e := &auditlog.Entry{
+		Operation: auditlog.OpAdmin,
+		ClientID:  name,
+	}
+	e.SetError(err)
+	if err != nil {
+		message += ": " + err.Error()
+	}
+	e.Message = message
+	auditlog.RecordSync(ctx, e)
+}
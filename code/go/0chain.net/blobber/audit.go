@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+)
+
+const auditWMCommand = "audit-wm"
+
+// exit codes of the audit-wm command
+const (
+	auditOK     = 0
+	auditBreaks = 1
+	auditFailed = 2
+)
+
+// runAuditWM checks the stored write marker chain of an allocation offline and
+// prints a JSON report. It only needs the config and the metadata database.
+//
+//	blobber audit-wm --allocation <id> [--config_dir ./config] [--output report.json]
+func runAuditWM(args []string) int {
+	var (
+		allocationID string
+		output       string
+	)
+
+	fs := flag.NewFlagSet(auditWMCommand, flag.ContinueOnError)
+	fs.StringVar(&allocationID, "allocation", "", "ID of the allocation to audit")
+	fs.StringVar(&output, "output", "", "file to write the report to, stdout if empty")
+	fs.StringVar(&configDir, "config_dir", "./config", "config_dir")
+	fs.IntVar(&deploymentMode, "deployment_mode", 2, "deployment mode: 0=dev,1=test, 2=mainnet")
+	fs.StringVar(&mountPoint, "files_dir", "", "Mounted partition where all files will be stored")
+	fs.StringVar(&logDir, "log_dir", os.TempDir(), "log_dir")
+	if err := fs.Parse(args); err != nil {
+		return auditFailed
+	}
+
+	if allocationID == "" {
+		fmt.Fprintln(os.Stderr, "Please specify --allocation which is the allocation to audit")
+		return auditFailed
+	}
+
+	// keep the setup progress output off the report
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	setupConfig(configDir, deploymentMode)
+	setupLogging()
+	err := datastore.GetStore().Open()
+	os.Stdout = stdout
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error connecting to the data store: "+err.Error())
+		return auditFailed
+	}
+	defer datastore.GetStore().Close()
+
+	ctx := datastore.GetStore().CreateTransaction(context.Background(), &sql.TxOptions{
+		ReadOnly: true,
+	})
+	tx := datastore.GetStore().GetTransaction(ctx)
+	defer tx.Rollback()
+
+	report, err := writemarker.AuditChain(ctx, allocationID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error auditing write markers: "+err.Error())
+		return auditFailed
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error marshaling the report: "+err.Error())
+		return auditFailed
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+	} else if err := os.WriteFile(output, data, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "Error writing the report: "+err.Error())
+		return auditFailed
+	}
+
+	if !report.OK {
+		return auditBreaks
+	}
+	return auditOK
+}
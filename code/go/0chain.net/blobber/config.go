@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
@@ -17,6 +18,12 @@ func setupConfig(configDir string, deploymentMode int) {
 
 	// setup config file
 	config.SetupConfig(configDir)
+	config.OnReload(func(file string) {
+		auditlog.Record(context.TODO(), &auditlog.Entry{
+			Operation: auditlog.OpConfigReload,
+			Message:   "config file " + file,
+		})
+	})
 
 	if mountPoint != "" {
 		config.Configuration.MountPoint = mountPoint
@@ -16,6 +16,9 @@ func setupFileStore() (err error) {
 	}
 
 	filestore.SetFileStore(fs)
+	if err = filestore.RegisterMetrics(fs); err != nil {
+		return
+	}
 
 	fmt.Print("	[OK]\n")
 	return nil
@@ -19,6 +19,7 @@ var (
 	httpsKeyFile   string
 	httpsCertFile  string
 	hostUrl        string
+	devChain       string
 )
 
 func init() {
@@ -37,6 +38,8 @@ func init() {
 	flag.StringVar(&hostUrl, "hosturl", "", "register url on blockchain instead of [schema://hostname+port] if it has value")
 
 	flag.IntVar(&grpcPort, "grpc_port", 0, "grpc_port")
+
+	flag.StringVar(&devChain, "dev-chain", "", "start, or join, an in-process chain simulator on this address (e.g. 127.0.0.1:9091) instead of connecting to block_worker")
 }
 
 func parseFlags() {
@@ -13,6 +13,7 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/handler"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -26,7 +27,15 @@ func startHttpServer() {
 	}
 
 	r := mux.NewRouter()
-	initHandlers(r, config.Development())
+	initHandlers(r)
+
+	// the metrics have a listener of their own, shared by the http and the https servers
+	if addr := config.Configuration.MetricsAddress; addr != "" {
+		go func() {
+			err := metrics.Serve(addr)
+			logging.Logger.Error("metrics server shut down", zap.Error(err))
+		}()
+	}
 
 	var wg sync.WaitGroup
 
@@ -104,12 +113,11 @@ func startServer(wg *sync.WaitGroup, r *mux.Router, mode string, port int, isTls
 	}
 }
 
-func initHandlers(r *mux.Router, devMode bool) {
+func initHandlers(r *mux.Router) {
 	handler.StartTime = time.Now().UTC()
 	r.HandleFunc("/", handler.HomepageHandler)
 	handler.SetupHandlers(r)
 	handler.SetupSwagger()
-	common.SetAdminCredentials(devMode)
 	common.Set0boxDetails()
 }
 
@@ -1,12 +1,24 @@
 package main
 
 import (
+	"os"
+
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case auditWMCommand:
+			os.Exit(runAuditWM(os.Args[2:]))
+		case wmAdminCommand:
+			os.Exit(runWMAdmin(os.Args[2:]))
+		case adminTokenCommand:
+			os.Exit(runAdminToken(os.Args[2:]))
+		}
+	}
 
 	parseFlags()
 
@@ -14,6 +26,11 @@ func main() {
 
 	setupLogging()
 
+	if err := setupTracing(); err != nil {
+		logging.Logger.Error("Error setting up tracing " + err.Error())
+		panic(err)
+	}
+
 	if err := setupDatabase(); err != nil {
 		logging.Logger.Error("Error setting up data store" + err.Error())
 		panic(err)
@@ -29,6 +46,11 @@ func main() {
 		panic(err)
 	}
 
+	if err := setupDevChain(); err != nil {
+		logging.Logger.Error("Error setting up dev chain " + err.Error())
+		panic(err)
+	}
+
 	if err := setupServerChain(); err != nil {
 		logging.Logger.Error("Error setting up server chain " + err.Error())
 		panic(err)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/storage"
+)
+
+// verifySelfChallenge runs the checks of the validators on the request built
+// for a self challenge.
+func verifySelfChallenge(sc *challenge.SelfChallenge, request []byte) error {
+	var challengeRequest storage.ChallengeRequest
+	if err := json.Unmarshal(request, &challengeRequest); err != nil {
+		return common.NewError("input_decode_error", "Error in decoding the challenge request. "+err.Error())
+	}
+
+	challengeObj := &storage.Challenge{
+		ID:             sc.ID,
+		RandomNumber:   sc.Seed,
+		AllocationID:   sc.AllocationID,
+		AllocationRoot: sc.AllocationRoot,
+		BlobberID:      node.Self.ID,
+		Timestamp:      sc.Timestamp,
+	}
+	return challengeRequest.VerifyChallenge(challengeObj, &storage.Allocation{ID: sc.AllocationID})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	"go.uber.org/zap"
+)
+
+func setupTracing() error {
+	fmt.Print("> init tracing")
+
+	shutdown, err := tracing.Setup("blobber", config.Configuration.Tracing)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-common.GetRootContext().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logging.Logger.Error("Error flushing the traces", zap.Error(err))
+		}
+	}()
+
+	fmt.Print("		[OK]\n")
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const wmAdminCommand = "wm-admin"
+
+// wm-admin verbs and the admin api endpoints they call
+var wmAdminVerbs = map[string]struct {
+	method string
+	path   string
+}{
+	"list":   {http.MethodGet, ""},
+	"redeem": {http.MethodPost, "/redeem"},
+	"pause":  {http.MethodPost, "/pause"},
+	"resume": {http.MethodPost, "/resume"},
+	"resync": {http.MethodPost, "/resync"},
+}
+
+// runWMAdmin calls the write marker redemption admin api of a running blobber
+// and prints the JSON response.
+//
+//	blobber wm-admin list --allocation <id> [--filter pending|failed]
+//	blobber wm-admin redeem --allocation <id> [--start_seq n] [--end_seq m]
+//	blobber wm-admin pause|resume|resync --allocation <id>
+func runWMAdmin(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: blobber wm-admin <list|redeem|pause|resume|resync> --allocation <id> [flags]")
+		return 2
+	}
+
+	verb, ok := wmAdminVerbs[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown wm-admin verb %q\n", args[0])
+		return 2
+	}
+
+	var (
+		blobberURL   string
+		token        string
+		allocationID string
+		filter       string
+		startSeq     int64
+		endSeq       int64
+		limit        int
+		offset       int
+	)
+
+	fs := flag.NewFlagSet(wmAdminCommand+" "+args[0], flag.ContinueOnError)
+	fs.StringVar(&blobberURL, "url", "http://localhost:5051", "url of the blobber")
+	fs.StringVar(&token, "token", os.Getenv("BLOBBER_ADMIN_TOKEN"), "admin token, defaults to $BLOBBER_ADMIN_TOKEN")
+	fs.StringVar(&allocationID, "allocation", "", "ID of the allocation")
+	fs.StringVar(&filter, "filter", "", "list only pending or failed markers")
+	fs.Int64Var(&startSeq, "start_seq", 0, "first sequence to redeem")
+	fs.Int64Var(&endSeq, "end_seq", 0, "sequence of the write marker to redeem")
+	fs.IntVar(&limit, "limit", 0, "number of markers to list")
+	fs.IntVar(&offset, "offset", 0, "offset of the markers to list")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if allocationID == "" {
+		fmt.Fprintln(os.Stderr, "Please specify --allocation which is the allocation to manage")
+		return 2
+	}
+
+	query := url.Values{}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	if startSeq > 0 {
+		query.Set("start_seq", fmt.Sprint(startSeq))
+	}
+	if endSeq > 0 {
+		query.Set("end_seq", fmt.Sprint(endSeq))
+	}
+	if limit > 0 {
+		query.Set("limit", fmt.Sprint(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", fmt.Sprint(offset))
+	}
+
+	u := strings.TrimRight(blobberURL, "/") + "/_writemarkers/" + url.PathEscape(allocationID) + verb.path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(verb.method, u, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error creating request: "+err.Error())
+		return 1
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// force redeem waits for the close connection transaction to be verified
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error calling the blobber: "+err.Error())
+		return 1
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading the response: "+err.Error())
+		return 1
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Blobber responded with %s: %s\n", resp.Status, body)
+		return 1
+	}
+	fmt.Println(string(body))
+	return 0
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/handler"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/ratelimit"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/stats"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
@@ -22,11 +25,14 @@ func setupWorkers(ctx context.Context) {
 	challenge.SetupWorkers(ctx)
 	readmarker.SetupWorkers(ctx)
 	writemarker.SetupWorkers(ctx)
+	ratelimit.SetupWorkers(ctx)
 	allocation.StartUpdateWorker(ctx, config.Configuration.UpdateAllocationsInterval)
 	allocation.StartFinalizeWorker(ctx, config.Configuration.FinalizeAllocationsInterval)
 	allocation.SetupWorkers(ctx)
 	challenge.SetupChallengeCleanUpWorker(ctx)
 	challenge.SetupChallengeTimingsCleanupWorker(ctx)
+	challenge.SetSelfChallengeVerifier(verifySelfChallenge)
+	challenge.SetupSelfChallengeWorker(ctx)
 	stats.SetupStatsWorker(ctx)
 	updateStorageScConfigWorker(ctx)
 }
@@ -39,6 +45,8 @@ func startRefreshSettings(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-time.After(REPEAT_DELAY * time.Second):
+			var before, after config.Settings
+			_ = before.CopyFrom(&config.Configuration)
 			err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 				_, e := config.ReloadFromChain(ctx, datastore.GetStore().GetDB())
 				return e
@@ -49,6 +57,14 @@ func startRefreshSettings(ctx context.Context) {
 			}
 
 			logging.Logger.Info("success to refresh blobber settings from chain")
+			_ = after.CopyFrom(&config.Configuration)
+			if after != before {
+				auditlog.Record(ctx, &auditlog.Entry{
+					Operation: auditlog.OpConfigReload,
+					Message: fmt.Sprintf("settings from chain: capacity %d, read price %v, write price %v, service charge %v, delegates %d",
+						after.Capacity, after.ReadPrice, after.WritePrice, after.ServiceCharge, after.NumDelegates),
+				})
+			}
 
 		}
 
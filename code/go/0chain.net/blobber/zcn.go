@@ -10,6 +10,7 @@ import (
 	handleCommon "github.com/0chain/blobber/code/go/0chain.net/core/common/handler"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/dev"
 	"github.com/0chain/gosdk/zboxcore/sdk"
 	"github.com/0chain/gosdk/zcncore"
 	"go.uber.org/zap"
@@ -74,6 +75,20 @@ func registerOnChain() error {
 	return err
 }
 
+// setupDevChain starts the chain simulator of --dev-chain, the blobber then
+// connects to it as to its block worker.
+func setupDevChain() error {
+	if devChain == "" {
+		return nil
+	}
+	fmt.Print("> setup dev chain")
+	if err := dev.SetupChain(devChain, config.Configuration.ChainID, config.Configuration.SignatureScheme); err != nil {
+		return err
+	}
+	fmt.Print("	[OK]\n")
+	return nil
+}
+
 func setupServerChain() error {
 	fmt.Print("> setup server chain")
 	common.SetupRootContext(node.GetNodeContext())
@@ -0,0 +1,143 @@
+package admintoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidToken is a token unknown, expired or revoked.
+	ErrInvalidToken = errors.New("invalid admin token")
+	// ErrScope is a token lacking the scope of the call.
+	ErrScope = errors.New("admin token lacks the scope")
+	// ErrNotFound is a name of no active token.
+	ErrNotFound = errors.New("admin token not found")
+)
+
+// Issue creates the token name granting scopes for ttl and returns its secret,
+// which is not stored and cannot be retrieved later on.
+func Issue(ctx context.Context, name string, scopes []string, ttl time.Duration) (string, *Token, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 64 {
+		return "", nil, common.NewError("invalid_parameters", "token name must be 1 to 64 characters")
+	}
+	if len(scopes) == 0 {
+		return "", nil, common.NewError("invalid_parameters", "token needs at least one scope")
+	}
+	for _, s := range scopes {
+		if !ValidScope(s) {
+			return "", nil, common.NewErrorf("invalid_parameters", "unknown scope %q, scopes are %s", s, strings.Join(Scopes, ", "))
+		}
+	}
+	if ttl <= 0 {
+		return "", nil, common.NewError("invalid_parameters", "token expiry must be positive")
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	now := common.Now()
+	t := &Token{
+		Name:      name,
+		Hash:      hashSecret(secret),
+		Scopes:    scopes,
+		ExpiresAt: now + common.Timestamp(ttl/time.Second),
+		CreatedAt: now,
+	}
+
+	err = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		var count int64
+		err := tx.Model(&Token{}).Where("name = ? AND revoked_at = 0 AND expires_at > ?", name, now).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return common.NewErrorf("token_exists", "an active token is already named %q, revoke it first", name)
+		}
+		return tx.Create(t).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, t, nil
+}
+
+// Revoke revokes the active tokens named name.
+func Revoke(ctx context.Context, name string) error {
+	return datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		res := tx.Model(&Token{}).Where("name = ? AND revoked_at = 0", name).
+			Update("revoked_at", common.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	}, datastore.WithParent(ctx))
+}
+
+// List returns the tokens, the expired and revoked ones included.
+func List(ctx context.Context) ([]*Token, error) {
+	var tokens []*Token
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return tx.Order("id").Find(&tokens).Error
+	}, datastore.WithParent(ctx))
+	return tokens, err
+}
+
+// lastUsedPeriod is the period, in seconds, at which the use of a token is
+// recorded, to spare a write to most admin calls.
+const lastUsedPeriod = 60
+
+// Authenticate returns the token of the secret when it is active and grants
+// scope, and records its use at most once per lastUsedPeriod.
+func Authenticate(ctx context.Context, secret, scope string) (*Token, error) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	var t Token
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		err := tx.Where("hash = ?", hashSecret(secret)).Take(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+
+		now := common.Now()
+		if t.Status(now) != StatusActive {
+			return ErrInvalidToken
+		}
+		if !t.HasScope(scope) {
+			return ErrScope
+		}
+		if now-t.LastUsedAt < lastUsedPeriod {
+			return nil
+		}
+		t.LastUsedAt = now
+		return tx.Model(&t).Update("last_used_at", now).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		// the name of the token is logged along the refusal of the call
+		if errors.Is(err, ErrScope) {
+			return &t, err
+		}
+		return nil, err
+	}
+	return &t, nil
+}
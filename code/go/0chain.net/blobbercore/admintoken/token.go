@@ -0,0 +1,110 @@
+// Package admintoken manages the named tokens of the admin api of the blobber.
+// A token grants its scopes until it expires or is revoked. Only the hash of a
+// token is stored, the token itself is shown once when it is issued.
+package admintoken
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"gorm.io/datatypes"
+)
+
+// Scopes of the admin api.
+const (
+	// ScopeReadStats reads the statistics, the challenge timings and failures,
+	// the write marker redemption state and the audit log.
+	ScopeReadStats = "read-stats"
+	// ScopeDebug dumps the goroutines and runs self challenges.
+	ScopeDebug = "debug"
+	// ScopeConfig reads the configuration of the blobber.
+	ScopeConfig = "config"
+	// ScopeGC removes the files of the disk no longer referenced.
+	ScopeGC = "gc"
+	// ScopeRedemptionControl forces, pauses, resumes and resyncs the
+	// redemption of the write markers.
+	ScopeRedemptionControl = "redemption-control"
+	// ScopeOperator changes the settings of the blobber at run time: the access
+	// rules and quotas of the clients, the limits of the allocations and the
+	// drain.
+	ScopeOperator = "operator"
+)
+
+// Scopes lists the scopes of the admin api.
+var Scopes = []string{ScopeReadStats, ScopeDebug, ScopeConfig, ScopeGC, ScopeRedemptionControl, ScopeOperator}
+
+// ValidScope reports whether scope is a scope of the admin api.
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// secretPrefix marks the blobber admin tokens, for secret scanners.
+const secretPrefix = "bat_"
+
+// Status of a token.
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// Token is a named admin token. Its secret is not stored, only its hash.
+type Token struct {
+	ID     int64                       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name   string                      `gorm:"column:name;size:64;not null" json:"name"`
+	Hash   string                      `gorm:"column:hash;size:64;not null;unique" json:"-"`
+	Scopes datatypes.JSONSlice[string] `gorm:"column:scopes;not null" json:"scopes"`
+	// RevokedAt is zero while the token is not revoked.
+	RevokedAt  common.Timestamp `gorm:"column:revoked_at;not null;default:0" json:"revoked_at,omitempty"`
+	ExpiresAt  common.Timestamp `gorm:"column:expires_at;not null" json:"expires_at"`
+	LastUsedAt common.Timestamp `gorm:"column:last_used_at;not null;default:0" json:"last_used_at,omitempty"`
+	CreatedAt  common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (Token) TableName() string {
+	return "admin_tokens"
+}
+
+// HasScope reports whether the token grants scope.
+func (t *Token) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is the status of the token at now.
+func (t *Token) Status(now common.Timestamp) string {
+	switch {
+	case t.RevokedAt > 0:
+		return StatusRevoked
+	case now >= t.ExpiresAt:
+		return StatusExpired
+	default:
+		return StatusActive
+	}
+}
+
+// newSecret returns a new random token secret.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// hashSecret is the hash of the secret stored in the database. The secrets are
+// random, they need neither salt nor stretching.
+func hashSecret(secret string) string {
+	return encryption.Hash(secret)
+}
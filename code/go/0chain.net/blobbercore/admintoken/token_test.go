@@ -0,0 +1,41 @@
+package admintoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken_Status(t *testing.T) {
+	token := &Token{Scopes: []string{ScopeReadStats, ScopeGC}, ExpiresAt: 2000}
+
+	require.True(t, token.HasScope(ScopeGC))
+	require.False(t, token.HasScope(ScopeDebug))
+
+	require.Equal(t, StatusActive, token.Status(1999))
+	require.Equal(t, StatusExpired, token.Status(2000))
+
+	token.RevokedAt = 1500
+	require.Equal(t, StatusRevoked, token.Status(1600))
+}
+
+func TestToken_Secret(t *testing.T) {
+	a, err := newSecret()
+	require.NoError(t, err)
+	b, err := newSecret()
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(a, secretPrefix))
+	require.NotEqual(t, a, b)
+	require.NotEqual(t, hashSecret(a), hashSecret(b))
+	require.NotContains(t, hashSecret(a), strings.TrimPrefix(a, secretPrefix))
+	require.Len(t, hashSecret(a), 64)
+}
+
+func TestValidScope(t *testing.T) {
+	for _, s := range Scopes {
+		require.True(t, ValidScope(s))
+	}
+	require.False(t, ValidScope("admin"))
+}
@@ -254,6 +254,22 @@ func (cc *AllocationChangeCollector) ComputeProperties() {
 	}
 }
 
+// AffectedPaths returns the paths of the files and the directories changed by
+// the connection.
+func (cc *AllocationChangeCollector) AffectedPaths() []string {
+	var paths []string
+	seen := make(map[string]struct{})
+	for _, acp := range cc.AllocationChanges {
+		for _, path := range acp.GetPath() {
+			if _, ok := seen[path]; !ok {
+				seen[path] = struct{}{}
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
 func (cc *AllocationChangeCollector) ApplyChanges(ctx context.Context, allocationRoot, prevAllocationRoot string,
 	ts common.Timestamp, fileIDMeta map[string]string) (*reference.Ref, error) {
 	rootRef, err := cc.GetRootRef(ctx)
@@ -354,7 +370,7 @@ func (a *AllocationChangeCollector) MoveToFilestore(ctx context.Context) error {
 									zap.String("validation_root", ref.ValidationRoot))
 							}
 						}
-						err := filestore.GetFileStore().MoveToFilestore(a.AllocationID, ref.ValidationRoot, ref.FilestoreVersion)
+						err := filestore.WithContext(ctx).MoveToFilestore(a.AllocationID, ref.ValidationRoot, ref.FilestoreVersion)
 						if err != nil {
 							logging.Logger.Error(fmt.Sprintf("Error while moving file: %s", err.Error()),
 								zap.String("validation_root", ref.ValidationRoot))
@@ -368,7 +384,7 @@ func (a *AllocationChangeCollector) MoveToFilestore(ctx context.Context) error {
 										zap.String("thumbnail_hash", ref.ThumbnailHash))
 								}
 							}
-							err := filestore.GetFileStore().MoveToFilestore(a.AllocationID, ref.ThumbnailHash, ref.FilestoreVersion)
+							err := filestore.WithContext(ctx).MoveToFilestore(a.AllocationID, ref.ThumbnailHash, ref.FilestoreVersion)
 							if err != nil {
 								logging.Logger.Error(fmt.Sprintf("Error while moving thumbnail file: %s", err.Error()),
 									zap.String("thumbnail_hash", ref.ThumbnailHash))
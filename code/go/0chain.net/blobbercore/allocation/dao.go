@@ -25,6 +25,6 @@ func GetOrCreate(ctx context.Context, allocationId string) (*Allocation, error)
 		return nil, errors.ThrowLog(err.Error(), common.ErrBadDataStore)
 	}
 
-	return SyncAllocation(allocationId)
+	return SyncAllocation(ctx, allocationId)
 
 }
@@ -56,6 +56,7 @@ type Allocation struct {
 	LatestRedeemedWM string           `gorm:"column:latest_redeemed_write_marker;size:64"`
 	LastRedeemedSeq  int64            `gorm:"column:last_redeemed_sequence;default:0"`
 	IsRedeemRequired bool             `gorm:"column:is_redeem_required"`
+	RedeemPaused     bool             `gorm:"column:redeem_paused;not null;default:false"`
 	TimeUnit         time.Duration    `gorm:"column:time_unit;not null;default:172800000000000"`
 	StartTime        common.Timestamp `gorm:"column:start_time;not null"`
 	// Ending and cleaning
@@ -66,7 +66,7 @@ func FetchAllocationFromEventsDB(ctx context.Context, allocationID string, alloc
 		return // found in DB
 	}
 
-	sa, err := requestAllocation(allocationID)
+	sa, err := requestAllocation(ctx, allocationID)
 	if err != nil {
 		return nil, err
 	}
@@ -187,13 +187,13 @@ func FetchAllocationFromEventsDB(ctx context.Context, allocationID string, alloc
 	return a, nil
 }
 
-func RequestReadPoolStat(clientID string) (*ReadPool, error) {
+func RequestReadPoolStat(ctx context.Context, clientID string) (*ReadPool, error) {
 	logging.Logger.Info("request read pools")
 
 	params := map[string]string{
 		"client_id": clientID,
 	}
-	resp, err := transaction.MakeSCRestAPICall(transaction.STORAGE_CONTRACT_ADDRESS, "/getReadPoolStat", params)
+	resp, err := transaction.MakeSCRestAPICall(ctx, transaction.STORAGE_CONTRACT_ADDRESS, "/getReadPoolStat", params)
 	if err != nil {
 		return nil, fmt.Errorf("requesting read pools stat: %v", err)
 	}
@@ -210,7 +210,7 @@ func RequestReadPoolStat(clientID string) (*ReadPool, error) {
 	return nil, errors.New("empty response received from MakeSCRestAPICall")
 }
 
-func RequestWritePool(allocationID string) (wps *WritePool, err error) {
+func RequestWritePool(ctx context.Context, allocationID string) (wps *WritePool, err error) {
 	logging.Logger.Info("request write pools")
 
 	var (
@@ -220,7 +220,7 @@ func RequestWritePool(allocationID string) (wps *WritePool, err error) {
 	params := map[string]string{
 		"allocation": allocationID,
 	}
-	resp, err = transaction.MakeSCRestAPICall(transaction.STORAGE_CONTRACT_ADDRESS, "/allocation", params)
+	resp, err = transaction.MakeSCRestAPICall(ctx, transaction.STORAGE_CONTRACT_ADDRESS, "/allocation", params)
 	if err != nil {
 		return nil, fmt.Errorf("requesting write pools stat: %v", err)
 	}
@@ -150,6 +150,32 @@ func (r *Repository) GetByTx(ctx context.Context, allocationID, txHash string) (
 	return alloc, err
 }
 
+// GetIDByIdOrTx returns the id of the allocation of idOrTx, its id or the
+// hash of its transaction, from the cache when it holds the allocation under
+// idOrTx or the id claimed by the client.
+func (r *Repository) GetIDByIdOrTx(ctx context.Context, claimedID, idOrTx string) (string, error) {
+	if a := r.getAllocFromGlobalCache(idOrTx); a != nil {
+		return a.ID, nil
+	}
+	if claimedID != "" {
+		if a := r.getAllocFromGlobalCache(claimedID); a != nil && a.Tx == idOrTx {
+			return a.ID, nil
+		}
+	}
+
+	var ids []string
+	err := datastore.GetStore().GetDB().WithContext(ctx).Table(TableNameAllocation).
+		Where(SQLWhereGetById+" OR "+SQLWhereGetByTx, idOrTx, idOrTx).
+		Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", gorm.ErrRecordNotFound
+	}
+	return ids[0], nil
+}
+
 func (r *Repository) GetAllocations(ctx context.Context, offset int64) ([]*Allocation, error) {
 	var tx = datastore.GetStore().GetTransaction(ctx)
 
@@ -153,7 +153,7 @@ func shouldFinalize(sa *transaction.StorageAllocation) bool {
 }
 
 func updateAllocation(ctx context.Context, a *Allocation, selfBlobberID string) {
-	var sa, err = requestAllocation(a.ID)
+	var sa, err = requestAllocation(ctx, a.ID)
 	if err != nil {
 		logging.Logger.Error("requesting allocations from SC", zap.Error(err))
 		return
@@ -196,7 +196,7 @@ func updateAllocation(ctx context.Context, a *Allocation, selfBlobberID string)
 }
 
 func finalizeExpiredAllocations(ctx context.Context) {
-	var allocs, err = requestExpiredAllocations()
+	var allocs, err = requestExpiredAllocations(ctx)
 	if err != nil {
 		logging.Logger.Error("requesting expired allocations from SC", zap.Error(err))
 		return
@@ -207,9 +207,9 @@ func finalizeExpiredAllocations(ctx context.Context) {
 	}
 }
 
-func requestAllocation(allocID string) (sa *transaction.StorageAllocation, err error) {
+func requestAllocation(ctx context.Context, allocID string) (sa *transaction.StorageAllocation, err error) {
 	var b []byte
-	b, err = transaction.MakeSCRestAPICall(
+	b, err = transaction.MakeSCRestAPICall(ctx,
 		transaction.STORAGE_CONTRACT_ADDRESS,
 		"/allocation",
 		map[string]string{"allocation": allocID})
@@ -221,9 +221,9 @@ func requestAllocation(allocID string) (sa *transaction.StorageAllocation, err e
 	return
 }
 
-func requestExpiredAllocations() (allocs []string, err error) {
+func requestExpiredAllocations(ctx context.Context) (allocs []string, err error) {
 	var b []byte
-	b, err = transaction.MakeSCRestAPICall(
+	b, err = transaction.MakeSCRestAPICall(ctx,
 		transaction.STORAGE_CONTRACT_ADDRESS,
 		"/expired-allocations",
 		map[string]string{"blobber_id": node.Self.ID})
@@ -13,9 +13,9 @@ import (
 )
 
 // SyncAllocation try to pull allocation from blockchain, and insert it in db.
-func SyncAllocation(allocationId string) (*Allocation, error) {
+func SyncAllocation(ctx context.Context, allocationId string) (*Allocation, error) {
 
-	sa, err := requestAllocation(allocationId)
+	sa, err := requestAllocation(ctx, allocationId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,113 @@
+// Package auditlog keeps an append-only log of the operations changing the
+// allocations and the blobber: who did what, from where and with which result.
+// Each entry is chained to the previous one by its hash, so an entry changed
+// or removed in the database breaks the chain.
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"gorm.io/datatypes"
+)
+
+// Operations recorded in the audit log.
+const (
+	OpCommit       = "commit"
+	OpRollback     = "rollback"
+	OpShareInsert  = "share_insert"
+	OpShareRevoke  = "share_revoke"
+	OpAuthTicket   = "auth_ticket"
+	OpAdmin        = "admin"
+	OpConfigReload = "config_reload"
+)
+
+// Operations lists the operations recorded in the audit log.
+var Operations = []string{OpCommit, OpRollback, OpShareInsert, OpShareRevoke, OpAuthTicket, OpAdmin, OpConfigReload}
+
+// Results of the operations.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	// ResultDenied is an operation refused for lack of authorization.
+	ResultDenied = "denied"
+)
+
+// Entry is an operation recorded in the audit log.
+// swagger:model AuditLogEntry
+type Entry struct {
+	ID           int64                       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Operation    string                      `gorm:"column:operation;size:32;not null" json:"operation"`
+	ClientID     string                      `gorm:"column:client_id;size:64" json:"client_id,omitempty"`
+	IP           string                      `gorm:"column:ip;size:64" json:"ip,omitempty"`
+	AllocationID string                      `gorm:"column:allocation_id;size:64" json:"allocation_id,omitempty"`
+	Paths        datatypes.JSONSlice[string] `gorm:"column:paths" json:"paths,omitempty"`
+	Result       string                      `gorm:"column:result;size:16;not null" json:"result"`
+	Message      string                      `gorm:"column:message" json:"message,omitempty"`
+	// WriteMarkerRoot is the allocation root of the write marker committed or
+	// rolled back to.
+	WriteMarkerRoot string           `gorm:"column:write_marker_root;size:64" json:"write_marker_root,omitempty"`
+	CreatedAt       common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+	// PrevHash is the hash of the previous entry of the log.
+	PrevHash string `gorm:"column:prev_hash;size:64;not null" json:"prev_hash"`
+	Hash     string `gorm:"column:hash;size:64;not null" json:"hash"`
+}
+
+func (Entry) TableName() string {
+	return "audit_log"
+}
+
+// computeHash is the hash of the entry chained to the previous one. The id of
+// the entry is not part of it, it is assigned by the database on insert.
+func (e *Entry) computeHash() string {
+	content, _ := json.Marshal(struct {
+		Operation       string   `json:"operation"`
+		ClientID        string   `json:"client_id"`
+		IP              string   `json:"ip"`
+		AllocationID    string   `json:"allocation_id"`
+		Paths           []string `json:"paths"`
+		Result          string   `json:"result"`
+		Message         string   `json:"message"`
+		WriteMarkerRoot string   `json:"write_marker_root"`
+		CreatedAt       int64    `json:"created_at"`
+	}{
+		e.Operation, e.ClientID, e.IP, e.AllocationID, e.Paths, e.Result,
+		e.Message, e.WriteMarkerRoot, int64(e.CreatedAt),
+	})
+	return encryption.Hash(e.PrevHash + ":" + string(content))
+}
+
+// seal chains the entry to the previous entry of the log.
+func (e *Entry) seal(prevHash string) {
+	e.PrevHash = prevHash
+	e.Hash = e.computeHash()
+}
+
+// SetError sets the result of the entry from the error of the operation.
+func (e *Entry) SetError(err error) {
+	if err != nil {
+		e.Result = ResultFailure
+		e.Message = err.Error()
+		return
+	}
+	e.Result = ResultSuccess
+}
+
+type entryKey struct{}
+
+// WithEntry returns a context carrying the entry of the operation, for the
+// operation to fill in what it affected.
+func WithEntry(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+// FromContext returns the entry of the operation of ctx. Outside of a recorded
+// operation the entry returned is discarded.
+func FromContext(ctx context.Context) *Entry {
+	if e, ok := ctx.Value(entryKey{}).(*Entry); ok {
+		return e
+	}
+	return &Entry{}
+}
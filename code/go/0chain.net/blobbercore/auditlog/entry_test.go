@@ -0,0 +1,90 @@
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func chain(n int) []*Entry {
+	var (
+		entries []*Entry
+		prev    string
+	)
+	for i := 1; i <= n; i++ {
+		e := &Entry{
+			ID:           int64(i),
+			Operation:    OpCommit,
+			ClientID:     "client",
+			AllocationID: "allocation",
+			Paths:        []string{"/a.txt", "/dir"},
+			Result:       ResultSuccess,
+			CreatedAt:    1700000000,
+		}
+		e.seal(prev)
+		prev = e.Hash
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func verify(entries []*Entry) *VerifyReport {
+	r := &VerifyReport{}
+	for _, e := range entries {
+		r.check(e)
+	}
+	return r
+}
+
+func TestVerify_Chain(t *testing.T) {
+	entries := chain(5)
+	r := verify(entries)
+	require.Empty(t, r.Breaks)
+	require.Equal(t, 5, r.EntriesChecked)
+	require.Equal(t, int64(1), r.FirstID)
+	require.Equal(t, int64(5), r.LastID)
+	require.Equal(t, entries[4].Hash, r.HeadHash)
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	entries := chain(5)
+	entries[2].Paths = []string{"/other.txt"}
+
+	r := verify(entries)
+	require.Len(t, r.Breaks, 1)
+	require.Equal(t, int64(3), r.Breaks[0].ID)
+	require.Equal(t, BreakHash, r.Breaks[0].Kind)
+
+	// rehashing the entry moves the break to the next one
+	entries[2].seal(entries[2].PrevHash)
+	r = verify(entries)
+	require.Len(t, r.Breaks, 1)
+	require.Equal(t, int64(4), r.Breaks[0].ID)
+	require.Equal(t, BreakChain, r.Breaks[0].Kind)
+}
+
+func TestVerify_Removed(t *testing.T) {
+	entries := chain(5)
+	entries = append(entries[:1], entries[2:]...)
+
+	r := verify(entries)
+	require.Len(t, r.Breaks, 1)
+	require.Equal(t, int64(3), r.Breaks[0].ID)
+	require.Equal(t, BreakChain, r.Breaks[0].Kind)
+}
+
+func TestEntry_Context(t *testing.T) {
+	e := &Entry{Operation: OpRollback}
+	ctx := WithEntry(context.Background(), e)
+	FromContext(ctx).WriteMarkerRoot = "root"
+	require.Equal(t, "root", e.WriteMarkerRoot)
+
+	// outside of a recorded operation the entry is discarded
+	FromContext(context.Background()).WriteMarkerRoot = "other"
+
+	e.SetError(errors.New("invalid write marker"))
+	require.Equal(t, ResultFailure, e.Result)
+	require.Equal(t, "invalid write marker", e.Message)
+}
@@ -0,0 +1,346 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// advisoryLockKey is the key of the postgres advisory lock serializing the
+// appends of the blobber and of the admin commands run next to it.
+const advisoryLockKey = 0x61756469746c6f67 // "auditlog"
+
+// batchSize is the number of entries read at once by Verify and Export.
+const batchSize = 1000
+
+// Formats of the export of the log.
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// queueSize is the number of entries waiting to be appended. The entries
+// recorded while the queue is full are dropped, rather than holding up the
+// operations recorded.
+const queueSize = 1024
+
+var errQueueFull = errors.New("audit log queue is full")
+
+var (
+	queue     chan *Entry
+	done      chan struct{}
+	startOnce sync.Once
+	closeOnce sync.Once
+	// closeMu keeps Record from queuing entries once the queue is closed.
+	closeMu sync.RWMutex
+	closed  bool
+)
+
+func start() {
+	startOnce.Do(func() {
+		queue = make(chan *Entry, queueSize)
+		done = make(chan struct{})
+		go appendLoop()
+	})
+}
+
+// Record queues the entry to be appended to the log, out of the request path.
+// A failure to record the entry is logged and counted in the metrics, and does
+// not fail the operation recorded.
+func Record(ctx context.Context, e *Entry) {
+	if !prepare(e) {
+		return
+	}
+	start()
+
+	closeMu.RLock()
+	defer closeMu.RUnlock()
+	if closed {
+		appendSync(ctx, e)
+		return
+	}
+	select {
+	case queue <- e:
+	default:
+		failures.WithLabelValues(failureQueueFull).Inc()
+		logError(errQueueFull, e)
+	}
+}
+
+// RecordSync appends the entry to the log before returning, for the admin and
+// the destructive operations, and for the commands exiting right after the
+// operation recorded.
+func RecordSync(ctx context.Context, e *Entry) {
+	if !prepare(e) {
+		return
+	}
+	appendSync(ctx, e)
+}
+
+// Close appends the entries queued and waits for them to be in the log, before
+// the store is closed on shutdown. The entries recorded afterwards are appended
+// synchronously.
+func Close() {
+	start()
+	closeOnce.Do(func() {
+		closeMu.Lock()
+		closed = true
+		close(queue)
+		closeMu.Unlock()
+	})
+	<-done
+}
+
+func appendSync(ctx context.Context, e *Entry) {
+	if err := appendEntries(ctx, []*Entry{e}); err != nil {
+		failures.WithLabelValues(failureAppend).Inc()
+		logError(err, e)
+	}
+}
+
+func prepare(e *Entry) bool {
+	if !config.Configuration.AuditLogEnabled {
+		return false
+	}
+	if e.CreatedAt == 0 {
+		e.CreatedAt = common.Now()
+	}
+	if e.Result == "" {
+		e.Result = ResultSuccess
+	}
+	return true
+}
+
+func logError(err error, e *Entry) {
+	logging.Logger.Error("[auditlog]record",
+		zap.String("operation", e.Operation),
+		zap.String("allocation_id", e.AllocationID),
+		zap.Error(err))
+}
+
+// appendLoop appends the queued entries, all those waiting at once in a single
+// transaction, until the queue is closed.
+func appendLoop() {
+	defer close(done)
+	for e := range queue {
+		entries := []*Entry{e}
+	drain:
+		for len(entries) < batchSize {
+			select {
+			case e, ok := <-queue:
+				if !ok {
+					break drain
+				}
+				entries = append(entries, e)
+			default:
+				break drain
+			}
+		}
+		if err := appendEntries(context.Background(), entries); err != nil {
+			failures.WithLabelValues(failureAppend).Add(float64(len(entries)))
+			for _, e := range entries {
+				logError(err, e)
+			}
+		}
+	}
+}
+
+func appendEntries(ctx context.Context, entries []*Entry) error {
+	return datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", advisoryLockKey).Error; err != nil {
+			return err
+		}
+		var last Entry
+		err := tx.Select("hash").Order("id desc").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		prevHash := last.Hash
+		for _, e := range entries {
+			e.seal(prevHash)
+			prevHash = e.Hash
+		}
+		return tx.Create(entries).Error
+	}, datastore.WithParent(ctx))
+}
+
+// Filter selects the entries of the log.
+type Filter struct {
+	AllocationID string
+	ClientID     string
+	Operation    string
+	// From and To bound the time of the entries, when not zero.
+	From common.Timestamp
+	To   common.Timestamp
+}
+
+func (f *Filter) apply(query *gorm.DB) *gorm.DB {
+	if f.AllocationID != "" {
+		query = query.Where("allocation_id = ?", f.AllocationID)
+	}
+	if f.ClientID != "" {
+		query = query.Where("client_id = ?", f.ClientID)
+	}
+	if f.Operation != "" {
+		query = query.Where("operation = ?", f.Operation)
+	}
+	if f.From > 0 {
+		query = query.Where("created_at >= ?", f.From)
+	}
+	if f.To > 0 {
+		query = query.Where("created_at <= ?", f.To)
+	}
+	return query
+}
+
+// GetEntries returns a page of the entries of the log selected by the filter,
+// in the order they were recorded.
+func GetEntries(f Filter, limit common.Pagination) ([]*Entry, error) {
+	var entries []*Entry
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return f.apply(tx.Model(&Entry{})).Limit(limit.Limit).Offset(limit.Offset).Order(clause.OrderByColumn{
+			Column: clause.Column{Name: "id"},
+			Desc:   limit.IsDescending,
+		}).Find(&entries).Error
+	})
+	if err != nil {
+		return nil, common.NewError("audit_log", err.Error())
+	}
+	return entries, nil
+}
+
+// iterate calls f on the entries of the log selected by the filter, in the
+// order they were recorded, reading them in batches.
+func iterate(filter Filter, f func(e *Entry) error) error {
+	var lastID int64
+	for {
+		var entries []*Entry
+		err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+			tx := datastore.GetStore().GetTransaction(ctx)
+			return filter.apply(tx.Model(&Entry{})).Where("id > ?", lastID).
+				Order("id").Limit(batchSize).Find(&entries).Error
+		})
+		if err != nil {
+			return common.NewError("audit_log", err.Error())
+		}
+		for _, e := range entries {
+			if err := f(e); err != nil {
+				return err
+			}
+		}
+		if len(entries) < batchSize {
+			return nil
+		}
+		lastID = entries[len(entries)-1].ID
+	}
+}
+
+// Kinds of breaks of the hash chain.
+const (
+	// BreakHash is an entry changed after it was recorded.
+	BreakHash = "hash_mismatch"
+	// BreakChain is an entry whose previous entry was removed or changed.
+	BreakChain = "chain_broken"
+)
+
+// Break is an entry of the log failing the verification of the hash chain.
+type Break struct {
+	ID       int64  `json:"id"`
+	Kind     string `json:"kind"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// VerifyReport is the result of the verification of the hash chain of the log.
+// swagger:model AuditLogVerifyReport
+type VerifyReport struct {
+	EntriesChecked int      `json:"entries_checked"`
+	FirstID        int64    `json:"first_id"`
+	LastID         int64    `json:"last_id"`
+	HeadHash       string   `json:"head_hash"`
+	Breaks         []*Break `json:"breaks"`
+	OK             bool     `json:"ok"`
+}
+
+// check verifies the next entry of the log.
+func (r *VerifyReport) check(e *Entry) {
+	if r.EntriesChecked == 0 {
+		r.FirstID = e.ID
+	}
+	if e.PrevHash != r.HeadHash {
+		r.Breaks = append(r.Breaks, &Break{ID: e.ID, Kind: BreakChain, Expected: r.HeadHash, Actual: e.PrevHash})
+	}
+	if hash := e.computeHash(); hash != e.Hash {
+		r.Breaks = append(r.Breaks, &Break{ID: e.ID, Kind: BreakHash, Expected: hash, Actual: e.Hash})
+	}
+	r.EntriesChecked++
+	r.LastID = e.ID
+	r.HeadHash = e.Hash
+}
+
+// Verify walks the whole log and checks the hash chain of its entries. The
+// head hash of the report can be kept outside the blobber to detect the
+// removal of the latest entries later on.
+func Verify() (*VerifyReport, error) {
+	report := &VerifyReport{Breaks: []*Break{}}
+	err := iterate(Filter{}, func(e *Entry) error {
+		report.check(e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.OK = len(report.Breaks) == 0
+	return report, nil
+}
+
+var csvHeader = []string{
+	"id", "created_at", "operation", "client_id", "ip", "allocation_id", "paths",
+	"result", "message", "write_marker_root", "prev_hash", "hash",
+}
+
+// Export writes the entries of the log selected by the filter to w, as JSON
+// lines or CSV.
+func Export(w io.Writer, filter Filter, format string) error {
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		return iterate(filter, func(e *Entry) error {
+			return enc.Encode(e)
+		})
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		err := iterate(filter, func(e *Entry) error {
+			return cw.Write([]string{
+				strconv.FormatInt(e.ID, 10), strconv.FormatInt(int64(e.CreatedAt), 10),
+				e.Operation, e.ClientID, e.IP, e.AllocationID, strings.Join(e.Paths, ";"),
+				e.Result, e.Message, e.WriteMarkerRoot, e.PrevHash, e.Hash,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return common.NewErrorf("invalid_parameters", "unknown export format %q, use jsonl or csv", format)
+	}
+}
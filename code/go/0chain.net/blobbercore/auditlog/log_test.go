@@ -0,0 +1,35 @@
+package auditlog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectAppend(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "hash" FROM "audit_log"`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectQuery(`INSERT INTO "audit_log"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+}
+
+func TestClose_AppendsQueued(t *testing.T) {
+	config.Configuration.AuditLogEnabled = true
+	defer func() { config.Configuration.AuditLogEnabled = false }()
+
+	mock := datastore.MockTheStore(t)
+	expectAppend(mock)
+	Record(context.TODO(), &Entry{Operation: OpCommit, AllocationID: "allocation"})
+	Close()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the entries recorded once closed are appended before Record returns
+	expectAppend(mock)
+	Record(context.TODO(), &Entry{Operation: OpCommit, AllocationID: "allocation"})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,19 @@
+package auditlog
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons of the entries not appended to the log.
+const (
+	failureQueueFull = "queue_full"
+	failureAppend    = "append"
+)
+
+// failures counts the entries lost, which the hash chain of the log cannot
+// tell about.
+var failures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "blobber_audit_log_failures_total",
+	Help: "Entries of the audit log not appended, by reason.",
+}, []string{"reason"})
@@ -28,7 +28,7 @@ var file_blobber_service_proto_rawDesc = []byte{
 	0x1a, 0x16, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
 	0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xcf, 0x0e, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x62, 0x62,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xf0, 0x19, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x62, 0x62,
 	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x66, 0x0a, 0x0d, 0x47, 0x65, 0x74,
 	0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x62, 0x6c, 0x6f,
 	0x62, 0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
@@ -41,9 +41,9 @@ var file_blobber_service_proto_rawDesc = []byte{
 	0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e,
 	0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x44, 0x61, 0x74, 0x61, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22,
-	0x1a, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x2f, 0x7b,
-	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x73,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x3a,
+	0x01, 0x2a, 0x22, 0x1a, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x6d, 0x65, 0x74,
+	0x61, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x73,
 	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1c,
 	0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65,
 	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62,
@@ -101,14 +101,14 @@ var file_blobber_service_proto_rawDesc = []byte{
 	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c,
 	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62,
 	0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x67, 0x22, 0x1c, 0x2f,
-	0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x7b,
-	0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x5a, 0x21,
-	0x1a, 0x1c, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01,
-	0x2a, 0x5a, 0x21, 0x2a, 0x1c, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x75, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x68, 0x0a, 0x06, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x16,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x6d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x67, 0x3a, 0x01, 0x2a,
+	0x5a, 0x21, 0x1a, 0x1c, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d,
+	0x3a, 0x01, 0x2a, 0x5a, 0x21, 0x3a, 0x01, 0x2a, 0x2a, 0x1c, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69,
+	0x6c, 0x65, 0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x22, 0x1c, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65,
+	0x2f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x68, 0x0a, 0x06, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x16,
 	0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72,
 	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
@@ -137,51 +137,165 @@ var file_blobber_service_proto_rawDesc = []byte{
 	0x65, 0x72, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22, 0x1a, 0x2f,
 	0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x63, 0x6f, 0x70, 0x79, 0x2f, 0x7b, 0x61, 0x6c,
-	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x7a, 0x0a, 0x0c,
-	0x43, 0x6f, 0x6c, 0x6c, 0x61, 0x62, 0x6f, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x2e, 0x62,
-	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x61, 0x62, 0x6f, 0x72, 0x61,
-	0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x62, 0x6c, 0x6f,
-	0x62, 0x62, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x61, 0x62, 0x6f, 0x72, 0x61, 0x74, 0x6f,
-	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d, 0x82, 0xd3, 0xe4, 0x93, 0x02,
-	0x27, 0x22, 0x22, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x63, 0x6f, 0x6c, 0x6c,
-	0x61, 0x62, 0x6f, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x42, 0x2c, 0x5a, 0x2a, 0x63, 0x6f, 0x64, 0x65,
-	0x2f, 0x67, 0x6f, 0x2f, 0x30, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x6e, 0x65, 0x74, 0x2f, 0x62,
-	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x62, 0x6c, 0x6f, 0x62, 0x62,
-	0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x70, 0x0a, 0x08,
+	0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62,
+	0x65, 0x72, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x52, 0x6f, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2f, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x29, 0x3a, 0x01, 0x2a, 0x22, 0x24, 0x2f, 0x76, 0x32, 0x2f, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x6c,
+	0x0a, 0x0a, 0x4d, 0x6f, 0x76, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1a, 0x2e, 0x62,
+	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62,
+	0x65, 0x72, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x3a, 0x01, 0x2a,
+	0x22, 0x1a, 0x2f, 0x76, 0x32, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x6d, 0x6f, 0x76, 0x65, 0x2f,
+	0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x6e, 0x0a, 0x0a,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x2e, 0x62, 0x6c, 0x6f,
+	0x62, 0x62, 0x65, 0x72, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x27, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x21, 0x2a, 0x1c, 0x2f, 0x76, 0x32,
+	0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x2f, 0x7b, 0x61, 0x6c,
+	0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x63, 0x0a, 0x09,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x12, 0x19, 0x2e, 0x62, 0x6c, 0x6f, 0x62,
+	0x62, 0x65, 0x72, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a, 0x22, 0x14, 0x2f, 0x76, 0x32,
+	0x2f, 0x64, 0x69, 0x72, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x7d, 0x12, 0x82, 0x01, 0x0a, 0x0f, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x65,
+	0x72, 0x4c, 0x6f, 0x63, 0x6b, 0x12, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e,
+	0x57, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72,
+	0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x4c, 0x6f, 0x63, 0x6b,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x26,
+	0x22, 0x21, 0x2f, 0x76, 0x32, 0x2f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x65,
+	0x72, 0x2f, 0x6c, 0x6f, 0x63, 0x6b, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x95, 0x01, 0x0a, 0x11, 0x57, 0x72, 0x69, 0x74, 0x65,
+	0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x21, 0x2e, 0x62,
+	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4d, 0x61, 0x72, 0x6b,
+	0x65, 0x72, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x4d,
+	0x61, 0x72, 0x6b, 0x65, 0x72, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x39, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x33, 0x2a, 0x31, 0x2f, 0x76, 0x32,
+	0x2f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x2f, 0x6c, 0x6f, 0x63,
+	0x6b, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x2f, 0x7b,
+	0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x7d, 0x12, 0x87,
+	0x01, 0x0a, 0x0f, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x49, 0x6e, 0x73,
+	0x65, 0x72, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x49, 0x6e,
+	0x73, 0x65, 0x72, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x31, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2b, 0x22, 0x26, 0x2f,
+	0x76, 0x32, 0x2f, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x2f, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x69, 0x6e, 0x66, 0x6f, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x01, 0x2a, 0x12, 0x87, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x53, 0x68, 0x61, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1f, 0x2e, 0x62,
+	0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e,
+	0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x31, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2b, 0x2a, 0x26, 0x2f, 0x76, 0x32, 0x2f, 0x6d, 0x61, 0x72,
+	0x6b, 0x65, 0x74, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x69, 0x6e,
+	0x66, 0x6f, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x3a,
+	0x01, 0x2a, 0x12, 0x72, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x79, 0x6c, 0x69, 0x73,
+	0x74, 0x12, 0x1b, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50,
+	0x6c, 0x61, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x79,
+	0x6c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x28, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x22, 0x12, 0x20, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6c, 0x61, 0x79, 0x6c, 0x69,
+	0x73, 0x74, 0x2f, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x7c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61,
+	0x79, 0x6c, 0x69, 0x73, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x62,
+	0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x62, 0x6c, 0x6f,
+	0x62, 0x62, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x61, 0x79, 0x6c, 0x69, 0x73, 0x74,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x26, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x20, 0x12, 0x1e, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x6c, 0x61, 0x79, 0x6c, 0x69,
+	0x73, 0x74, 0x2f, 0x66, 0x69, 0x6c, 0x65, 0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x86, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x52,
+	0x65, 0x61, 0x64, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x62,
+	0x62, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x52, 0x65, 0x61, 0x64, 0x4d, 0x61,
+	0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x62, 0x6c,
+	0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x52, 0x65, 0x61, 0x64,
+	0x4d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x2d,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x27, 0x3a, 0x01, 0x2a, 0x22, 0x22, 0x2f, 0x76, 0x32, 0x2f, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x72, 0x65, 0x64, 0x65, 0x65, 0x6d,
+	0x2f, 0x7b, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x7d, 0x12, 0x5f, 0x0a,
+	0x12, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x22, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x44, 0x6f,
+	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65,
+	0x72, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x59,
+	0x0a, 0x10, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x12, 0x20, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x63, 0x6f, 0x64,
+	0x65, 0x2f, 0x67, 0x6f, 0x2f, 0x30, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x2e, 0x6e, 0x65, 0x74, 0x2f,
+	0x62, 0x6c, 0x6f, 0x62, 0x62, 0x65, 0x72, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x62, 0x6c, 0x6f, 0x62,
+	0x62, 0x65, 0x72, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_blobber_service_proto_goTypes = []interface{}{
-	(*GetAllocationRequest)(nil),     // 0: blobber.GetAllocationRequest
-	(*GetFileMetaDataRequest)(nil),   // 1: blobber.GetFileMetaDataRequest
-	(*GetFileStatsRequest)(nil),      // 2: blobber.GetFileStatsRequest
-	(*ListEntitiesRequest)(nil),      // 3: blobber.ListEntitiesRequest
-	(*GetObjectPathRequest)(nil),     // 4: blobber.GetObjectPathRequest
-	(*GetReferencePathRequest)(nil),  // 5: blobber.GetReferencePathRequest
-	(*GetObjectTreeRequest)(nil),     // 6: blobber.GetObjectTreeRequest
-	(*DownloadFileRequest)(nil),      // 7: blobber.DownloadFileRequest
-	(*RenameObjectRequest)(nil),      // 8: blobber.RenameObjectRequest
-	(*UploadFileRequest)(nil),        // 9: blobber.UploadFileRequest
-	(*CommitRequest)(nil),            // 10: blobber.CommitRequest
-	(*CalculateHashRequest)(nil),     // 11: blobber.CalculateHashRequest
-	(*CommitMetaTxnRequest)(nil),     // 12: blobber.CommitMetaTxnRequest
-	(*CopyObjectRequest)(nil),        // 13: blobber.CopyObjectRequest
-	(*CollaboratorRequest)(nil),      // 14: blobber.CollaboratorRequest
-	(*GetAllocationResponse)(nil),    // 15: blobber.GetAllocationResponse
-	(*GetFileMetaDataResponse)(nil),  // 16: blobber.GetFileMetaDataResponse
-	(*GetFileStatsResponse)(nil),     // 17: blobber.GetFileStatsResponse
-	(*ListEntitiesResponse)(nil),     // 18: blobber.ListEntitiesResponse
-	(*GetObjectPathResponse)(nil),    // 19: blobber.GetObjectPathResponse
-	(*GetReferencePathResponse)(nil), // 20: blobber.GetReferencePathResponse
-	(*GetObjectTreeResponse)(nil),    // 21: blobber.GetObjectTreeResponse
-	(*DownloadFileResponse)(nil),     // 22: blobber.DownloadFileResponse
-	(*RenameObjectResponse)(nil),     // 23: blobber.RenameObjectResponse
-	(*UploadFileResponse)(nil),       // 24: blobber.UploadFileResponse
-	(*CommitResponse)(nil),           // 25: blobber.CommitResponse
-	(*CalculateHashResponse)(nil),    // 26: blobber.CalculateHashResponse
-	(*CommitMetaTxnResponse)(nil),    // 27: blobber.CommitMetaTxnResponse
-	(*CopyObjectResponse)(nil),       // 28: blobber.CopyObjectResponse
-	(*CollaboratorResponse)(nil),     // 29: blobber.CollaboratorResponse
+	(*GetAllocationRequest)(nil),       // 0: blobber.GetAllocationRequest
+	(*GetFileMetaDataRequest)(nil),     // 1: blobber.GetFileMetaDataRequest
+	(*GetFileStatsRequest)(nil),        // 2: blobber.GetFileStatsRequest
+	(*ListEntitiesRequest)(nil),        // 3: blobber.ListEntitiesRequest
+	(*GetObjectPathRequest)(nil),       // 4: blobber.GetObjectPathRequest
+	(*GetReferencePathRequest)(nil),    // 5: blobber.GetReferencePathRequest
+	(*GetObjectTreeRequest)(nil),       // 6: blobber.GetObjectTreeRequest
+	(*DownloadFileRequest)(nil),        // 7: blobber.DownloadFileRequest
+	(*RenameObjectRequest)(nil),        // 8: blobber.RenameObjectRequest
+	(*UploadFileRequest)(nil),          // 9: blobber.UploadFileRequest
+	(*CommitRequest)(nil),              // 10: blobber.CommitRequest
+	(*CalculateHashRequest)(nil),       // 11: blobber.CalculateHashRequest
+	(*CommitMetaTxnRequest)(nil),       // 12: blobber.CommitMetaTxnRequest
+	(*CopyObjectRequest)(nil),          // 13: blobber.CopyObjectRequest
+	(*RollbackRequest)(nil),            // 14: blobber.RollbackRequest
+	(*MoveObjectRequest)(nil),          // 15: blobber.MoveObjectRequest
+	(*DeleteFileRequest)(nil),          // 16: blobber.DeleteFileRequest
+	(*CreateDirRequest)(nil),           // 17: blobber.CreateDirRequest
+	(*WriteMarkerLockRequest)(nil),     // 18: blobber.WriteMarkerLockRequest
+	(*WriteMarkerUnlockRequest)(nil),   // 19: blobber.WriteMarkerUnlockRequest
+	(*InsertShareInfoRequest)(nil),     // 20: blobber.InsertShareInfoRequest
+	(*RevokeShareInfoRequest)(nil),     // 21: blobber.RevokeShareInfoRequest
+	(*GetPlaylistRequest)(nil),         // 22: blobber.GetPlaylistRequest
+	(*GetPlaylistFileRequest)(nil),     // 23: blobber.GetPlaylistFileRequest
+	(*RedeemReadMarkerRequest)(nil),    // 24: blobber.RedeemReadMarkerRequest
+	(*DownloadFileStreamRequest)(nil),  // 25: blobber.DownloadFileStreamRequest
+	(*UploadFileStreamRequest)(nil),    // 26: blobber.UploadFileStreamRequest
+	(*GetAllocationResponse)(nil),      // 27: blobber.GetAllocationResponse
+	(*GetFileMetaDataResponse)(nil),    // 28: blobber.GetFileMetaDataResponse
+	(*GetFileStatsResponse)(nil),       // 29: blobber.GetFileStatsResponse
+	(*ListEntitiesResponse)(nil),       // 30: blobber.ListEntitiesResponse
+	(*GetObjectPathResponse)(nil),      // 31: blobber.GetObjectPathResponse
+	(*GetReferencePathResponse)(nil),   // 32: blobber.GetReferencePathResponse
+	(*GetObjectTreeResponse)(nil),      // 33: blobber.GetObjectTreeResponse
+	(*DownloadFileResponse)(nil),       // 34: blobber.DownloadFileResponse
+	(*RenameObjectResponse)(nil),       // 35: blobber.RenameObjectResponse
+	(*UploadFileResponse)(nil),         // 36: blobber.UploadFileResponse
+	(*CommitResponse)(nil),             // 37: blobber.CommitResponse
+	(*CalculateHashResponse)(nil),      // 38: blobber.CalculateHashResponse
+	(*CommitMetaTxnResponse)(nil),      // 39: blobber.CommitMetaTxnResponse
+	(*CopyObjectResponse)(nil),         // 40: blobber.CopyObjectResponse
+	(*RollbackResponse)(nil),           // 41: blobber.RollbackResponse
+	(*MoveObjectResponse)(nil),         // 42: blobber.MoveObjectResponse
+	(*DeleteFileResponse)(nil),         // 43: blobber.DeleteFileResponse
+	(*CreateDirResponse)(nil),          // 44: blobber.CreateDirResponse
+	(*WriteMarkerLockResponse)(nil),    // 45: blobber.WriteMarkerLockResponse
+	(*WriteMarkerUnlockResponse)(nil),  // 46: blobber.WriteMarkerUnlockResponse
+	(*InsertShareInfoResponse)(nil),    // 47: blobber.InsertShareInfoResponse
+	(*RevokeShareInfoResponse)(nil),    // 48: blobber.RevokeShareInfoResponse
+	(*GetPlaylistResponse)(nil),        // 49: blobber.GetPlaylistResponse
+	(*GetPlaylistFileResponse)(nil),    // 50: blobber.GetPlaylistFileResponse
+	(*RedeemReadMarkerResponse)(nil),   // 51: blobber.RedeemReadMarkerResponse
+	(*DownloadFileStreamResponse)(nil), // 52: blobber.DownloadFileStreamResponse
+	(*UploadFileStreamResponse)(nil),   // 53: blobber.UploadFileStreamResponse
 }
 var file_blobber_service_proto_depIdxs = []int32{
 	0,  // 0: blobber.BlobberService.GetAllocation:input_type -> blobber.GetAllocationRequest
@@ -198,24 +312,48 @@ var file_blobber_service_proto_depIdxs = []int32{
 	11, // 11: blobber.BlobberService.CalculateHash:input_type -> blobber.CalculateHashRequest
 	12, // 12: blobber.BlobberService.CommitMetaTxn:input_type -> blobber.CommitMetaTxnRequest
 	13, // 13: blobber.BlobberService.CopyObject:input_type -> blobber.CopyObjectRequest
-	14, // 14: blobber.BlobberService.Collaborator:input_type -> blobber.CollaboratorRequest
-	15, // 15: blobber.BlobberService.GetAllocation:output_type -> blobber.GetAllocationResponse
-	16, // 16: blobber.BlobberService.GetFileMetaData:output_type -> blobber.GetFileMetaDataResponse
-	17, // 17: blobber.BlobberService.GetFileStats:output_type -> blobber.GetFileStatsResponse
-	18, // 18: blobber.BlobberService.ListEntities:output_type -> blobber.ListEntitiesResponse
-	19, // 19: blobber.BlobberService.GetObjectPath:output_type -> blobber.GetObjectPathResponse
-	20, // 20: blobber.BlobberService.GetReferencePath:output_type -> blobber.GetReferencePathResponse
-	21, // 21: blobber.BlobberService.GetObjectTree:output_type -> blobber.GetObjectTreeResponse
-	22, // 22: blobber.BlobberService.DownloadFile:output_type -> blobber.DownloadFileResponse
-	23, // 23: blobber.BlobberService.RenameObject:output_type -> blobber.RenameObjectResponse
-	24, // 24: blobber.BlobberService.UploadFile:output_type -> blobber.UploadFileResponse
-	25, // 25: blobber.BlobberService.Commit:output_type -> blobber.CommitResponse
-	26, // 26: blobber.BlobberService.CalculateHash:output_type -> blobber.CalculateHashResponse
-	27, // 27: blobber.BlobberService.CommitMetaTxn:output_type -> blobber.CommitMetaTxnResponse
-	28, // 28: blobber.BlobberService.CopyObject:output_type -> blobber.CopyObjectResponse
-	29, // 29: blobber.BlobberService.Collaborator:output_type -> blobber.CollaboratorResponse
-	15, // [15:30] is the sub-list for method output_type
-	0,  // [0:15] is the sub-list for method input_type
+	14, // 14: blobber.BlobberService.Rollback:input_type -> blobber.RollbackRequest
+	15, // 15: blobber.BlobberService.MoveObject:input_type -> blobber.MoveObjectRequest
+	16, // 16: blobber.BlobberService.DeleteFile:input_type -> blobber.DeleteFileRequest
+	17, // 17: blobber.BlobberService.CreateDir:input_type -> blobber.CreateDirRequest
+	18, // 18: blobber.BlobberService.WriteMarkerLock:input_type -> blobber.WriteMarkerLockRequest
+	19, // 19: blobber.BlobberService.WriteMarkerUnlock:input_type -> blobber.WriteMarkerUnlockRequest
+	20, // 20: blobber.BlobberService.InsertShareInfo:input_type -> blobber.InsertShareInfoRequest
+	21, // 21: blobber.BlobberService.RevokeShareInfo:input_type -> blobber.RevokeShareInfoRequest
+	22, // 22: blobber.BlobberService.GetPlaylist:input_type -> blobber.GetPlaylistRequest
+	23, // 23: blobber.BlobberService.GetPlaylistFile:input_type -> blobber.GetPlaylistFileRequest
+	24, // 24: blobber.BlobberService.RedeemReadMarker:input_type -> blobber.RedeemReadMarkerRequest
+	25, // 25: blobber.BlobberService.DownloadFileStream:input_type -> blobber.DownloadFileStreamRequest
+	26, // 26: blobber.BlobberService.UploadFileStream:input_type -> blobber.UploadFileStreamRequest
+	27, // 27: blobber.BlobberService.GetAllocation:output_type -> blobber.GetAllocationResponse
+	28, // 28: blobber.BlobberService.GetFileMetaData:output_type -> blobber.GetFileMetaDataResponse
+	29, // 29: blobber.BlobberService.GetFileStats:output_type -> blobber.GetFileStatsResponse
+	30, // 30: blobber.BlobberService.ListEntities:output_type -> blobber.ListEntitiesResponse
+	31, // 31: blobber.BlobberService.GetObjectPath:output_type -> blobber.GetObjectPathResponse
+	32, // 32: blobber.BlobberService.GetReferencePath:output_type -> blobber.GetReferencePathResponse
+	33, // 33: blobber.BlobberService.GetObjectTree:output_type -> blobber.GetObjectTreeResponse
+	34, // 34: blobber.BlobberService.DownloadFile:output_type -> blobber.DownloadFileResponse
+	35, // 35: blobber.BlobberService.RenameObject:output_type -> blobber.RenameObjectResponse
+	36, // 36: blobber.BlobberService.UploadFile:output_type -> blobber.UploadFileResponse
+	37, // 37: blobber.BlobberService.Commit:output_type -> blobber.CommitResponse
+	38, // 38: blobber.BlobberService.CalculateHash:output_type -> blobber.CalculateHashResponse
+	39, // 39: blobber.BlobberService.CommitMetaTxn:output_type -> blobber.CommitMetaTxnResponse
+	40, // 40: blobber.BlobberService.CopyObject:output_type -> blobber.CopyObjectResponse
+	41, // 41: blobber.BlobberService.Rollback:output_type -> blobber.RollbackResponse
+	42, // 42: blobber.BlobberService.MoveObject:output_type -> blobber.MoveObjectResponse
+	43, // 43: blobber.BlobberService.DeleteFile:output_type -> blobber.DeleteFileResponse
+	44, // 44: blobber.BlobberService.CreateDir:output_type -> blobber.CreateDirResponse
+	45, // 45: blobber.BlobberService.WriteMarkerLock:output_type -> blobber.WriteMarkerLockResponse
+	46, // 46: blobber.BlobberService.WriteMarkerUnlock:output_type -> blobber.WriteMarkerUnlockResponse
+	47, // 47: blobber.BlobberService.InsertShareInfo:output_type -> blobber.InsertShareInfoResponse
+	48, // 48: blobber.BlobberService.RevokeShareInfo:output_type -> blobber.RevokeShareInfoResponse
+	49, // 49: blobber.BlobberService.GetPlaylist:output_type -> blobber.GetPlaylistResponse
+	50, // 50: blobber.BlobberService.GetPlaylistFile:output_type -> blobber.GetPlaylistFileResponse
+	51, // 51: blobber.BlobberService.RedeemReadMarker:output_type -> blobber.RedeemReadMarkerResponse
+	52, // 52: blobber.BlobberService.DownloadFileStream:output_type -> blobber.DownloadFileStreamResponse
+	53, // 53: blobber.BlobberService.UploadFileStream:output_type -> blobber.UploadFileStreamResponse
+	27, // [27:54] is the sub-list for method output_type
+	0,  // [0:27] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -36,7 +36,20 @@ type BlobberServiceClient interface {
 	CalculateHash(ctx context.Context, in *CalculateHashRequest, opts ...grpc.CallOption) (*CalculateHashResponse, error)
 	CommitMetaTxn(ctx context.Context, in *CommitMetaTxnRequest, opts ...grpc.CallOption) (*CommitMetaTxnResponse, error)
 	CopyObject(ctx context.Context, in *CopyObjectRequest, opts ...grpc.CallOption) (*CopyObjectResponse, error)
-	Collaborator(ctx context.Context, in *CollaboratorRequest, opts ...grpc.CallOption) (*CollaboratorResponse, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error)
+	MoveObject(ctx context.Context, in *MoveObjectRequest, opts ...grpc.CallOption) (*MoveObjectResponse, error)
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error)
+	CreateDir(ctx context.Context, in *CreateDirRequest, opts ...grpc.CallOption) (*CreateDirResponse, error)
+	WriteMarkerLock(ctx context.Context, in *WriteMarkerLockRequest, opts ...grpc.CallOption) (*WriteMarkerLockResponse, error)
+	WriteMarkerUnlock(ctx context.Context, in *WriteMarkerUnlockRequest, opts ...grpc.CallOption) (*WriteMarkerUnlockResponse, error)
+	InsertShareInfo(ctx context.Context, in *InsertShareInfoRequest, opts ...grpc.CallOption) (*InsertShareInfoResponse, error)
+	RevokeShareInfo(ctx context.Context, in *RevokeShareInfoRequest, opts ...grpc.CallOption) (*RevokeShareInfoResponse, error)
+	GetPlaylist(ctx context.Context, in *GetPlaylistRequest, opts ...grpc.CallOption) (*GetPlaylistResponse, error)
+	GetPlaylistFile(ctx context.Context, in *GetPlaylistFileRequest, opts ...grpc.CallOption) (*GetPlaylistFileResponse, error)
+	RedeemReadMarker(ctx context.Context, in *RedeemReadMarkerRequest, opts ...grpc.CallOption) (*RedeemReadMarkerResponse, error)
+	// Streaming RPCs are served over gRPC only.
+	DownloadFileStream(ctx context.Context, in *DownloadFileStreamRequest, opts ...grpc.CallOption) (BlobberService_DownloadFileStreamClient, error)
+	UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (BlobberService_UploadFileStreamClient, error)
 }
 
 type blobberServiceClient struct {
@@ -173,17 +186,173 @@ func (c *blobberServiceClient) CopyObject(ctx context.Context, in *CopyObjectReq
 	return out, nil
 }
 
-func (c *blobberServiceClient) Collaborator(ctx context.Context, in *CollaboratorRequest, opts ...grpc.CallOption) (*CollaboratorResponse, error) {
-	out := new(CollaboratorResponse)
-	err := c.cc.Invoke(ctx, "/blobber.BlobberService/Collaborator", in, out, opts...)
+func (c *blobberServiceClient) Rollback(ctx context.Context, in *RollbackRequest, opts ...grpc.CallOption) (*RollbackResponse, error) {
+	out := new(RollbackResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/Rollback", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *blobberServiceClient) MoveObject(ctx context.Context, in *MoveObjectRequest, opts ...grpc.CallOption) (*MoveObjectResponse, error) {
+	out := new(MoveObjectResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/MoveObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error) {
+	out := new(DeleteFileResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) CreateDir(ctx context.Context, in *CreateDirRequest, opts ...grpc.CallOption) (*CreateDirResponse, error) {
+	out := new(CreateDirResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/CreateDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) WriteMarkerLock(ctx context.Context, in *WriteMarkerLockRequest, opts ...grpc.CallOption) (*WriteMarkerLockResponse, error) {
+	out := new(WriteMarkerLockResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/WriteMarkerLock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) WriteMarkerUnlock(ctx context.Context, in *WriteMarkerUnlockRequest, opts ...grpc.CallOption) (*WriteMarkerUnlockResponse, error) {
+	out := new(WriteMarkerUnlockResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/WriteMarkerUnlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) InsertShareInfo(ctx context.Context, in *InsertShareInfoRequest, opts ...grpc.CallOption) (*InsertShareInfoResponse, error) {
+	out := new(InsertShareInfoResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/InsertShareInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) RevokeShareInfo(ctx context.Context, in *RevokeShareInfoRequest, opts ...grpc.CallOption) (*RevokeShareInfoResponse, error) {
+	out := new(RevokeShareInfoResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/RevokeShareInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) GetPlaylist(ctx context.Context, in *GetPlaylistRequest, opts ...grpc.CallOption) (*GetPlaylistResponse, error) {
+	out := new(GetPlaylistResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/GetPlaylist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) GetPlaylistFile(ctx context.Context, in *GetPlaylistFileRequest, opts ...grpc.CallOption) (*GetPlaylistFileResponse, error) {
+	out := new(GetPlaylistFileResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/GetPlaylistFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) RedeemReadMarker(ctx context.Context, in *RedeemReadMarkerRequest, opts ...grpc.CallOption) (*RedeemReadMarkerResponse, error) {
+	out := new(RedeemReadMarkerResponse)
+	err := c.cc.Invoke(ctx, "/blobber.BlobberService/RedeemReadMarker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blobberServiceClient) DownloadFileStream(ctx context.Context, in *DownloadFileStreamRequest, opts ...grpc.CallOption) (BlobberService_DownloadFileStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlobberService_ServiceDesc.Streams[0], "/blobber.BlobberService/DownloadFileStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobberServiceDownloadFileStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlobberService_DownloadFileStreamClient interface {
+	Recv() (*DownloadFileStreamResponse, error)
+	grpc.ClientStream
+}
+
+type blobberServiceDownloadFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobberServiceDownloadFileStreamClient) Recv() (*DownloadFileStreamResponse, error) {
+	m := new(DownloadFileStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blobberServiceClient) UploadFileStream(ctx context.Context, opts ...grpc.CallOption) (BlobberService_UploadFileStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlobberService_ServiceDesc.Streams[1], "/blobber.BlobberService/UploadFileStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blobberServiceUploadFileStreamClient{stream}
+	return x, nil
+}
+
+type BlobberService_UploadFileStreamClient interface {
+	Send(*UploadFileStreamRequest) error
+	CloseAndRecv() (*UploadFileStreamResponse, error)
+	grpc.ClientStream
+}
+
+type blobberServiceUploadFileStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *blobberServiceUploadFileStreamClient) Send(m *UploadFileStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *blobberServiceUploadFileStreamClient) CloseAndRecv() (*UploadFileStreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadFileStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BlobberServiceServer is the server API for BlobberService service.
-// All implementations must embed UnimplementedBlobberServiceServer
+// All implementations should embed UnimplementedBlobberServiceServer
 // for forward compatibility
 type BlobberServiceServer interface {
 	GetAllocation(context.Context, *GetAllocationRequest) (*GetAllocationResponse, error)
@@ -200,11 +369,23 @@ type BlobberServiceServer interface {
 	CalculateHash(context.Context, *CalculateHashRequest) (*CalculateHashResponse, error)
 	CommitMetaTxn(context.Context, *CommitMetaTxnRequest) (*CommitMetaTxnResponse, error)
 	CopyObject(context.Context, *CopyObjectRequest) (*CopyObjectResponse, error)
-	Collaborator(context.Context, *CollaboratorRequest) (*CollaboratorResponse, error)
-	mustEmbedUnimplementedBlobberServiceServer()
+	Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error)
+	MoveObject(context.Context, *MoveObjectRequest) (*MoveObjectResponse, error)
+	DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error)
+	CreateDir(context.Context, *CreateDirRequest) (*CreateDirResponse, error)
+	WriteMarkerLock(context.Context, *WriteMarkerLockRequest) (*WriteMarkerLockResponse, error)
+	WriteMarkerUnlock(context.Context, *WriteMarkerUnlockRequest) (*WriteMarkerUnlockResponse, error)
+	InsertShareInfo(context.Context, *InsertShareInfoRequest) (*InsertShareInfoResponse, error)
+	RevokeShareInfo(context.Context, *RevokeShareInfoRequest) (*RevokeShareInfoResponse, error)
+	GetPlaylist(context.Context, *GetPlaylistRequest) (*GetPlaylistResponse, error)
+	GetPlaylistFile(context.Context, *GetPlaylistFileRequest) (*GetPlaylistFileResponse, error)
+	RedeemReadMarker(context.Context, *RedeemReadMarkerRequest) (*RedeemReadMarkerResponse, error)
+	// Streaming RPCs are served over gRPC only.
+	DownloadFileStream(*DownloadFileStreamRequest, BlobberService_DownloadFileStreamServer) error
+	UploadFileStream(BlobberService_UploadFileStreamServer) error
 }
 
-// UnimplementedBlobberServiceServer must be embedded to have forward compatible implementations.
+// UnimplementedBlobberServiceServer should be embedded to have forward compatible implementations.
 type UnimplementedBlobberServiceServer struct {
 }
 
@@ -250,10 +431,45 @@ func (UnimplementedBlobberServiceServer) CommitMetaTxn(context.Context, *CommitM
 func (UnimplementedBlobberServiceServer) CopyObject(context.Context, *CopyObjectRequest) (*CopyObjectResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CopyObject not implemented")
 }
-func (UnimplementedBlobberServiceServer) Collaborator(context.Context, *CollaboratorRequest) (*CollaboratorResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Collaborator not implemented")
+func (UnimplementedBlobberServiceServer) Rollback(context.Context, *RollbackRequest) (*RollbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rollback not implemented")
+}
+func (UnimplementedBlobberServiceServer) MoveObject(context.Context, *MoveObjectRequest) (*MoveObjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveObject not implemented")
+}
+func (UnimplementedBlobberServiceServer) DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedBlobberServiceServer) CreateDir(context.Context, *CreateDirRequest) (*CreateDirResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDir not implemented")
+}
+func (UnimplementedBlobberServiceServer) WriteMarkerLock(context.Context, *WriteMarkerLockRequest) (*WriteMarkerLockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteMarkerLock not implemented")
+}
+func (UnimplementedBlobberServiceServer) WriteMarkerUnlock(context.Context, *WriteMarkerUnlockRequest) (*WriteMarkerUnlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteMarkerUnlock not implemented")
+}
+func (UnimplementedBlobberServiceServer) InsertShareInfo(context.Context, *InsertShareInfoRequest) (*InsertShareInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertShareInfo not implemented")
+}
+func (UnimplementedBlobberServiceServer) RevokeShareInfo(context.Context, *RevokeShareInfoRequest) (*RevokeShareInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeShareInfo not implemented")
+}
+func (UnimplementedBlobberServiceServer) GetPlaylist(context.Context, *GetPlaylistRequest) (*GetPlaylistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlaylist not implemented")
+}
+func (UnimplementedBlobberServiceServer) GetPlaylistFile(context.Context, *GetPlaylistFileRequest) (*GetPlaylistFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlaylistFile not implemented")
+}
+func (UnimplementedBlobberServiceServer) RedeemReadMarker(context.Context, *RedeemReadMarkerRequest) (*RedeemReadMarkerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeemReadMarker not implemented")
+}
+func (UnimplementedBlobberServiceServer) DownloadFileStream(*DownloadFileStreamRequest, BlobberService_DownloadFileStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadFileStream not implemented")
+}
+func (UnimplementedBlobberServiceServer) UploadFileStream(BlobberService_UploadFileStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFileStream not implemented")
 }
-func (UnimplementedBlobberServiceServer) mustEmbedUnimplementedBlobberServiceServer() {}
 
 // UnsafeBlobberServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to BlobberServiceServer will
@@ -518,24 +734,251 @@ func _BlobberService_CopyObject_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BlobberService_Collaborator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CollaboratorRequest)
+func _BlobberService_Rollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BlobberServiceServer).Collaborator(ctx, in)
+		return srv.(BlobberServiceServer).Rollback(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blobber.BlobberService/Collaborator",
+		FullMethod: "/blobber.BlobberService/Rollback",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BlobberServiceServer).Collaborator(ctx, req.(*CollaboratorRequest))
+		return srv.(BlobberServiceServer).Rollback(ctx, req.(*RollbackRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlobberService_MoveObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).MoveObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/MoveObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).MoveObject(ctx, req.(*MoveObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/DeleteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_CreateDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).CreateDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/CreateDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).CreateDir(ctx, req.(*CreateDirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_WriteMarkerLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteMarkerLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).WriteMarkerLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/WriteMarkerLock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).WriteMarkerLock(ctx, req.(*WriteMarkerLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_WriteMarkerUnlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteMarkerUnlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).WriteMarkerUnlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/WriteMarkerUnlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).WriteMarkerUnlock(ctx, req.(*WriteMarkerUnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_InsertShareInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertShareInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).InsertShareInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/InsertShareInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).InsertShareInfo(ctx, req.(*InsertShareInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_RevokeShareInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeShareInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).RevokeShareInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/RevokeShareInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).RevokeShareInfo(ctx, req.(*RevokeShareInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_GetPlaylist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlaylistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).GetPlaylist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/GetPlaylist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).GetPlaylist(ctx, req.(*GetPlaylistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_GetPlaylistFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlaylistFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).GetPlaylistFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/GetPlaylistFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).GetPlaylistFile(ctx, req.(*GetPlaylistFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_RedeemReadMarker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeemReadMarkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlobberServiceServer).RedeemReadMarker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blobber.BlobberService/RedeemReadMarker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlobberServiceServer).RedeemReadMarker(ctx, req.(*RedeemReadMarkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlobberService_DownloadFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadFileStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlobberServiceServer).DownloadFileStream(m, &blobberServiceDownloadFileStreamServer{stream})
+}
+
+type BlobberService_DownloadFileStreamServer interface {
+	Send(*DownloadFileStreamResponse) error
+	grpc.ServerStream
+}
+
+type blobberServiceDownloadFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobberServiceDownloadFileStreamServer) Send(m *DownloadFileStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlobberService_UploadFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BlobberServiceServer).UploadFileStream(&blobberServiceUploadFileStreamServer{stream})
+}
+
+type BlobberService_UploadFileStreamServer interface {
+	SendAndClose(*UploadFileStreamResponse) error
+	Recv() (*UploadFileStreamRequest, error)
+	grpc.ServerStream
+}
+
+type blobberServiceUploadFileStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *blobberServiceUploadFileStreamServer) SendAndClose(m *UploadFileStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *blobberServiceUploadFileStreamServer) Recv() (*UploadFileStreamRequest, error) {
+	m := new(UploadFileStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // BlobberService_ServiceDesc is the grpc.ServiceDesc for BlobberService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -600,10 +1043,61 @@ var BlobberService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _BlobberService_CopyObject_Handler,
 		},
 		{
-			MethodName: "Collaborator",
-			Handler:    _BlobberService_Collaborator_Handler,
+			MethodName: "Rollback",
+			Handler:    _BlobberService_Rollback_Handler,
+		},
+		{
+			MethodName: "MoveObject",
+			Handler:    _BlobberService_MoveObject_Handler,
+		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _BlobberService_DeleteFile_Handler,
+		},
+		{
+			MethodName: "CreateDir",
+			Handler:    _BlobberService_CreateDir_Handler,
+		},
+		{
+			MethodName: "WriteMarkerLock",
+			Handler:    _BlobberService_WriteMarkerLock_Handler,
+		},
+		{
+			MethodName: "WriteMarkerUnlock",
+			Handler:    _BlobberService_WriteMarkerUnlock_Handler,
+		},
+		{
+			MethodName: "InsertShareInfo",
+			Handler:    _BlobberService_InsertShareInfo_Handler,
+		},
+		{
+			MethodName: "RevokeShareInfo",
+			Handler:    _BlobberService_RevokeShareInfo_Handler,
+		},
+		{
+			MethodName: "GetPlaylist",
+			Handler:    _BlobberService_GetPlaylist_Handler,
+		},
+		{
+			MethodName: "GetPlaylistFile",
+			Handler:    _BlobberService_GetPlaylistFile_Handler,
+		},
+		{
+			MethodName: "RedeemReadMarker",
+			Handler:    _BlobberService_RedeemReadMarker_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadFileStream",
+			Handler:       _BlobberService_DownloadFileStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadFileStream",
+			Handler:       _BlobberService_UploadFileStream_Handler,
+			ClientStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "blobber_service.proto",
 }
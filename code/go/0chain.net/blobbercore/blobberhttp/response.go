@@ -1,6 +1,7 @@
 package blobberhttp
 
 import (
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
@@ -62,6 +63,15 @@ type DownloadResponse struct {
 	AllocationID string                 `json:"-"`
 	Path         string                 `json:"-"`
 	LatestRM     *readmarker.ReadMarker `json:"latest_rm"`
+	// PresignedBlocks are the blocks left for the pre-signed urls of the
+	// allocation, after a read marker paying for pre-signed reads.
+	PresignedBlocks int64 `json:"presigned_blocks,omitempty"`
+}
+
+// swagger:model DownloadReceiptResponse
+type DownloadReceiptResponse struct {
+	*filestore.FileDownloadResponse
+	Receipt *readmarker.DownloadReceipt `json:"receipt"`
 }
 
 // swagger:model LatestWriteMarkerResult
@@ -56,7 +56,7 @@ func syncOpenChallenges(ctx context.Context) {
 		var challengeIDs []string
 		challenges.Challenges = make([]*ChallengeEntity, 0)
 		apiStart := time.Now()
-		retBytes, err := transaction.MakeSCRestAPICall(transaction.STORAGE_CONTRACT_ADDRESS, "/openchallenges", params)
+		retBytes, err := transaction.MakeSCRestAPICall(ctx, transaction.STORAGE_CONTRACT_ADDRESS, "/openchallenges", params)
 		if err != nil {
 			logging.Logger.Error("[challenge]open: ", zap.Error(err))
 			break
@@ -124,6 +124,11 @@ func validateOnValidators(ctx context.Context, c *ChallengeEntity) error {
 		zap.String("challenge_id", c.ChallengeID),
 		zap.Time("created", createdTime))
 
+	if c.slack() < 0 {
+		c.CancelChallenge(ctx, ErrExpiredCCT)
+		return nil
+	}
+
 	err := c.UnmarshalFields()
 	if err != nil {
 		logging.Logger.Error("[challenge]validate: ",
@@ -143,12 +148,20 @@ func validateOnValidators(ctx context.Context, c *ChallengeEntity) error {
 			zap.Any("challenge_id", c.ChallengeID),
 			zap.Time("created", createdTime),
 			zap.Error(err))
+		// the cancelled challenges have their failure recorded already
+		c.statusMutex.Lock()
+		cancelled := c.Status == Cancelled
+		c.statusMutex.Unlock()
+		if !cancelled {
+			recordChallengeFailure(c, err)
+		}
 		deleteChallenge(c.RoundCreatedAt)
 		return nil
 	}
 
 	completedValidation := time.Now()
-	if err := UpdateChallengeTimingCompleteValidation(c.ChallengeID, common.Timestamp(completedValidation.Unix())); err != nil {
+	observeStage(stageValidation, c.CreatedAt, completedValidation)
+	if err := UpdateChallengeTimingCompleteValidation(c.ChallengeID, common.Timestamp(completedValidation.Unix()), c.slack()); err != nil {
 		logging.Logger.Error("[challengetiming]validation",
 			zap.Any("challenge_id", c.ChallengeID),
 			zap.Time("created", createdTime),
@@ -169,19 +182,19 @@ func (c *ChallengeEntity) getCommitTransaction(ctx context.Context) (*transactio
 		zap.Any("challenge_id", c.ChallengeID),
 		zap.Time("created", createdTime))
 
-	currentRound := roundInfo.CurrentRound + int64(float64(roundInfo.LastRoundDiff)*(float64(time.Since(roundInfo.CurrentRoundCaptureTime).Milliseconds())/float64(GetRoundInterval.Milliseconds())))
+	round := currentRound()
 	logging.Logger.Info("[challenge]commit",
 		zap.Any("ChallengeID", c.ChallengeID),
 		zap.Any("RoundCreatedAt", c.RoundCreatedAt),
 		zap.Any("ChallengeCompletionTime", config.StorageSCConfig.ChallengeCompletionTime),
-		zap.Any("currentRound", currentRound),
+		zap.Any("currentRound", round),
 		zap.Any("roundInfo.LastRoundDiff", roundInfo.LastRoundDiff),
 		zap.Any("roundInfo.CurrentRound", roundInfo.CurrentRound),
 		zap.Any("roundInfo.CurrentRoundCaptureTime", roundInfo.CurrentRoundCaptureTime),
 		zap.Any("time.Since(roundInfo.CurrentRoundCaptureTime).Milliseconds()", time.Since(roundInfo.CurrentRoundCaptureTime).Milliseconds()),
 	)
 
-	if currentRound-c.RoundCreatedAt > config.StorageSCConfig.ChallengeCompletionTime {
+	if round-c.RoundCreatedAt > config.StorageSCConfig.ChallengeCompletionTime {
 		c.CancelChallenge(ctx, ErrExpiredCCT)
 		return nil, nil
 	}
@@ -189,9 +202,10 @@ func (c *ChallengeEntity) getCommitTransaction(ctx context.Context) (*transactio
 	txn, err := transaction.NewTransactionEntity()
 	if err != nil {
 		logging.Logger.Error("[challenge]createTxn", zap.Error(err))
-		c.CancelChallenge(ctx, err)
+		c.CancelChallenge(ctx, withFailureCategory(FailureTxnSubmission, err))
 		return nil, nil
 	}
+	txn.WithContext(ctx)
 
 	sn := &ChallengeResponse{}
 	sn.ChallengeID = c.ChallengeID
@@ -204,11 +218,12 @@ func (c *ChallengeEntity) getCommitTransaction(ctx context.Context) (*transactio
 	err = txn.ExecuteSmartContract(transaction.STORAGE_CONTRACT_ADDRESS, transaction.CHALLENGE_RESPONSE, sn, 0)
 	if err != nil {
 		logging.Logger.Info("Failed submitting challenge to the mining network", zap.String("err:", err.Error()))
-		c.CancelChallenge(ctx, err)
+		c.CancelChallenge(ctx, withFailureCategory(FailureTxnSubmission, err))
 		return nil, nil
 	}
 
-	err = UpdateChallengeTimingTxnSubmission(c.ChallengeID, txn.CreationDate)
+	observeStage(stageSubmission, c.CreatedAt, time.Now())
+	err = UpdateChallengeTimingTxnSubmission(c.ChallengeID, txn.CreationDate, c.slack())
 	if err != nil {
 		logging.Logger.Error("[challengetiming]txn_submission",
 			zap.Any("challenge_id", c.ChallengeID),
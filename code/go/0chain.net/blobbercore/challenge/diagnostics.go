@@ -0,0 +1,211 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Root causes of the failed and cancelled challenges.
+const (
+	FailureMissingRef           = "missing_ref"
+	FailureMerkleMismatch       = "merkle_mismatch"
+	FailureValidatorUnreachable = "validator_unreachable"
+	FailureTxnSubmission        = "txn_submission_failure"
+	FailureDeadlineMissed       = "deadline_missed"
+	FailureChainVerify          = "chain_verify_failure"
+	FailureInternal             = "internal_error"
+	FailureUnknown              = "unknown"
+)
+
+// FailureCategories lists the root causes in the order they are reported.
+var FailureCategories = []string{
+	FailureMissingRef,
+	FailureMerkleMismatch,
+	FailureValidatorUnreachable,
+	FailureTxnSubmission,
+	FailureDeadlineMissed,
+	FailureChainVerify,
+	FailureInternal,
+	FailureUnknown,
+}
+
+// challengeFailure is an error of a challenge with its root cause and, for
+// the challenges the validators did not agree on, the outcome of each
+// validator.
+type challengeFailure struct {
+	category   string
+	validators map[string]string
+	err        error
+}
+
+func (f *challengeFailure) Error() string {
+	return f.err.Error()
+}
+
+func (f *challengeFailure) Unwrap() error {
+	return f.err
+}
+
+func withFailureCategory(category string, err error) error {
+	return &challengeFailure{category: category, err: err}
+}
+
+// failureCategory sorts the error that failed a challenge into a root cause.
+func failureCategory(err error) string {
+	var f *challengeFailure
+	switch {
+	case errors.As(err, &f):
+		return f.category
+	case errors.Is(err, ErrExpiredCCT):
+		return FailureDeadlineMissed
+	case errors.Is(err, ErrInvalidObjectPath), errors.Is(err, gorm.ErrRecordNotFound):
+		return FailureMissingRef
+	case errors.Is(err, ErrNoValidator):
+		return FailureValidatorUnreachable
+	default:
+		return FailureUnknown
+	}
+}
+
+// lookupFailureCategory sorts an error looking up the write markers or the
+// refs of a challenge: a missing row is a missing ref, any other error is an
+// error of the blobber, not of its data.
+func lookupFailureCategory(err error) string {
+	var cerr *common.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (errors.As(err, &cerr) && cerr.Code == "write_marker_not_found") {
+		return FailureMissingRef
+	}
+	return FailureInternal
+}
+
+// FailureEvidence is what was known of the challenge when it failed.
+// swagger:model FailureEvidence
+type FailureEvidence struct {
+	Error                   string `json:"error"`
+	AllocationRoot          string `json:"allocation_root,omitempty"`
+	RespondedAllocationRoot string `json:"responded_allocation_root,omitempty"`
+	BlockNum                int64  `json:"block_num,omitempty"`
+	Path                    string `json:"path,omitempty"`
+	ValidationRoot          string `json:"validation_root,omitempty"`
+	// Validators is the error or the ticket message of each validator.
+	Validators    map[string]string `json:"validators,omitempty"`
+	NumValidators int               `json:"num_validators"`
+	CommitTxnIDs  []string          `json:"commit_txn_ids,omitempty"`
+	// Slack is the number of rounds left before the challenge expires.
+	Slack int64 `json:"slack"`
+}
+
+// FailureDiagnosis is the root cause of a failed or cancelled challenge.
+// swagger:model FailureDiagnosis
+type FailureDiagnosis struct {
+	ChallengeID  string           `gorm:"column:challenge_id;size:64;primaryKey" json:"challenge_id"`
+	AllocationID string           `gorm:"column:allocation_id;size:64;not null" json:"allocation_id"`
+	Category     string           `gorm:"column:category;size:32;not null" json:"category"`
+	Message      string           `gorm:"column:message" json:"message"`
+	Evidence     datatypes.JSON   `gorm:"column:evidence" json:"evidence"`
+	CreatedAt    common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (FailureDiagnosis) TableName() string {
+	return "challenge_failures"
+}
+
+func (cr *ChallengeEntity) failureEvidence(err error) *FailureEvidence {
+	ev := &FailureEvidence{
+		Error:                   err.Error(),
+		AllocationRoot:          cr.AllocationRoot,
+		RespondedAllocationRoot: cr.RespondedAllocationRoot,
+		BlockNum:                cr.BlockNum,
+		NumValidators:           len(cr.Validators),
+		CommitTxnIDs:            cr.LastCommitTxnIDs,
+		Slack:                   cr.slack(),
+	}
+	if cr.ObjectPath != nil && cr.ObjectPath.Meta != nil {
+		ev.Path, _ = cr.ObjectPath.Meta["path"].(string)
+		ev.ValidationRoot, _ = cr.ObjectPath.Meta["validation_root"].(string)
+	}
+	var f *challengeFailure
+	if errors.As(err, &f) {
+		ev.Validators = f.validators
+	}
+	return ev
+}
+
+// recordChallengeFailure stores the root cause of the failure of the
+// challenge with the evidence gathered. A later failure of the same challenge
+// replaces the earlier one.
+func recordChallengeFailure(cr *ChallengeEntity, err error) {
+	category := failureCategory(err)
+	evidence, merr := json.Marshal(cr.failureEvidence(err))
+	if merr != nil {
+		logging.Logger.Error("[challenge]failure:evidence", zap.String("challenge_id", cr.ChallengeID), zap.Error(merr))
+	}
+
+	f := &FailureDiagnosis{
+		ChallengeID:  cr.ChallengeID,
+		AllocationID: cr.AllocationID,
+		Category:     category,
+		Message:      err.Error(),
+		Evidence:     datatypes.JSON(evidence),
+		CreatedAt:    common.Now(),
+	}
+	werr := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(f).Error
+	})
+	if werr != nil {
+		logging.Logger.Error("[challenge]failure:db", zap.String("challenge_id", cr.ChallengeID), zap.Error(werr))
+		return
+	}
+	logging.Logger.Info("[challenge]failure",
+		zap.String("challenge_id", cr.ChallengeID),
+		zap.String("allocation_id", cr.AllocationID),
+		zap.String("category", category),
+		zap.ByteString("evidence", evidence))
+}
+
+// GetChallengeFailures returns the latest challenge failures, optionally of
+// an allocation and of a category only.
+func GetChallengeFailures(allocationID, category string, limit common.Pagination) ([]*FailureDiagnosis, error) {
+	var failures []*FailureDiagnosis
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		query := tx.Model(&FailureDiagnosis{})
+		if allocationID != "" {
+			query = query.Where("allocation_id = ?", allocationID)
+		}
+		if category != "" {
+			query = query.Where("category = ?", category)
+		}
+		return query.Limit(limit.Limit).Offset(limit.Offset).Order(clause.OrderByColumn{
+			Column: clause.Column{Name: "created_at"},
+			Desc:   limit.IsDescending,
+		}).Find(&failures).Error
+	})
+	if err != nil {
+		return nil, common.NewError("challenge_failures", err.Error())
+	}
+	return failures, nil
+}
+
+func cleanUpChallengeFailures() {
+	retention := config.Configuration.ChallengeFailureRetention
+	if retention <= 0 {
+		return
+	}
+	_ = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		db := datastore.GetStore().GetTransaction(ctx)
+		return db.Delete(&FailureDiagnosis{}, "created_at < ?", time.Now().Add(-retention).Unix()).Error
+	})
+}
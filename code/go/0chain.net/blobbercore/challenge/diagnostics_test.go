@@ -0,0 +1,76 @@
+package challenge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFailureCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"expired", ErrExpiredCCT, FailureDeadlineMissed},
+		{"not a file", ErrInvalidObjectPath, FailureMissingRef},
+		{"ref not found", gorm.ErrRecordNotFound, FailureMissingRef},
+		{"no validators", ErrNoValidator, FailureValidatorUnreachable},
+		{"categorized", withFailureCategory(FailureTxnSubmission, errors.New("txn")), FailureTxnSubmission},
+		{"no consensus", &challengeFailure{category: FailureMerkleMismatch, err: ErrNoConsensusChallenge}, FailureMerkleMismatch},
+		{"other", common.NewError("db", "connection refused"), FailureUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, failureCategory(tt.err))
+		})
+	}
+}
+
+func TestLookupFailureCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"ref not found", gorm.ErrRecordNotFound, FailureMissingRef},
+		{"write marker not found", common.NewError("write_marker_not_found", "no start marker"), FailureMissingRef},
+		{"db error", errors.New("connection refused"), FailureInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, lookupFailureCategory(tt.err))
+		})
+	}
+}
+
+func TestFailureEvidence(t *testing.T) {
+	cr := &ChallengeEntity{
+		ChallengeID:    "c",
+		AllocationRoot: "root",
+		BlockNum:       7,
+		Validators:     []ValidationNode{{ID: "v1"}, {ID: "v2"}},
+		ObjectPath: &reference.ObjectPath{
+			Meta: map[string]interface{}{"path": "/a.txt", "validation_root": "vr"},
+		},
+	}
+	err := &challengeFailure{
+		category:   FailureMerkleMismatch,
+		validators: map[string]string{"v1": "rejected: failed: invalid proof", "v2": "passed"},
+		err:        ErrNoConsensusChallenge,
+	}
+
+	ev := cr.failureEvidence(err)
+	require.Equal(t, ErrNoConsensusChallenge.Error(), ev.Error)
+	require.Equal(t, "root", ev.AllocationRoot)
+	require.Equal(t, int64(7), ev.BlockNum)
+	require.Equal(t, "/a.txt", ev.Path)
+	require.Equal(t, "vr", ev.ValidationRoot)
+	require.Equal(t, 2, ev.NumValidators)
+	require.Equal(t, "passed", ev.Validators["v2"])
+	require.True(t, errors.Is(err, ErrNoConsensusChallenge))
+}
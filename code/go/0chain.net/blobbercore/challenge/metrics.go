@@ -0,0 +1,43 @@
+package challenge
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Stages of the challenge pipeline, as recorded in ChallengeTiming.
+const (
+	stageValidation   = "validation"
+	stageSubmission   = "submission"
+	stageVerification = "verification"
+	stageCancellation = "cancellation"
+	stageExpiration   = "expiration"
+)
+
+var (
+	stageSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blobber_challenge_stage_seconds",
+		Help:    "Time from the creation of a challenge on the chain to the end of each stage of its pipeline.",
+		Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"stage"})
+
+	proofGenerationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blobber_challenge_proof_generation_seconds",
+		Help:    "Time to generate the proof of a challenged block, by file store version.",
+		Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"version"})
+)
+
+// observeStage records the end at t of a stage of a challenge created on the
+// chain at createdAt.
+func observeStage(stage string, createdAt common.Timestamp, t time.Time) {
+	stageSeconds.WithLabelValues(stage).Observe(t.Sub(common.ToTime(createdAt)).Seconds())
+}
+
+func observeProofGeneration(version int, d time.Duration) {
+	proofGenerationSeconds.WithLabelValues(strconv.Itoa(version)).Observe(d.Seconds())
+}
@@ -0,0 +1,151 @@
+package challenge
+
+import (
+	"container/list"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+)
+
+// objectPathCache keeps the object paths of the files challenged under the
+// latest allocation root of each allocation. The object path of a file is the
+// same for all of its blocks but for the block number in the file, so the
+// files are kept by the range of allocation blocks they hold.
+type objectPathCache struct {
+	mu     sync.Mutex
+	count  int
+	allocs map[string]*list.Element
+	lru    *list.List
+}
+
+type allocObjectPaths struct {
+	allocationID string
+	root         string
+	files        []*cachedObjectPath // sorted by startBlock
+}
+
+type cachedObjectPath struct {
+	startBlock int64
+	endBlock   int64
+	objectPath *reference.ObjectPath
+}
+
+var objectPaths = &objectPathCache{
+	allocs: make(map[string]*list.Element),
+	lru:    list.New(),
+}
+
+func (c *objectPathCache) get(allocationID, root string, blockNum int64) *reference.ObjectPath {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.allocs[allocationID]
+	if !ok {
+		return nil
+	}
+	ap := el.Value.(*allocObjectPaths)
+	if ap.root != root {
+		c.remove(el)
+		return nil
+	}
+	c.lru.MoveToFront(el)
+
+	i := sort.Search(len(ap.files), func(i int) bool {
+		return ap.files[i].endBlock >= blockNum
+	})
+	if i == len(ap.files) || ap.files[i].startBlock > blockNum {
+		return nil
+	}
+
+	// the maps of the object path are shared, they are only read
+	op := *ap.files[i].objectPath
+	op.FileBlockNum = blockNum - ap.files[i].startBlock + 1
+	return &op
+}
+
+func (c *objectPathCache) add(allocationID, root string, blockNum int64, op *reference.ObjectPath) {
+	numBlocks, ok := op.Meta["num_of_blocks"].(int64)
+	if !ok || numBlocks <= 0 || op.FileBlockNum <= 0 {
+		return
+	}
+	startBlock := blockNum - op.FileBlockNum + 1
+	file := &cachedObjectPath{
+		startBlock: startBlock,
+		endBlock:   startBlock + numBlocks - 1,
+		objectPath: op,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.allocs[allocationID]
+	if ok && el.Value.(*allocObjectPaths).root != root {
+		c.remove(el)
+		ok = false
+	}
+	if !ok {
+		el = c.lru.PushFront(&allocObjectPaths{allocationID: allocationID, root: root})
+		c.allocs[allocationID] = el
+	}
+	c.lru.MoveToFront(el)
+
+	ap := el.Value.(*allocObjectPaths)
+	i := sort.Search(len(ap.files), func(i int) bool {
+		return ap.files[i].endBlock >= file.startBlock
+	})
+	if i < len(ap.files) && ap.files[i].startBlock <= file.endBlock {
+		return // already cached
+	}
+	ap.files = append(ap.files, nil)
+	copy(ap.files[i+1:], ap.files[i:])
+	ap.files[i] = file
+	c.count++
+
+	for c.count > config.Configuration.ProofCacheMaxObjectPaths && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *objectPathCache) invalidate(allocationID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.allocs[allocationID]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *objectPathCache) remove(el *list.Element) {
+	ap := c.lru.Remove(el).(*allocObjectPaths)
+	delete(c.allocs, ap.allocationID)
+	c.count -= len(ap.files)
+}
+
+// getObjectPath returns the object path of the block of the allocation, from
+// the cache if the file of the block was challenged since the last commit.
+func getObjectPath(ctx context.Context, allocationID, root string, blockNum int64) (*reference.ObjectPath, error) {
+	if !config.Configuration.ProofCacheEnabled || config.Configuration.ProofCacheMaxObjectPaths <= 0 || blockNum <= 0 {
+		return reference.GetObjectPath(ctx, allocationID, blockNum)
+	}
+
+	if op := objectPaths.get(allocationID, root, blockNum); op != nil {
+		return op, nil
+	}
+
+	op, err := reference.GetObjectPath(ctx, allocationID, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if op != nil && op.RootHash == root && op.Meta["type"] == reference.FILE {
+		objectPaths.add(allocationID, root, blockNum, op)
+	}
+	return op, nil
+}
+
+// InvalidateObjectPaths drops the object paths cached for the allocation. It
+// is called once a commit or a rollback changes the allocation root.
+func InvalidateObjectPaths(allocationID string) {
+	objectPaths.invalidate(allocationID)
+}
@@ -0,0 +1,46 @@
+package challenge
+
+import (
+	"container/list"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectPathCache(t *testing.T) {
+	config.Configuration.ProofCacheMaxObjectPaths = 2
+	c := &objectPathCache{allocs: make(map[string]*list.Element), lru: list.New()}
+
+	// blocks 11 to 20 of the allocation
+	c.add("a", "root1", 15, &reference.ObjectPath{
+		RootHash:     "root1",
+		FileBlockNum: 5,
+		Meta:         map[string]interface{}{"num_of_blocks": int64(10)},
+	})
+
+	op := c.get("a", "root1", 11)
+	require.NotNil(t, op)
+	require.EqualValues(t, 1, op.FileBlockNum)
+	op = c.get("a", "root1", 20)
+	require.NotNil(t, op)
+	require.EqualValues(t, 10, op.FileBlockNum)
+	require.Nil(t, c.get("a", "root1", 10))
+	require.Nil(t, c.get("a", "root1", 21))
+
+	// a commit changes the allocation root
+	require.Nil(t, c.get("a", "root2", 15))
+	require.Equal(t, 0, c.count)
+
+	c.add("a", "root2", 1, &reference.ObjectPath{FileBlockNum: 1, Meta: map[string]interface{}{"num_of_blocks": int64(1)}})
+	c.add("b", "root", 1, &reference.ObjectPath{FileBlockNum: 1, Meta: map[string]interface{}{"num_of_blocks": int64(1)}})
+	c.add("c", "root", 1, &reference.ObjectPath{FileBlockNum: 1, Meta: map[string]interface{}{"num_of_blocks": int64(1)}})
+	require.Equal(t, 2, c.count)
+	require.Nil(t, c.get("a", "root2", 1))
+	require.NotNil(t, c.get("c", "root", 1))
+
+	c.invalidate("c")
+	require.Nil(t, c.get("c", "root", 1))
+	require.Equal(t, 1, c.count)
+}
@@ -19,7 +19,6 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/core/lock"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
-	"github.com/0chain/blobber/code/go/0chain.net/core/util"
 	sdkUtil "github.com/0chain/gosdk/core/util"
 	"github.com/remeh/sizedwaitgroup"
 	"gorm.io/gorm"
@@ -47,6 +46,11 @@ type ChallengeResponse struct {
 
 func (cr *ChallengeEntity) CancelChallenge(ctx context.Context, errReason error) {
 	cancellation := time.Now()
+	if errors.Is(errReason, ErrExpiredCCT) {
+		observeStage(stageExpiration, cr.CreatedAt, cancellation)
+	} else {
+		observeStage(stageCancellation, cr.CreatedAt, cancellation)
+	}
 	db := datastore.GetStore().GetTransaction(ctx)
 	deleteChallenge(cr.RoundCreatedAt)
 	cr.statusMutex.Lock()
@@ -58,6 +62,7 @@ func (cr *ChallengeEntity) CancelChallenge(ctx context.Context, errReason error)
 	if err := db.Save(cr).Error; err != nil {
 		logging.Logger.Error("[challenge]cancel:db ", zap.String("challenge_id", cr.ChallengeID), zap.Error(err))
 	}
+	recordChallengeFailure(cr, errReason)
 
 	if err := UpdateChallengeTimingCancellation(cr.ChallengeID, common.Timestamp(cancellation.Unix()), errReason); err != nil {
 		logging.Logger.Error("[challengetiming]cancellation",
@@ -68,6 +73,50 @@ func (cr *ChallengeEntity) CancelChallenge(ctx context.Context, errReason error)
 	logging.Logger.Error("[challenge]canceled", zap.String("challenge_id", cr.ChallengeID), zap.Any("round_created_at", cr.RoundCreatedAt), zap.Error(errReason))
 }
 
+// challengeBlockNum picks the block of the allocation challenged by the seed.
+func challengeBlockNum(numBlocks, seed int64) int64 {
+	r := rand.New(rand.NewSource(seed))
+	return r.Int63n(numBlocks) + 1
+}
+
+// newChallengeReadInput builds the input to read the challenged block of the
+// file in the object path and its merkle proof.
+func newChallengeReadInput(allocationID string, objectPath *reference.ObjectPath, seed int64) *filestore.ChallengeReadBlockInput {
+	r := rand.New(rand.NewSource(seed))
+	blockoffset := r.Intn(sdkUtil.FixedMerkleLeaves)
+
+	fromPreCommit := true
+
+	if objectPath.Meta["is_precommit"] != nil {
+		fromPreCommit = objectPath.Meta["is_precommit"].(bool)
+		if fromPreCommit {
+			fromPreCommit = objectPath.Meta["validation_root"].(string) != objectPath.Meta["prev_validation_root"].(string)
+		}
+	} else {
+		logging.Logger.Error("is_precommit_is_nil", zap.Any("object_path", objectPath))
+	}
+
+	return &filestore.ChallengeReadBlockInput{
+		Hash:             objectPath.Meta["validation_root"].(string),
+		FileSize:         objectPath.Meta["size"].(int64),
+		BlockOffset:      blockoffset,
+		AllocationID:     allocationID,
+		IsPrecommit:      fromPreCommit,
+		FilestoreVersion: objectPath.FilestoreVersion,
+	}
+}
+
+func writeMarkersPostData(wms []*writemarker.WriteMarkerEntity) []map[string]interface{} {
+	markersArray := make([]map[string]interface{}, 0)
+	for _, wm := range wms {
+		markersMap := make(map[string]interface{})
+		markersMap["write_marker"] = wm.WM
+		markersMap["client_key"] = wm.ClientPublicKey
+		markersArray = append(markersArray, markersMap)
+	}
+	return markersArray
+}
+
 // LoadValidationTickets load validation tickets
 func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 	if len(cr.Validators) == 0 {
@@ -92,17 +141,17 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 	wms, err := writemarker.GetWriteMarkersInRange(ctx, cr.AllocationID, cr.AllocationRoot, cr.Timestamp, allocationObj.AllocationRoot)
 	if err != nil {
 		allocMu.RUnlock()
-		return err
+		return withFailureCategory(lookupFailureCategory(err), err)
 	}
 	if len(wms) == 0 {
 		allocMu.RUnlock()
-		return common.NewError("write_marker_not_found", "Could find the writemarker for the given allocation root on challenge")
+		return withFailureCategory(FailureMissingRef, common.NewError("write_marker_not_found", "Could find the writemarker for the given allocation root on challenge"))
 	}
 
 	rootRef, err := reference.GetReference(ctx, cr.AllocationID, "/")
 	if err != nil && err != gorm.ErrRecordNotFound {
 		allocMu.RUnlock()
-		cr.CancelChallenge(ctx, err)
+		cr.CancelChallenge(ctx, withFailureCategory(FailureInternal, err))
 		return err
 	}
 
@@ -113,17 +162,15 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 			logging.Logger.Error("root_mismatch", zap.Any("allocation_root", allocationObj.AllocationRoot), zap.Any("latest_write_marker", wms[len(wms)-1].WM.AllocationRoot), zap.Any("root_ref_hash", rootRef.Hash))
 		}
 		if rootRef.NumBlocks > 0 {
-			r := rand.New(rand.NewSource(cr.RandomNumber))
-			blockNum = r.Int63n(rootRef.NumBlocks)
-			blockNum++
+			blockNum = challengeBlockNum(rootRef.NumBlocks, cr.RandomNumber)
 			cr.BlockNum = blockNum
 		}
 
 		logging.Logger.Info("[challenge]rand: ", zap.Any("rootRef.NumBlocks", rootRef.NumBlocks), zap.Any("blockNum", blockNum), zap.Any("challenge_id", cr.ChallengeID), zap.Any("random_seed", cr.RandomNumber))
-		objectPath, err = reference.GetObjectPath(ctx, cr.AllocationID, blockNum)
+		objectPath, err = getObjectPath(ctx, cr.AllocationID, rootRef.Hash, blockNum)
 		if err != nil {
 			allocMu.RUnlock()
-			cr.CancelChallenge(ctx, err)
+			cr.CancelChallenge(ctx, withFailureCategory(FailureMissingRef, err))
 			return err
 		}
 		if objectPath != nil {
@@ -138,14 +185,7 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 	if objectPath != nil {
 		postData["object_path"] = objectPath
 	}
-	markersArray := make([]map[string]interface{}, 0)
-	for _, wm := range wms {
-		markersMap := make(map[string]interface{})
-		markersMap["write_marker"] = wm.WM
-		markersMap["client_key"] = wm.ClientPublicKey
-		markersArray = append(markersArray, markersMap)
-	}
-	postData["write_markers"] = markersArray
+	postData["write_markers"] = writeMarkersPostData(wms)
 
 	var proofGenTime int64 = -1
 
@@ -158,38 +198,19 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 			return ErrInvalidObjectPath
 		}
 
-		r := rand.New(rand.NewSource(cr.RandomNumber))
-		blockoffset := r.Intn(sdkUtil.FixedMerkleLeaves)
-
-		fromPreCommit := true
-
-		if objectPath.Meta["is_precommit"] != nil {
-			fromPreCommit = objectPath.Meta["is_precommit"].(bool)
-			if fromPreCommit {
-				fromPreCommit = objectPath.Meta["validation_root"].(string) != objectPath.Meta["prev_validation_root"].(string)
-			}
-		} else {
-			logging.Logger.Error("is_precommit_is_nil", zap.Any("object_path", objectPath))
-		}
-
-		challengeReadInput := &filestore.ChallengeReadBlockInput{
-			Hash:             objectPath.Meta["validation_root"].(string),
-			FileSize:         objectPath.Meta["size"].(int64),
-			BlockOffset:      blockoffset,
-			AllocationID:     cr.AllocationID,
-			IsPrecommit:      fromPreCommit,
-			FilestoreVersion: objectPath.FilestoreVersion,
-		}
+		challengeReadInput := newChallengeReadInput(cr.AllocationID, objectPath, cr.RandomNumber)
 
 		t1 := time.Now()
 		challengeResponse, err := filestore.GetFileStore().GetBlocksMerkleTreeForChallenge(challengeReadInput)
 
 		if err != nil {
 			allocMu.RUnlock()
-			cr.CancelChallenge(ctx, err)
+			cr.CancelChallenge(ctx, withFailureCategory(FailureMerkleMismatch, err))
 			return common.NewError("blockdata_not_found", err.Error())
 		}
 		proofGenTime = time.Since(t1).Milliseconds()
+		proofCost.observe(cr.AllocationID, challengeReadInput.FileSize, challengeReadInput.FilestoreVersion, time.Duration(proofGenTime)*time.Millisecond)
+		observeProofGeneration(challengeReadInput.FilestoreVersion, time.Since(t1))
 
 		if objectPath.Meta["size"] != nil {
 			logging.Logger.Info("Proof gen logs: ",
@@ -234,6 +255,10 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 		cr.ValidationTickets = make([]*ValidationTicket, len(cr.Validators))
 	}
 
+	// outcome of each validator, kept as evidence if the challenge fails
+	outcomes := make(map[string]string)
+	numRejected := 0
+
 	accessMu := sync.RWMutex{}
 	updateMapAndSlice := func(validatorID string, i int, vt *ValidationTicket) {
 		accessMu.Lock()
@@ -245,12 +270,25 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 		}
 		accessMu.Unlock()
 	}
+	setOutcome := func(validatorID, outcome string) {
+		accessMu.Lock()
+		outcomes[validatorID] = outcome
+		accessMu.Unlock()
+	}
 
 	numSuccess := 0
 	numFailed := 0
 
+	// start from a different validator for each challenge to spread the load
+	offset := 0
+	if cr.RandomNumber > 0 {
+		offset = int(cr.RandomNumber % int64(len(cr.Validators)))
+	}
+
 	swg := sizedwaitgroup.New(10)
-	for i, validator := range cr.Validators {
+	for k := range cr.Validators {
+		i := (k + offset) % len(cr.Validators)
+		validator := cr.Validators[i]
 		if cr.ValidationTickets[i] != nil {
 			exisitingVT := cr.ValidationTickets[i]
 			if exisitingVT.Signature != "" && exisitingVT.ChallengeID == cr.ChallengeID {
@@ -258,16 +296,15 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 			}
 		}
 
-		url := validator.URL + VALIDATOR_URL
-
 		swg.Add()
 		go func(url, validatorID string, i int) {
 			defer swg.Done()
 
-			resp, err := util.SendPostRequest(url, postDataBytes, nil)
+			resp, err := getValidatorClient(validatorID, url).send(ctx, cr.ChallengeID, postDataBytes)
 			if err != nil {
 				numFailed++
 				logging.Logger.Error("[challenge]post: ", zap.Any("error", err.Error()))
+				setOutcome(validatorID, "request failed: "+err.Error())
 				updateMapAndSlice(validatorID, i, nil)
 				return
 			}
@@ -282,6 +319,7 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 					zap.Any("resp", string(resp)),
 					zap.Any("error", err.Error()),
 				)
+				setOutcome(validatorID, "invalid ticket: "+err.Error())
 				updateMapAndSlice(validatorID, i, nil)
 				return
 			}
@@ -295,6 +333,7 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 					zap.Any("resp", string(resp)),
 					zap.Any("error", "Validator ID mismatch"),
 				)
+				setOutcome(validatorID, "invalid ticket: validator id mismatch")
 				updateMapAndSlice(validatorID, i, nil)
 				return
 			}
@@ -313,18 +352,24 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 					"[challenge]ticket: Validation ticket from validator could not be verified.",
 					zap.String("validator", validatorID),
 				)
+				setOutcome(validatorID, "invalid ticket: signature could not be verified")
 				updateMapAndSlice(validatorID, i, nil)
 				return
 			}
 			updateMapAndSlice(validatorID, i, &validationTicket)
 
 			if validationTicket.Result {
+				setOutcome(validatorID, "passed")
 				numSuccess++
 			} else {
+				accessMu.Lock()
+				outcomes[validatorID] = "rejected: " + validationTicket.MessageCode + ": " + validationTicket.Message
+				numRejected++
+				accessMu.Unlock()
 				numFailed++
 			}
 
-		}(url, validator.ID, i)
+		}(validator.URL, validator.ID, i)
 	}
 
 	for {
@@ -343,7 +388,18 @@ func (cr *ChallengeEntity) LoadValidationTickets(ctx context.Context) error {
 		cr.statusMutex.Unlock()
 		cr.UpdatedAt = time.Now().UTC()
 	} else {
-		cr.CancelChallenge(ctx, ErrNoConsensusChallenge)
+		// the validators that could check the proof rejected it
+		category := FailureValidatorUnreachable
+		accessMu.RLock()
+		if numRejected > 0 {
+			category = FailureMerkleMismatch
+		}
+		failure := &challengeFailure{category: category, validators: make(map[string]string, len(outcomes)), err: ErrNoConsensusChallenge}
+		for id, outcome := range outcomes {
+			failure.validators[id] = outcome
+		}
+		accessMu.RUnlock()
+		cr.CancelChallenge(ctx, failure)
 		return ErrNoConsensusChallenge
 	}
 
@@ -424,6 +480,7 @@ func (cr *ChallengeEntity) SaveChallengeResult(ctx context.Context, t *transacti
 	}
 
 	txnVerification := time.Now()
+	observeStage(stageVerification, cr.CreatedAt, txnVerification)
 	if err := UpdateChallengeTimingTxnVerification(cr.ChallengeID, common.Timestamp(txnVerification.Unix())); err != nil {
 		logging.Logger.Error("[challengetiming]txnverification",
 			zap.Any("challenge_id", cr.ChallengeID),
@@ -0,0 +1,174 @@
+package challenge
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+)
+
+// currentRound estimates the current round of the chain from the last round
+// fetched from the sharders and the round rate seen since.
+func currentRound() int64 {
+	return roundInfo.CurrentRound + int64(float64(roundInfo.LastRoundDiff)*(float64(time.Since(roundInfo.CurrentRoundCaptureTime).Milliseconds())/float64(GetRoundInterval.Milliseconds())))
+}
+
+// roundDuration estimates the duration of a round.
+func roundDuration() time.Duration {
+	if roundInfo.LastRoundDiff <= 0 {
+		return GetRoundInterval / 1000
+	}
+	return GetRoundInterval / time.Duration(roundInfo.LastRoundDiff)
+}
+
+// expiryRound is the round the challenge expires at.
+func (c *ChallengeEntity) expiryRound() int64 {
+	return c.RoundCreatedAt + config.StorageSCConfig.ChallengeCompletionTime
+}
+
+// slack is the number of rounds left before the challenge expires.
+func (c *ChallengeEntity) slack() int64 {
+	return c.expiryRound() - currentRound()
+}
+
+// proofCostModel estimates the time to generate the proof of a challenge
+// from the proof times observed for each filestore version. Until the file
+// of a challenge is selected, the last file challenged in the allocation
+// is used as a hint of its size.
+type proofCostModel struct {
+	mu       sync.Mutex
+	msPerMB  map[int]float64
+	lastFile map[string]fileHint
+}
+
+type fileHint struct {
+	size    int64
+	version int
+}
+
+const (
+	// weight of the latest observation in the moving average of proof times
+	proofCostAlpha = 0.2
+	// bound of the allocations tracked for file hints
+	maxFileHints = 10000
+)
+
+var proofCost = &proofCostModel{
+	msPerMB:  make(map[int]float64),
+	lastFile: make(map[string]fileHint),
+}
+
+func (m *proofCostModel) observe(allocationID string, size int64, version int, proofGenTime time.Duration) {
+	if size <= 0 || proofGenTime < 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ms := float64(proofGenTime.Milliseconds()) * float64(1<<20) / float64(size)
+	if prev, ok := m.msPerMB[version]; ok {
+		ms = prev + proofCostAlpha*(ms-prev)
+	}
+	m.msPerMB[version] = ms
+
+	if _, ok := m.lastFile[allocationID]; !ok && len(m.lastFile) >= maxFileHints {
+		clear(m.lastFile)
+	}
+	m.lastFile[allocationID] = fileHint{size: size, version: version}
+}
+
+func (m *proofCostModel) estimate(c *ChallengeEntity) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hint, ok := m.lastFile[c.AllocationID]
+	if c.ObjectPath != nil && c.ObjectPath.Size > 0 {
+		hint, ok = fileHint{size: c.ObjectPath.Size, version: c.ObjectPath.FilestoreVersion}, true
+	}
+	if !ok {
+		return 0
+	}
+	ms := m.msPerMB[hint.version] * float64(hint.size) / float64(1<<20)
+	return time.Duration(ms) * time.Millisecond
+}
+
+// challengeQueue orders the challenges to validate by the time left before
+// they expire, less the estimated time to generate their proof.
+type challengeQueue struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	items challengeHeap
+}
+
+type queuedChallenge struct {
+	challenge *ChallengeEntity
+	startBy   time.Time
+}
+
+type challengeHeap []*queuedChallenge
+
+func (h challengeHeap) Len() int { return len(h) }
+func (h challengeHeap) Less(i, j int) bool {
+	if !h[i].startBy.Equal(h[j].startBy) {
+		return h[i].startBy.Before(h[j].startBy)
+	}
+	return h[i].challenge.RoundCreatedAt < h[j].challenge.RoundCreatedAt
+}
+func (h challengeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *challengeHeap) Push(x interface{}) { *h = append(*h, x.(*queuedChallenge)) }
+func (h *challengeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return it
+}
+
+func newChallengeQueue() *challengeQueue {
+	q := &challengeQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *challengeQueue) push(c *ChallengeEntity, now time.Time) {
+	deadline := now.Add(time.Duration(c.slack()) * roundDuration())
+	qc := &queuedChallenge{
+		challenge: c,
+		startBy:   deadline.Add(-proofCost.estimate(c)),
+	}
+
+	q.mu.Lock()
+	heap.Push(&q.items, qc)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+// pop waits for the most urgent challenge. It returns nil once ctx is done.
+func (q *challengeQueue) pop(ctx context.Context) *ChallengeEntity {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.items.Len() == 0 {
+		if ctx.Err() != nil {
+			return nil
+		}
+		q.cond.Wait()
+	}
+	return heap.Pop(&q.items).(*queuedChallenge).challenge
+}
+
+// close wakes up the workers waiting on the queue once their context is done.
+func (q *challengeQueue) close() {
+	q.mu.Lock()
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+func (q *challengeQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
@@ -0,0 +1,79 @@
+package challenge
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeQueue_Order(t *testing.T) {
+	config.StorageSCConfig.ChallengeCompletionTime = 100
+	roundInfo = RoundInfo{CurrentRound: 1000, CurrentRoundCaptureTime: time.Now(), LastRoundDiff: 1000}
+	proofCost = &proofCostModel{
+		msPerMB:  map[int]float64{1: 1000},
+		lastFile: make(map[string]fileHint),
+	}
+
+	q := newChallengeQueue()
+	now := time.Now()
+	q.push(&ChallengeEntity{ChallengeID: "late", AllocationID: "a", RoundCreatedAt: 990}, now)
+	q.push(&ChallengeEntity{ChallengeID: "early", AllocationID: "a", RoundCreatedAt: 950}, now)
+	// created after "late" but its proof takes long enough to start before it
+	q.push(&ChallengeEntity{
+		ChallengeID:    "large",
+		AllocationID:   "a",
+		RoundCreatedAt: 995,
+		ObjectPath:     &reference.ObjectPath{Size: 3 << 20, FilestoreVersion: 1},
+	}, now)
+	require.Equal(t, 3, q.len())
+
+	ctx := context.Background()
+	require.Equal(t, "early", q.pop(ctx).ChallengeID)
+	require.Equal(t, "large", q.pop(ctx).ChallengeID)
+	require.Equal(t, "late", q.pop(ctx).ChallengeID)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Nil(t, q.pop(cctx))
+}
+
+func TestProofCostModel(t *testing.T) {
+	m := &proofCostModel{msPerMB: make(map[int]float64), lastFile: make(map[string]fileHint)}
+	c := &ChallengeEntity{AllocationID: "a"}
+	require.Zero(t, m.estimate(c))
+
+	m.observe("a", 4<<20, 0, 400*time.Millisecond)
+	require.Equal(t, 400*time.Millisecond, m.estimate(c))
+
+	// the selected file is used over the allocation hint
+	c.ObjectPath = &reference.ObjectPath{Size: 1 << 20}
+	require.Equal(t, 100*time.Millisecond, m.estimate(c))
+
+	m.observe("a", 1<<20, 0, 200*time.Millisecond)
+	require.Equal(t, 120*time.Millisecond, m.estimate(c))
+}
+
+func TestGetBatch_ByExpiry(t *testing.T) {
+	config.StorageSCConfig.ChallengeCompletionTime = 100
+	challengeMap.Clear()
+	defer challengeMap.Clear()
+	for round, status := range map[int64]ChallengeStatus{1: Processed, 2: Accepted, 3: Processed, 4: Processed} {
+		challengeMap.Put(round, &ChallengeEntity{RoundCreatedAt: round, Status: status, statusMutex: &sync.Mutex{}})
+	}
+
+	batch := getBatch(10)
+	require.Len(t, batch, 3, "the challenges being validated don't hold back the processed ones")
+	for i, round := range []int64{1, 3, 4} {
+		require.EqualValues(t, round, batch[i].RoundCreatedAt)
+	}
+
+	batch = getBatch(2)
+	require.Len(t, batch, 2)
+	require.EqualValues(t, 1, batch[0].RoundCreatedAt, "the batch takes the challenges closest to their expiry")
+	require.EqualValues(t, 3, batch[1].RoundCreatedAt)
+}
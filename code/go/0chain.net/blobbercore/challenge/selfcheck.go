@@ -0,0 +1,297 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/lock"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Stages of a self challenge, reported when the allocation is not ready.
+const (
+	SelfChallengeStageWriteMarker  = "write_marker"
+	SelfChallengeStageObjectPath   = "object_path"
+	SelfChallengeStageProof        = "proof"
+	SelfChallengeStageVerification = "verification"
+)
+
+// SelfChallenge is a challenge the blobber issues to itself, on the latest
+// write marker of the allocation. It is never submitted on chain.
+type SelfChallenge struct {
+	ID             string
+	AllocationID   string
+	AllocationRoot string
+	Seed           int64
+	Timestamp      common.Timestamp
+}
+
+// SelfChallengeVerifier verifies the json encoded challenge request built for
+// the self challenge, the same way a validator does.
+type SelfChallengeVerifier func(sc *SelfChallenge, request []byte) error
+
+var selfChallengeVerifier SelfChallengeVerifier
+
+// SetSelfChallengeVerifier sets the verifier of the self challenges. The
+// validator code depends on the handlers of the blobber, so it is wired in
+// by the blobber binary.
+func SetSelfChallengeVerifier(v SelfChallengeVerifier) {
+	selfChallengeVerifier = v
+}
+
+// SelfChallengeResult is the readiness of an allocation to be challenged, as
+// found by the latest self challenge on it.
+// swagger:model SelfChallengeResult
+type SelfChallengeResult struct {
+	AllocationID   string           `json:"allocation_id"`
+	AllocationRoot string           `json:"allocation_root"`
+	Seed           int64            `json:"seed"`
+	BlockNum       int64            `json:"block_num"`
+	Path           string           `json:"path,omitempty"`
+	Ready          bool             `json:"ready"`
+	Stage          string           `json:"stage,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	ProofGenTime   int64            `json:"proof_gen_time"`
+	CheckedAt      common.Timestamp `json:"checked_at"`
+}
+
+func (r *SelfChallengeResult) fail(stage string, err error) *SelfChallengeResult {
+	r.Ready = false
+	r.Stage = stage
+	r.Error = err.Error()
+	return r
+}
+
+var selfChallengeResults = struct {
+	sync.RWMutex
+	m map[string]*SelfChallengeResult
+}{m: make(map[string]*SelfChallengeResult)}
+
+func setSelfChallengeResult(r *SelfChallengeResult) {
+	selfChallengeResults.Lock()
+	selfChallengeResults.m[r.AllocationID] = r
+	selfChallengeResults.Unlock()
+}
+
+// GetSelfChallengeResults returns the latest self challenge result of each
+// allocation, the allocations not ready first.
+func GetSelfChallengeResults() []*SelfChallengeResult {
+	selfChallengeResults.RLock()
+	results := make([]*SelfChallengeResult, 0, len(selfChallengeResults.m))
+	for _, r := range selfChallengeResults.m {
+		results = append(results, r)
+	}
+	selfChallengeResults.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Ready != results[j].Ready {
+			return !results[i].Ready
+		}
+		return results[i].AllocationID < results[j].AllocationID
+	})
+	return results
+}
+
+// RunSelfChallenge challenges a random block of the allocation the way the
+// blockchain does and verifies the proof in-process.
+func RunSelfChallenge(ctx context.Context, allocationID string) (*SelfChallengeResult, error) {
+	if selfChallengeVerifier == nil {
+		return nil, common.NewError("self_challenge_disabled", "No verifier is set for self challenges")
+	}
+
+	sc := &SelfChallenge{
+		AllocationID: allocationID,
+		Seed:         rand.Int63(),
+	}
+	sc.ID = fmt.Sprintf("self:%s:%d", allocationID, sc.Seed)
+
+	result := &SelfChallengeResult{
+		AllocationID: allocationID,
+		Seed:         sc.Seed,
+		ProofGenTime: -1,
+		CheckedAt:    common.Now(),
+	}
+
+	request, err := buildSelfChallengeRequest(ctx, sc, result)
+	if err != nil {
+		return nil, err
+	}
+	if request != nil {
+		if err := selfChallengeVerifier(sc, request); err != nil {
+			result.fail(SelfChallengeStageVerification, err)
+		} else {
+			result.Ready = true
+		}
+	}
+
+	if !result.Ready {
+		logging.Logger.Warn("[selfchallenge]allocation not ready",
+			zap.String("allocation_id", allocationID),
+			zap.String("stage", result.Stage),
+			zap.Int64("block_num", result.BlockNum),
+			zap.String("path", result.Path),
+			zap.String("error", result.Error))
+	}
+	setSelfChallengeResult(result)
+	return result, nil
+}
+
+// buildSelfChallengeRequest builds the request a validator would get for the
+// self challenge. It returns a nil request once the result is final.
+func buildSelfChallengeRequest(ctx context.Context, sc *SelfChallenge, result *SelfChallengeResult) ([]byte, error) {
+	allocMu := lock.GetMutex(allocation.Allocation{}.TableName(), sc.AllocationID)
+	allocMu.RLock()
+	defer allocMu.RUnlock()
+
+	allocationObj, err := allocation.Repo.GetAllocationFromDB(ctx, sc.AllocationID)
+	if err != nil {
+		return nil, common.NewError("invalid_allocation", "Allocation not found. "+err.Error())
+	}
+	result.AllocationRoot = allocationObj.AllocationRoot
+
+	// nothing was written to the allocation yet
+	if allocationObj.AllocationRoot == "" {
+		result.Ready = true
+		return nil, nil
+	}
+
+	wm, err := writemarker.GetWriteMarkerEntity(ctx, allocationObj.AllocationRoot)
+	if err != nil {
+		result.fail(SelfChallengeStageWriteMarker, err)
+		return nil, nil
+	}
+	sc.AllocationRoot = wm.WM.AllocationRoot
+	sc.Timestamp = wm.WM.Timestamp
+
+	rootRef, err := reference.GetReference(ctx, sc.AllocationID, "/")
+	if err != nil && err != gorm.ErrRecordNotFound {
+		result.fail(SelfChallengeStageObjectPath, err)
+		return nil, nil
+	}
+
+	postData := make(map[string]interface{})
+	postData["challenge_id"] = sc.ID
+	postData["write_markers"] = writeMarkersPostData([]*writemarker.WriteMarkerEntity{wm})
+
+	if rootRef != nil {
+		if rootRef.NumBlocks > 0 {
+			result.BlockNum = challengeBlockNum(rootRef.NumBlocks, sc.Seed)
+		}
+
+		objectPath, err := reference.GetObjectPath(ctx, sc.AllocationID, result.BlockNum)
+		if err != nil {
+			result.fail(SelfChallengeStageObjectPath, err)
+			return nil, nil
+		}
+		if objectPath != nil {
+			postData["object_path"] = objectPath
+		}
+
+		if result.BlockNum > 0 {
+			if objectPath.Meta["type"] != reference.FILE {
+				result.fail(SelfChallengeStageObjectPath, ErrInvalidObjectPath)
+				return nil, nil
+			}
+			result.Path, _ = objectPath.Meta["path"].(string)
+
+			t1 := time.Now()
+			// read from disk to catch files that can not be proven any more
+			challengeReadInput := newChallengeReadInput(sc.AllocationID, objectPath, sc.Seed)
+			challengeReadInput.SkipCache = true
+			challengeResponse, err := filestore.GetFileStore().GetBlocksMerkleTreeForChallenge(challengeReadInput)
+			if err != nil {
+				result.fail(SelfChallengeStageProof, err)
+				return nil, nil
+			}
+			result.ProofGenTime = time.Since(t1).Milliseconds()
+			postData["challenge_proof"] = challengeResponse
+		}
+	}
+
+	return json.Marshal(postData)
+}
+
+// SetupSelfChallengeWorker starts the worker checking periodically that a
+// random block of each allocation can be proven.
+func SetupSelfChallengeWorker(ctx context.Context) {
+	if !config.Configuration.SelfChallengeEnabled || config.Configuration.SelfChallengeInterval <= 0 {
+		return
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(config.Configuration.SelfChallengeInterval):
+				selfChallengeAllocations(ctx)
+			}
+		}
+	}()
+}
+
+func selfChallengeAllocations(ctx context.Context) {
+	var ids []string
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		db := datastore.GetStore().GetTransaction(ctx)
+		return db.Model(&allocation.Allocation{}).
+			Where("finalized = false AND cleaned_up = false").
+			Order("id ASC").
+			Pluck("id", &ids).Error
+	})
+	if err != nil {
+		logging.Logger.Error("[selfchallenge]finding allocations", zap.Error(err))
+		return
+	}
+
+	notReady := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		var result *SelfChallengeResult
+		err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+			var err error
+			result, err = RunSelfChallenge(ctx, id)
+			return err
+		})
+		if err != nil {
+			logging.Logger.Error("[selfchallenge]", zap.String("allocation_id", id), zap.Error(err))
+			continue
+		}
+		if !result.Ready {
+			notReady++
+		}
+	}
+
+	// forget the allocations that are gone
+	active := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		active[id] = struct{}{}
+	}
+	selfChallengeResults.Lock()
+	for id := range selfChallengeResults.m {
+		if _, ok := active[id]; !ok {
+			delete(selfChallengeResults.m, id)
+		}
+	}
+	selfChallengeResults.Unlock()
+
+	logging.Logger.Info("[selfchallenge]completed",
+		zap.Int("allocations", len(ids)),
+		zap.Int("not_ready", notReady))
+}
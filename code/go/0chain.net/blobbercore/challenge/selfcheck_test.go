@@ -0,0 +1,36 @@
+package challenge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeBlockNum(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		blockNum := challengeBlockNum(10, seed)
+		require.GreaterOrEqual(t, blockNum, int64(1))
+		require.LessOrEqual(t, blockNum, int64(10))
+		require.Equal(t, blockNum, challengeBlockNum(10, seed))
+	}
+}
+
+func TestGetSelfChallengeResults_NotReadyFirst(t *testing.T) {
+	selfChallengeResults.m = make(map[string]*SelfChallengeResult)
+	setSelfChallengeResult(&SelfChallengeResult{AllocationID: "a", Ready: true})
+	setSelfChallengeResult(&SelfChallengeResult{AllocationID: "c", Ready: false, Stage: SelfChallengeStageProof})
+	setSelfChallengeResult(&SelfChallengeResult{AllocationID: "b", Ready: false, Stage: SelfChallengeStageObjectPath})
+
+	results := GetSelfChallengeResults()
+	require.Len(t, results, 3)
+	require.Equal(t, "b", results[0].AllocationID)
+	require.Equal(t, "c", results[1].AllocationID)
+	require.Equal(t, "a", results[2].AllocationID)
+}
+
+func TestRunSelfChallenge_NoVerifier(t *testing.T) {
+	SetSelfChallengeVerifier(nil)
+	_, err := RunSelfChallenge(context.TODO(), "a")
+	require.Error(t, err)
+}
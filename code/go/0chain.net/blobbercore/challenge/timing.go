@@ -27,8 +27,12 @@ type ChallengeTiming struct {
 	ProofGenTime int64 `gorm:"proof_gen_time" json:"proof_gen_time"`
 	// CompleteValidation is when all validation tickets are all received.
 	CompleteValidation common.Timestamp `gorm:"complete_validation" json:"complete_validation"`
+	// ValidationSlack is the number of rounds left before expiration when validation completed.
+	ValidationSlack int64 `gorm:"column:validation_slack" json:"validation_slack"`
 	// TxnSubmission is when challenge response is first sent to blockchain.
 	TxnSubmission common.Timestamp `gorm:"txn_submission" json:"txn_submission"`
+	// SubmissionSlack is the number of rounds left before expiration when challenge response was submitted.
+	SubmissionSlack int64 `gorm:"column:submission_slack" json:"submission_slack"`
 	// TxnVerification is when challenge response is verified on blockchain.
 	TxnVerification common.Timestamp `gorm:"txn_verification" json:"txn_verification"`
 	// Cancelled is when challenge is cancelled by blobber due to expiration or bad challenge data (eg. invalid ref or not a file) which is impossible to validate.
@@ -93,13 +97,17 @@ func UpdateChallengeTimingCancellation(challengeID string, cancellation common.T
 	return err
 }
 
-func UpdateChallengeTimingCompleteValidation(challengeID string, completeValidation common.Timestamp) error {
+func UpdateChallengeTimingCompleteValidation(challengeID string, completeValidation common.Timestamp, slack int64) error {
 	c := &ChallengeTiming{
 		ChallengeID: challengeID,
 	}
 	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 		tx := datastore.GetStore().GetTransaction(ctx)
-		return tx.Model(&c).Update("complete_validation", completeValidation).Error
+		values := map[string]interface{}{
+			"complete_validation": completeValidation,
+			"validation_slack":    slack,
+		}
+		return tx.Model(&c).Updates(values).Error
 	})
 
 	return err
@@ -128,14 +136,18 @@ func UpdateChallengeTimingProofGenerationAndFileSize(
 	return err
 }
 
-func UpdateChallengeTimingTxnSubmission(challengeID string, txnSubmission common.Timestamp) error {
+func UpdateChallengeTimingTxnSubmission(challengeID string, txnSubmission common.Timestamp, slack int64) error {
 	c := &ChallengeTiming{
 		ChallengeID: challengeID,
 	}
 
 	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 		tx := datastore.GetStore().GetTransaction(ctx)
-		return tx.Model(&c).Update("txn_submission", txnSubmission).Error
+		values := map[string]interface{}{
+			"txn_submission":   txnSubmission,
+			"submission_slack": slack,
+		}
+		return tx.Model(&c).Updates(values).Error
 	})
 
 	return err
@@ -198,6 +210,7 @@ func SetupChallengeTimingsCleanupWorker(ctx context.Context) {
 				return
 			case <-time.After(cleanupInterval):
 				cleanUpTimingWorker()
+				cleanUpChallengeFailures()
 			}
 		}
 	}()
@@ -0,0 +1,224 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	"github.com/0chain/blobber/code/go/0chain.net/core/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+const VALIDATOR_BATCH_URL = "/v1/storage/challenge/batch"
+
+type validatorRequest struct {
+	// ctx is the trace of the validation of the challenge
+	ctx         context.Context
+	challengeID string
+	data        []byte
+	done        chan validatorResponse
+}
+
+type validatorResponse struct {
+	ticket []byte
+	err    error
+}
+
+type batchChallengeResult struct {
+	ChallengeID string          `json:"challenge_id"`
+	Ticket      json.RawMessage `json:"ticket,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+type batchChallengeResponse struct {
+	Results []*batchChallengeResult `json:"results"`
+}
+
+// validatorClient sends the challenges to a validator. It caps the requests
+// in flight to the validator, so a burst of challenges is spread over the
+// validators instead of queueing on the slowest one. The challenges queued
+// meanwhile are sent together in a batch once a request completes.
+type validatorClient struct {
+	id  string
+	url string
+
+	mu       sync.Mutex
+	inFlight int
+	pending  []*validatorRequest
+	// noBatch is set once the validator turns out not to support batches
+	noBatch bool
+}
+
+var validatorClients = struct {
+	sync.Mutex
+	m map[string]*validatorClient
+}{m: make(map[string]*validatorClient)}
+
+func getValidatorClient(validatorID, url string) *validatorClient {
+	validatorClients.Lock()
+	defer validatorClients.Unlock()
+	vc, ok := validatorClients.m[validatorID]
+	if !ok || vc.url != url {
+		vc = &validatorClient{id: validatorID, url: url}
+		validatorClients.m[validatorID] = vc
+	}
+	return vc
+}
+
+// send posts the challenge request to the validator and returns the
+// validation ticket it responded with.
+func (vc *validatorClient) send(ctx context.Context, challengeID string, data []byte) ([]byte, error) {
+	req := &validatorRequest{
+		ctx:         ctx,
+		challengeID: challengeID,
+		data:        data,
+		done:        make(chan validatorResponse, 1),
+	}
+
+	vc.mu.Lock()
+	vc.pending = append(vc.pending, req)
+	vc.dispatch()
+	vc.mu.Unlock()
+
+	select {
+	case res := <-req.done:
+		return res.ticket, res.err
+	case <-ctx.Done():
+		vc.mu.Lock()
+		for i, r := range vc.pending {
+			if r == req {
+				vc.pending = append(vc.pending[:i], vc.pending[i+1:]...)
+				break
+			}
+		}
+		vc.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch sends the pending requests while the validator has free slots.
+// It must be called with vc.mu held.
+func (vc *validatorClient) dispatch() {
+	maxInFlight := config.Configuration.ChallengeValidatorConcurrency
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	batchSize := config.Configuration.ChallengeValidatorBatchSize
+	if batchSize <= 0 || vc.noBatch {
+		batchSize = 1
+	}
+
+	for vc.inFlight < maxInFlight && len(vc.pending) > 0 {
+		n := len(vc.pending)
+		if n > batchSize {
+			n = batchSize
+		}
+		batch := make([]*validatorRequest, n)
+		copy(batch, vc.pending)
+		vc.pending = vc.pending[n:]
+		vc.inFlight++
+		go vc.post(batch)
+	}
+}
+
+func (vc *validatorClient) post(batch []*validatorRequest) {
+	defer func() {
+		vc.mu.Lock()
+		vc.inFlight--
+		vc.dispatch()
+		vc.mu.Unlock()
+	}()
+
+	if len(batch) > 1 {
+		err := vc.postBatch(batch)
+		if err == nil {
+			return
+		}
+		logging.Logger.Error("[challenge]batch: falling back to single requests",
+			zap.String("validator", vc.id),
+			zap.Int("challenges", len(batch)),
+			zap.Error(err))
+	}
+
+	for _, req := range batch {
+		ticket, err := util.SendPostRequestContext(req.ctx, vc.url+VALIDATOR_URL, req.data, nil)
+		req.done <- validatorResponse{ticket: ticket, err: err}
+	}
+}
+
+// postBatch sends the requests in a single batch. It returns an error if the
+// batch as a whole could not be validated.
+func (vc *validatorClient) postBatch(batch []*validatorRequest) (err error) {
+	challenges := make([]json.RawMessage, len(batch))
+	for i, req := range batch {
+		challenges[i] = req.data
+	}
+	data, err := json.Marshal(map[string]interface{}{"challenges": challenges})
+	if err != nil {
+		return err
+	}
+
+	// the batch is traced as part of the validation of its first challenge,
+	// linked to the validations of the others
+	others := make([]context.Context, 0, len(batch)-1)
+	for _, req := range batch[1:] {
+		others = append(others, req.ctx)
+	}
+	spanCtx, span := tracing.StartLinked(batch[0].ctx, "challenge.validatorBatch", others,
+		attribute.String("validator", vc.id),
+		attribute.Int("challenges", len(batch)))
+	defer func() { tracing.End(span, err) }()
+
+	req, ctx, cncl, err := util.NewHTTPRequestContext(spanCtx, http.MethodPost, vc.url+VALIDATOR_BATCH_URL, data)
+	defer cncl()
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		vc.mu.Lock()
+		vc.noBatch = true
+		vc.mu.Unlock()
+		return common.NewError("batch_not_supported", "Validator does not support batch validation")
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return common.NewError("http_error", "Error from HTTP call. "+string(body))
+	}
+
+	var batchResp batchChallengeResponse
+	if err := json.Unmarshal(body, &batchResp); err != nil {
+		return err
+	}
+	if len(batchResp.Results) != len(batch) {
+		return common.NewErrorf("invalid_batch_response", "Expected %d results, got %d", len(batch), len(batchResp.Results))
+	}
+
+	for i, req := range batch {
+		result := batchResp.Results[i]
+		switch {
+		case result.ChallengeID != req.challengeID:
+			req.done <- validatorResponse{err: common.NewError("invalid_batch_response", "Result is for a different challenge")}
+		case result.Error != "":
+			req.done <- validatorResponse{err: common.NewError("validation_error", result.Error)}
+		default:
+			req.done <- validatorResponse{ticket: result.Ticket}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testChallenge struct {
+	ChallengeID string `json:"challenge_id"`
+}
+
+func TestValidatorClient_BatchesQueuedChallenges(t *testing.T) {
+	config.Configuration.ChallengeValidatorConcurrency = 1
+	config.Configuration.ChallengeValidatorBatchSize = 10
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		batches [][]string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc(VALIDATOR_URL, func(w http.ResponseWriter, r *http.Request) {
+		var c testChallenge
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+		close(started)
+		<-release
+		_ = json.NewEncoder(w).Encode(c)
+	})
+	mux.HandleFunc(VALIDATOR_BATCH_URL, func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			Challenges []testChallenge `json:"challenges"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &batch))
+
+		resp := batchChallengeResponse{}
+		ids := make([]string, 0, len(batch.Challenges))
+		for _, c := range batch.Challenges {
+			ticket, _ := json.Marshal(c)
+			resp.Results = append(resp.Results, &batchChallengeResult{ChallengeID: c.ChallengeID, Ticket: ticket})
+			ids = append(ids, c.ChallengeID)
+		}
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	vc := &validatorClient{id: "v", url: server.URL}
+	send := func(id string) {
+		data, _ := json.Marshal(testChallenge{ChallengeID: id})
+		ticket, err := vc.send(context.TODO(), id, data)
+		require.NoError(t, err)
+		var c testChallenge
+		require.NoError(t, json.Unmarshal(ticket, &c))
+		require.Equal(t, id, c.ChallengeID)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		send("first")
+	}()
+	<-started
+
+	// queued while the first challenge is in flight
+	for _, id := range []string{"a", "b", "c"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			send(id)
+		}(id)
+	}
+	require.Eventually(t, func() bool {
+		vc.mu.Lock()
+		defer vc.mu.Unlock()
+		return len(vc.pending) == 3
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	require.Len(t, batches, 1)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, batches[0])
+	require.Eventually(t, func() bool {
+		vc.mu.Lock()
+		defer vc.mu.Unlock()
+		return vc.inFlight == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestValidatorClient_NoBatchSupport(t *testing.T) {
+	logging.Logger = zap.NewNop()
+	config.Configuration.ChallengeValidatorConcurrency = 1
+	config.Configuration.ChallengeValidatorBatchSize = 10
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(VALIDATOR_URL, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	vc := &validatorClient{id: "v", url: server.URL}
+	vc.inFlight = 1
+	reqs := make([]*validatorRequest, 0, 2)
+	for _, id := range []string{"a", "b"} {
+		data, _ := json.Marshal(testChallenge{ChallengeID: id})
+		reqs = append(reqs, &validatorRequest{ctx: context.TODO(), challengeID: id, data: data, done: make(chan validatorResponse, 1)})
+	}
+	vc.post(reqs)
+
+	require.True(t, vc.noBatch)
+	for _, req := range reqs {
+		res := <-req.done
+		require.NoError(t, res.err)
+		require.JSONEq(t, string(req.data), string(res.ticket))
+	}
+}
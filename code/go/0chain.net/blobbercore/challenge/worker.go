@@ -2,6 +2,7 @@ package challenge
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -10,10 +11,11 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
 	"github.com/emirpasic/gods/maps/treemap"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
-	"golang.org/x/sync/semaphore"
 )
 
 const GetRoundInterval = 3 * time.Minute
@@ -47,6 +49,7 @@ var (
 	toProcessChallenge = make(chan *ChallengeEntity, 100)
 	challengeMap       = treemap.NewWith(Int64Comparator)
 	challengeMapLock   = sync.RWMutex{}
+	toValidate         = newChallengeQueue()
 	roundInfo          = RoundInfo{}
 )
 
@@ -113,13 +116,16 @@ func challengeProcessor(ctx context.Context) {
 		}
 	}()
 	numWorkers := config.Configuration.ChallengeResolveNumWorkers
-	sem := semaphore.NewWeighted(int64(numWorkers))
 	logging.Logger.Info("initializing challenge workers",
 		zap.Int("num_workers", numWorkers))
+	for i := 0; i < numWorkers; i++ {
+		go validationWorker(ctx)
+	}
 	for {
 		select {
 		case <-ctx.Done():
 			logging.Logger.Info("exiting challengeProcessor")
+			toValidate.close()
 			return
 
 		case it := <-toProcessChallenge:
@@ -134,16 +140,26 @@ func challengeProcessor(ctx context.Context) {
 				continue
 			}
 
-			err := sem.Acquire(ctx, 1)
-			if err != nil {
-				logging.Logger.Error("failed to acquire semaphore", zap.Error(err))
-				continue
-			}
-			go func(it *ChallengeEntity) {
-				processChallenge(ctx, it)
-				sem.Release(1)
-			}(it)
+			toValidate.push(it, time.Now())
+		}
+	}
+}
+
+// validationWorker validates the most urgent challenge of the queue, one at a time.
+func validationWorker(ctx context.Context) {
+	for {
+		it := toValidate.pop(ctx)
+		if it == nil {
+			return
 		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logging.Logger.Error("[validationWorker]challenge", zap.Any("err", r))
+				}
+			}()
+			processChallenge(ctx, it)
+		}()
 	}
 }
 
@@ -152,9 +168,11 @@ func processChallenge(ctx context.Context, it *ChallengeEntity) {
 	logging.Logger.Info("processing_challenge",
 		zap.String("challenge_id", it.ChallengeID))
 
-	_ = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+	spanCtx, span := tracing.Start(ctx, "challenge.validate", it.traceAttributes()...)
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 		return validateOnValidators(ctx, it)
-	})
+	}, datastore.WithParent(spanCtx))
+	tracing.End(span, err)
 }
 
 func commitOnChainWorker(ctx context.Context) {
@@ -186,15 +204,20 @@ func commitOnChainWorker(ctx context.Context) {
 				txn *transaction.Transaction
 				err error
 			)
+			spanCtx, span := tracing.Start(ctx, "challenge.commit", chall.traceAttributes()...)
 			_ = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 				txn, err = chall.getCommitTransaction(ctx)
 				return err
-			})
+			}, datastore.WithParent(spanCtx))
 
-			if txn != nil {
+			if txn == nil {
+				tracing.End(span, err)
+			} else {
 				wg.Add(1)
 				go func(challenge *ChallengeEntity) {
+					var verifyErr error
 					defer func() {
+						tracing.End(span, verifyErr)
 						wg.Done()
 						if r := recover(); r != nil {
 							logging.Logger.Error("verifyChallengeTransaction", zap.Any("err", r))
@@ -205,8 +228,12 @@ func commitOnChainWorker(ctx context.Context) {
 						if err == nil || err != ErrEntityNotFound {
 							deleteChallenge(challenge.RoundCreatedAt)
 						}
+						if err != nil && err != ErrEntityNotFound {
+							verifyErr = err
+							recordChallengeFailure(challenge, withFailureCategory(FailureChainVerify, err))
+						}
 						return nil
-					})
+					}, datastore.WithParent(spanCtx))
 				}(&chall)
 			}
 		}
@@ -214,6 +241,9 @@ func commitOnChainWorker(ctx context.Context) {
 	}
 }
 
+// getBatch returns the processed challenges closest to their expiry. The
+// challenges still being validated are skipped, they don't hold back the
+// submission of the ones already processed.
 func getBatch(batchSize int) (chall []ChallengeEntity) {
 	challengeMapLock.RLock()
 
@@ -225,15 +255,10 @@ func getBatch(batchSize int) (chall []ChallengeEntity) {
 	var toClean []int64
 	it := challengeMap.Iterator()
 	for it.Next() {
-		if len(chall) >= batchSize {
-			break
-		}
 		ticket := it.Value().(*ChallengeEntity)
 		ticket.statusMutex.Lock()
 		switch ticket.Status {
 		case Accepted:
-			ticket.statusMutex.Unlock()
-			goto brekLoop
 		case Processed:
 			chall = append(chall, *ticket)
 		default:
@@ -241,15 +266,27 @@ func getBatch(batchSize int) (chall []ChallengeEntity) {
 		}
 		ticket.statusMutex.Unlock()
 	}
-brekLoop:
 	challengeMapLock.RUnlock()
 	for _, r := range toClean {
 		deleteChallenge(r)
 	}
 
+	sort.SliceStable(chall, func(i, j int) bool {
+		return chall[i].expiryRound() < chall[j].expiryRound()
+	})
+	if len(chall) > batchSize {
+		chall = chall[:batchSize]
+	}
 	return
 }
 
+func (it *ChallengeEntity) traceAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("challenge_id", it.ChallengeID),
+		attribute.String("allocation_id", it.AllocationID),
+	}
+}
+
 func (it *ChallengeEntity) createChallenge(ctx context.Context) bool {
 	db := datastore.GetStore().GetTransaction(ctx)
 
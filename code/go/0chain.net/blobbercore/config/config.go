@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/core/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
@@ -24,10 +25,36 @@ func SetupDefaultConfig() {
 	viper.SetDefault("writemarker_redeem.marker_redeem_interval", time.Minute*10)
 	viper.SetDefault("readmarker_redeem.frequency", 10)
 	viper.SetDefault("readmarker_redeem.num_workers", 5)
+	viper.SetDefault("readmarker_redeem.fee_aware", false)
+	viper.SetDefault("readmarker_redeem.min_value_fee_ratio", 1.0)
+	viper.SetDefault("readmarker_redeem.fee", 0)
+	viper.SetDefault("readmarker_redeem.fee_refresh_interval", time.Minute*10)
+	viper.SetDefault("readmarker_redeem.expiry_window", time.Hour*24)
+	viper.SetDefault("readmarker_redeem.max_deferral", time.Hour*24*7)
+	viper.SetDefault("presigned_url.enabled", false)
+	viper.SetDefault("presigned_url.max_lifetime", time.Hour*24*7)
+	viper.SetDefault("presigned_url.daily_block_limit", 156250)
+	viper.SetDefault("presigned_url.hmac_secret", "")
 	viper.SetDefault("challenge_response.frequency", 10)
 	viper.SetDefault("challenge_response.num_workers", 5)
 	viper.SetDefault("challenge_response.max_retries", 10)
 	viper.SetDefault("challenge_response.cleanup_gap", 100000)
+	viper.SetDefault("challenge_response.validator_concurrency", 5)
+	viper.SetDefault("challenge_response.validator_batch_size", 10)
+	viper.SetDefault("self_challenge.enabled", true)
+	viper.SetDefault("self_challenge.interval", time.Hour*6)
+	viper.SetDefault("challenge_proof_cache.enabled", true)
+	viper.SetDefault("challenge_proof_cache.max_size_mb", 256)
+	viper.SetDefault("challenge_proof_cache.max_object_paths", 10000)
+	viper.SetDefault("challenge_diagnostics.retention", time.Hour*24*7)
+	viper.SetDefault("audit_log.enabled", true)
+	viper.SetDefault("metrics.address", "127.0.0.1:9102")
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", tracing.ExporterStdout)
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.file", "")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 	viper.SetDefault("rate_limiters.block_limit_daily", 1562500)
 	viper.SetDefault("rate_limiters.block_limit_request", 500)
 	viper.SetDefault("rate_limiters.block_limit_monthly", 31250000)
@@ -35,6 +62,9 @@ func SetupDefaultConfig() {
 	viper.SetDefault("rate_limiters.commit_limit_monthly", 30000)
 	viper.SetDefault("rate_limiters.commit_limit_daily", 1600)
 	viper.SetDefault("rate_limiters.commit_zero_limit_daily", 400)
+	viper.SetDefault("rate_limiters.allocation_rps", 0)
+	viper.SetDefault("rate_limiters.allocation_upload_bps", 0)
+	viper.SetDefault("rate_limiters.allocation_download_bps", 0)
 
 	viper.SetDefault("healthcheck.frequency", "60s")
 
@@ -55,6 +85,14 @@ func SetupDefaultConfig() {
 	viper.SetDefault("max_objects_dir", 1000)
 }
 
+var reloadHooks []func(file string)
+
+// OnReload registers f to be called after the config file is reloaded. The
+// hooks must be registered before the config file can change.
+func OnReload(f func(file string)) {
+	reloadHooks = append(reloadHooks, f)
+}
+
 /*SetupConfig - setup the configuration system */
 func SetupConfig(configPath string) {
 	replacer := strings.NewReplacer(".", "_")
@@ -77,6 +115,9 @@ func SetupConfig(configPath string) {
 	viper.OnConfigChange(func(e fsnotify.Event) {
 		fmt.Println("Config file changed:", e.Name)
 		ReadConfig(int(Configuration.DeploymentMode))
+		for _, f := range reloadHooks {
+			f(e.Name)
+		}
 	})
 
 	viper.WatchConfig()
@@ -108,9 +149,28 @@ type Config struct {
 	MarkerRedeemInterval          time.Duration
 	RMRedeemFreq                  int64
 	RMRedeemNumWorkers            int
+	RMRedeemFeeAware              bool
+	RMRedeemMinValueFeeRatio      float64
+	RMRedeemFee                   uint64
+	RMRedeemFeeRefreshInterval    time.Duration
+	RMRedeemExpiryWindow          time.Duration
+	RMRedeemMaxDeferral           time.Duration
+	PresignedURLEnabled           bool
+	PresignedURLMaxLifetime       time.Duration
+	PresignedURLDailyBlockLimit   int64
+	PresignedURLHMACSecret        string
 	ChallengeResolveFreq          int64
 	ChallengeResolveNumWorkers    int
+	ChallengeValidatorConcurrency int
+	ChallengeValidatorBatchSize   int
 	ChallengeMaxRetires           int
+	SelfChallengeEnabled          bool
+	SelfChallengeInterval         time.Duration
+	ProofCacheEnabled             bool
+	ProofCacheMaxSizeMB           int64
+	ProofCacheMaxObjectPaths      int
+	ChallengeFailureRetention     time.Duration
+	AuditLogEnabled               bool
 	TempFilesCleanupFreq          int64
 	TempFilesCleanupNumWorkers    int
 	BlockLimitDaily               int64
@@ -120,6 +180,9 @@ type Config struct {
 	CommitLimitMonthly            int64
 	CommitLimitDaily              int64
 	CommitZeroLimitDaily          int64
+	AllocationRPS                 float64
+	AllocationUploadBPS           int64
+	AllocationDownloadBPS         int64
 	ChallengeCleanupGap           int64
 
 	HealthCheckWorkerFreq time.Duration
@@ -158,6 +221,12 @@ type Config struct {
 	BlobberUpdateInterval time.Duration
 
 	IsEnterprise bool
+
+	// MetricsAddress is the address the metrics are served on, apart from
+	// the API. Empty disables them.
+	MetricsAddress string
+
+	Tracing tracing.Config
 }
 
 /*Configuration of the system */
@@ -232,13 +301,43 @@ func ReadConfig(deploymentMode int) {
 
 	Configuration.RMRedeemFreq = viper.GetInt64("readmarker_redeem.frequency")
 	Configuration.RMRedeemNumWorkers = viper.GetInt("readmarker_redeem.num_workers")
+	Configuration.RMRedeemFeeAware = viper.GetBool("readmarker_redeem.fee_aware")
+	Configuration.RMRedeemMinValueFeeRatio = viper.GetFloat64("readmarker_redeem.min_value_fee_ratio")
+	Configuration.RMRedeemFee = viper.GetUint64("readmarker_redeem.fee")
+	Configuration.RMRedeemFeeRefreshInterval = viper.GetDuration("readmarker_redeem.fee_refresh_interval")
+	Configuration.RMRedeemExpiryWindow = viper.GetDuration("readmarker_redeem.expiry_window")
+	Configuration.RMRedeemMaxDeferral = viper.GetDuration("readmarker_redeem.max_deferral")
+
+	Configuration.PresignedURLEnabled = viper.GetBool("presigned_url.enabled")
+	Configuration.PresignedURLMaxLifetime = viper.GetDuration("presigned_url.max_lifetime")
+	Configuration.PresignedURLDailyBlockLimit = viper.GetInt64("presigned_url.daily_block_limit")
+	Configuration.PresignedURLHMACSecret = viper.GetString("presigned_url.hmac_secret")
 
 	Configuration.HealthCheckWorkerFreq = viper.GetDuration("healthcheck.frequency")
 
 	Configuration.ChallengeResolveFreq = viper.GetInt64("challenge_response.frequency")
 	Configuration.ChallengeResolveNumWorkers = viper.GetInt("challenge_response.num_workers")
+	Configuration.ChallengeValidatorConcurrency = viper.GetInt("challenge_response.validator_concurrency")
+	Configuration.ChallengeValidatorBatchSize = viper.GetInt("challenge_response.validator_batch_size")
 	Configuration.ChallengeMaxRetires = viper.GetInt("challenge_response.max_retries")
 	Configuration.ChallengeCleanupGap = viper.GetInt64("challenge_response.cleanup_gap")
+	Configuration.SelfChallengeEnabled = viper.GetBool("self_challenge.enabled")
+	Configuration.SelfChallengeInterval = viper.GetDuration("self_challenge.interval")
+	Configuration.ProofCacheEnabled = viper.GetBool("challenge_proof_cache.enabled")
+	Configuration.ProofCacheMaxSizeMB = viper.GetInt64("challenge_proof_cache.max_size_mb")
+	Configuration.ProofCacheMaxObjectPaths = viper.GetInt("challenge_proof_cache.max_object_paths")
+	Configuration.ChallengeFailureRetention = viper.GetDuration("challenge_diagnostics.retention")
+	Configuration.AuditLogEnabled = viper.GetBool("audit_log.enabled")
+
+	Configuration.MetricsAddress = viper.GetString("metrics.address")
+	Configuration.Tracing = tracing.Config{
+		Enabled:     viper.GetBool("tracing.enabled"),
+		Exporter:    viper.GetString("tracing.exporter"),
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		Insecure:    viper.GetBool("tracing.insecure"),
+		File:        viper.GetString("tracing.file"),
+		SampleRatio: viper.GetFloat64("tracing.sample_ratio"),
+	}
 
 	Configuration.AutomaticUpdate = viper.GetBool("disk_update.automatic_update")
 	blobberUpdateIntrv := viper.GetDuration("disk_update.blobber_update_interval")
@@ -300,6 +399,9 @@ func ReadConfig(deploymentMode int) {
 	Configuration.CommitLimitMonthly = viper.GetInt64("rate_limiters.commit_limit_monthly")
 	Configuration.CommitLimitDaily = viper.GetInt64("rate_limiters.commit_limit_daily")
 	Configuration.CommitZeroLimitDaily = viper.GetInt64("rate_limiters.commit_zero_limit_daily")
+	Configuration.AllocationRPS = viper.GetFloat64("rate_limiters.allocation_rps")
+	Configuration.AllocationUploadBPS = viper.GetInt64("rate_limiters.allocation_upload_bps")
+	Configuration.AllocationDownloadBPS = viper.GetInt64("rate_limiters.allocation_download_bps")
 
 	Configuration.IsEnterprise = viper.GetBool("is_enterprise")
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
@@ -175,6 +176,23 @@ func ReadMakerGRPCToReadMaker(rm *blobbergrpc.ReadMarker) *readmarker.ReadMarker
 	}
 }
 
+func PlaylistFileToPlaylistFileGRPC(f *reference.PlaylistFile) *blobbergrpc.PlaylistFile {
+	if f == nil {
+		return nil
+	}
+
+	return &blobbergrpc.PlaylistFile{
+		LookupHash:  f.LookupHash,
+		Name:        f.Name,
+		Path:        f.Path,
+		NumOfBlocks: f.NumBlocks,
+		ParentPath:  f.ParentPath,
+		Size:        f.Size,
+		Mimetype:    f.MimeType,
+		Type:        f.Type,
+	}
+}
+
 func FileStatsGRPCToFileStats(fileStats *blobbergrpc.FileStats) *reference.FileStats {
 	if fileStats == nil {
 		return nil
@@ -444,3 +462,24 @@ func DownloadFileGRPCToHTTP(req *blobbergrpc.DownloadFileRequest) (*http.Request
 	r.Header.Set("X-Mode", req.Content)
 	return r, nil
 }
+
+// DownloadFileStreamGRPCToHTTP creates the download request of numBlocks
+// blocks of the stream starting at blockNum.
+func DownloadFileStreamGRPCToHTTP(req *blobbergrpc.DownloadFileStreamRequest, blockNum, numBlocks int64) (*http.Request, error) {
+	r, err := http.NewRequest("GET", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("X-Path", req.Path)
+	r.Header.Set("X-Path-Hash", req.PathHash)
+	r.Header.Set("X-Block-Num", strconv.FormatInt(blockNum, 10))
+	r.Header.Set("X-Num-Blocks", strconv.FormatInt(numBlocks, 10))
+	r.Header.Set("X-Connection-ID", req.ConnectionId)
+	r.Header.Set("X-Read-Marker", req.ReadMarker)
+	r.Header.Set("X-Auth-Token", req.AuthToken)
+	r.Header.Set("X-Mode", req.Content)
+	r.Header.Set("X-Verify-Download", strconv.FormatBool(req.VerifyDownload))
+	r.Header.Set("X-Version", req.Version)
+	return r, nil
+}
@@ -7,7 +7,9 @@ import (
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobberhttp"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
 )
 
 func GetAllocationResponseCreator(resp interface{}) *blobbergrpc.GetAllocationResponse {
@@ -219,3 +221,139 @@ func UploadFileResponseCreator(r interface{}) *blobbergrpc.UploadFileResponse {
 		UploadOffset:    httpResp.UploadOffset,
 	}
 }
+
+func RollbackResponseCreator(r interface{}) *blobbergrpc.RollbackResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*blobberhttp.CommitResult)
+	resp := &blobbergrpc.RollbackResponse{
+		AllocationRoot: httpResp.AllocationRoot,
+		ErrorMessage:   httpResp.ErrorMessage,
+		Success:        httpResp.Success,
+	}
+	if httpResp.WriteMarker != nil {
+		resp.WriteMarker = WriteMarkerToWriteMarkerGRPC(&httpResp.WriteMarker.WM)
+	}
+	return resp
+}
+
+func MoveObjectResponseCreator(r interface{}) *blobbergrpc.MoveObjectResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*allocation.UploadResult)
+	return &blobbergrpc.MoveObjectResponse{
+		Filename:        httpResp.Filename,
+		Size:            httpResp.Size,
+		ValidationRoot:  httpResp.ValidationRoot,
+		FixedMerkleRoot: httpResp.FixedMerkleRoot,
+		UploadLength:    httpResp.UploadLength,
+		UploadOffset:    httpResp.UploadOffset,
+	}
+}
+
+func DeleteFileResponseCreator(r interface{}) *blobbergrpc.DeleteFileResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*allocation.UploadResult)
+	return &blobbergrpc.DeleteFileResponse{
+		Filename:        httpResp.Filename,
+		Size:            httpResp.Size,
+		ValidationRoot:  httpResp.ValidationRoot,
+		FixedMerkleRoot: httpResp.FixedMerkleRoot,
+	}
+}
+
+func CreateDirResponseCreator(r interface{}) *blobbergrpc.CreateDirResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*allocation.UploadResult)
+	return &blobbergrpc.CreateDirResponse{Filename: httpResp.Filename}
+}
+
+func WriteMarkerLockResponseCreator(r interface{}) *blobbergrpc.WriteMarkerLockResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*writemarker.LockResult)
+	return &blobbergrpc.WriteMarkerLockResponse{
+		Status:    int32(httpResp.Status),
+		CreatedAt: httpResp.CreatedAt,
+	}
+}
+
+func InsertShareInfoResponseCreator(r interface{}) *blobbergrpc.InsertShareInfoResponse {
+	httpResp, _ := r.(map[string]interface{})
+	msg, _ := httpResp["message"].(string)
+
+	return &blobbergrpc.InsertShareInfoResponse{Message: msg}
+}
+
+func RevokeShareInfoResponseCreator(r interface{}) *blobbergrpc.RevokeShareInfoResponse {
+	httpResp, _ := r.(map[string]interface{})
+	status, _ := httpResp["status"].(int)
+	msg, _ := httpResp["message"].(string)
+
+	return &blobbergrpc.RevokeShareInfoResponse{Status: int32(status), Message: msg}
+}
+
+func GetPlaylistResponseCreator(r interface{}) *blobbergrpc.GetPlaylistResponse {
+	files, _ := r.([]reference.PlaylistFile)
+
+	resp := &blobbergrpc.GetPlaylistResponse{}
+	for i := range files {
+		resp.Files = append(resp.Files, PlaylistFileToPlaylistFileGRPC(&files[i]))
+	}
+	return resp
+}
+
+func GetPlaylistFileResponseCreator(r interface{}) *blobbergrpc.GetPlaylistFileResponse {
+	file, _ := r.(*reference.PlaylistFile)
+
+	return &blobbergrpc.GetPlaylistFileResponse{File: PlaylistFileToPlaylistFileGRPC(file)}
+}
+
+func RedeemReadMarkerResponseCreator(r interface{}) *blobbergrpc.RedeemReadMarkerResponse {
+	if r == nil {
+		return nil
+	}
+
+	httpResp, _ := r.(*blobberhttp.DownloadResponse)
+	return &blobbergrpc.RedeemReadMarkerResponse{
+		Success:  httpResp.Success,
+		LatestRm: ReadMarkerToReadMarkerGRPC(httpResp.LatestRM),
+	}
+}
+
+func DownloadFileStreamResponseCreator(r interface{}, blockNum, numBlocks int64) *blobbergrpc.DownloadFileStreamResponse {
+	resp := &blobbergrpc.DownloadFileStreamResponse{
+		BlockNum:  blockNum,
+		NumBlocks: numBlocks,
+	}
+
+	switch httpResp := r.(type) {
+	case []byte:
+		resp.Data = httpResp
+	case *filestore.FileDownloadResponse:
+		resp.Data = httpResp.Data
+		for i, nodes := range httpResp.Nodes {
+			proof := &blobbergrpc.ValidationProof{Nodes: nodes}
+			if i < len(httpResp.Indexes) {
+				for _, ind := range httpResp.Indexes[i] {
+					proof.Indexes = append(proof.Indexes, int64(ind))
+				}
+			}
+			resp.Proofs = append(resp.Proofs, proof)
+		}
+	}
+
+	return resp
+}
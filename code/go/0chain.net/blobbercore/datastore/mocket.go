@@ -86,8 +86,8 @@ func (store *Mocket) GetTransaction(ctx context.Context) *EnhancedDB {
 	return nil
 }
 
-func (store *Mocket) WithNewTransaction(f func(ctx context.Context) error) error {
-	ctx := store.CreateTransaction(context.TODO())
+func (store *Mocket) WithNewTransaction(f func(ctx context.Context) error, opts ...TxOption) error {
+	ctx := store.CreateTransaction(newTxContext(opts))
 	defer ctx.Done()
 
 	tx := store.GetTransaction(ctx)
@@ -9,6 +9,8 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -71,6 +73,9 @@ func (store *postgresStore) Open() error {
 	sqldb.SetMaxOpenConns(400)
 	sqldb.SetConnMaxLifetime(30 * time.Minute)
 	sqldb.SetConnMaxIdleTime(5 * time.Minute)
+	if err := metrics.RegisterDBStats(sqldb, config.Configuration.DBName); err != nil {
+		logging.Logger.Warn("db pool stats are not exported: " + err.Error())
+	}
 	// Enable Logger, show detailed log
 	//db.LogMode(true)
 	store.db = db
@@ -99,15 +104,18 @@ func (store *postgresStore) GetTransaction(ctx context.Context) *EnhancedDB {
 	return nil
 }
 
-func (store *postgresStore) WithNewTransaction(f func(ctx context.Context) error) error {
-	timeoutctx, cancel := context.WithTimeout(context.TODO(), 45*time.Second)
+func (store *postgresStore) WithNewTransaction(f func(ctx context.Context) error, opts ...TxOption) (err error) {
+	spanCtx, span := tracing.Start(newTxContext(opts), "datastore.WithNewTransaction")
+	defer func() { tracing.End(span, err) }()
+
+	timeoutctx, cancel := context.WithTimeout(spanCtx, 45*time.Second)
 	defer cancel()
 	ctx := store.CreateTransaction(timeoutctx)
 	tx := store.GetTransaction(ctx)
 	if tx.Error != nil {
 		return tx.Error
 	}
-	err := f(ctx)
+	err = f(ctx)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"gorm.io/gorm"
 )
 
@@ -45,7 +46,7 @@ type Store interface {
 	CreateTransaction(ctx context.Context, opts ...*sql.TxOptions) context.Context
 	// GetTransaction get transaction from context
 	GetTransaction(ctx context.Context) *EnhancedDB
-	WithNewTransaction(f func(ctx context.Context) error) error
+	WithNewTransaction(f func(ctx context.Context) error, opts ...TxOption) error
 	WithTransaction(ctx context.Context, f func(ctx context.Context) error) error
 	// Get db connection with user that creates roles and databases. Its dialactor does not contain database name
 	GetPgDB() (*gorm.DB, error)
@@ -53,6 +54,33 @@ type Store interface {
 	Close()
 }
 
+// TxOption configures a transaction started by WithNewTransaction.
+type TxOption func(*txOptions)
+
+type txOptions struct {
+	parent context.Context
+}
+
+// WithParent continues the trace of ctx in the transaction. The transaction is
+// bound to neither the deadline nor the cancellation of ctx.
+func WithParent(ctx context.Context) TxOption {
+	return func(o *txOptions) {
+		o.parent = ctx
+	}
+}
+
+// newTxContext returns the context a new transaction starts from.
+func newTxContext(opts []TxOption) context.Context {
+	var o txOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.parent == nil {
+		return context.TODO()
+	}
+	return tracing.Detach(o.parent)
+}
+
 var instance Store
 
 func init() {
@@ -20,6 +20,8 @@ type FileStore struct {
 	rwMU    *sync.RWMutex
 
 	diskCapacity uint64
+
+	proofCache *proofCache
 }
 
 var contentHashMapLock = common.GetNewLocker()
@@ -47,6 +49,7 @@ func (fs *FileStore) Initialize() (err error) {
 	fs.allocMu = &sync.Mutex{}
 	fs.rwMU = &sync.RWMutex{}
 	fs.mAllocs = make(map[string]*allocation)
+	fs.proofCache = newProofCache()
 
 	if err = fs.initMap(); err != nil {
 		return
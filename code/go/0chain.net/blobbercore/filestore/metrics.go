@@ -0,0 +1,30 @@
+package filestore
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterMetrics exposes the disk usage of the file store.
+func RegisterMetrics(fs FileStorer) error {
+	usage := func(kind string, f func() uint64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "blobber_disk_usage_bytes",
+			Help:        "Size of the temporary and the committed files of the allocations.",
+			ConstLabels: prometheus.Labels{"kind": kind},
+		}, func() float64 { return float64(f()) })
+	}
+	collectors := []prometheus.Collector{
+		usage("temp", fs.GetTotalTempFileSizes),
+		usage("committed", fs.GetTotalCommittedFileSize),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "blobber_disk_capacity_bytes",
+			Help: "Capacity of the volume of the file store.",
+		}, func() float64 { return float64(fs.GetCurrentDiskCapacity()) }),
+	}
+	for _, c := range collectors {
+		if err := prometheus.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
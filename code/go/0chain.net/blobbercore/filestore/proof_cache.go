@@ -0,0 +1,56 @@
+package filestore
+
+import (
+	"fmt"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/cache"
+)
+
+// proofCache keeps the fixed merkle tree nodes of recently committed and
+// challenged files, so that a challenge proof only reads the leaf content of
+// the file from disk. The nodes of a file only depend on its content, so an
+// entry stays valid until the file is deleted.
+type proofCache struct {
+	lru *cache.LRU
+}
+
+func newProofCache() *proofCache {
+	if !config.Configuration.ProofCacheEnabled || config.Configuration.ProofCacheMaxSizeMB <= 0 {
+		return nil
+	}
+	size := int(config.Configuration.ProofCacheMaxSizeMB * MB / FMTSize)
+	if size == 0 {
+		size = 1
+	}
+	return &proofCache{lru: cache.NewLRUCache(size)}
+}
+
+func proofCacheKey(allocID, hash string, version int) string {
+	return fmt.Sprintf("%s:%s:%d", allocID, hash, version)
+}
+
+func (pc *proofCache) get(allocID, hash string, version int) []byte {
+	if pc == nil {
+		return nil
+	}
+	v, err := pc.lru.Get(proofCacheKey(allocID, hash, version))
+	if err != nil {
+		return nil
+	}
+	return v.([]byte)
+}
+
+func (pc *proofCache) add(allocID, hash string, version int, nodes []byte) {
+	if pc == nil || len(nodes) != FMTSize {
+		return
+	}
+	_ = pc.lru.Add(proofCacheKey(allocID, hash, version), nodes)
+}
+
+func (pc *proofCache) delete(allocID, hash string, version int) {
+	if pc == nil {
+		return
+	}
+	_ = pc.lru.Delete(proofCacheKey(allocID, hash, version))
+}
@@ -177,6 +177,7 @@ func (fs *FileStore) DeleteFromFilestore(allocID, hash string, version int) erro
 	if err != nil {
 		return common.NewError("blob_object_dir_creation_error", err.Error())
 	}
+	fs.proofCache.delete(allocID, hash, version)
 	fs.incrDecrAllocFileSizeAndNumber(allocID, -stat.Size(), -1)
 
 	return nil
@@ -282,7 +283,15 @@ func (fs *FileStore) CommitWrite(allocID, conID string, fileData *FileInputData)
 	if err != nil {
 		return false, common.NewError("seek_error", err.Error())
 	}
-	fmtRootBytes, err := fileData.Hasher.fmt.CalculateRootAndStoreNodes(r)
+	var (
+		fmtNodes  *bytes.Buffer
+		fmtWriter io.Writer = r
+	)
+	if fs.proofCache != nil {
+		fmtNodes = bytes.NewBuffer(make([]byte, 0, FMTSize))
+		fmtWriter = io.MultiWriter(r, fmtNodes)
+	}
+	fmtRootBytes, err := fileData.Hasher.fmt.CalculateRootAndStoreNodes(fmtWriter)
 	if err != nil {
 		return false, common.NewError("fmt_hash_calculation_error", err.Error())
 	}
@@ -312,6 +321,10 @@ func (fs *FileStore) CommitWrite(allocID, conID string, fileData *FileInputData)
 
 	l.Unlock()
 
+	if fmtNodes != nil {
+		fs.proofCache.add(allocID, fileData.ValidationRoot, VERSION, fmtNodes.Bytes())
+	}
+
 	fs.updateAllocTempFileSize(allocID, -fileSize)
 	// Each commit write should add 1 to file number because of the following:
 	// 1. NewFile: Obvioulsy needs to increment by 1
@@ -649,10 +662,18 @@ func (fs *FileStore) GetBlocksMerkleTreeForChallenge(in *ChallengeReadBlockInput
 		offset:   offset,
 	}
 
-	merkleProof, err := fmp.GetMerkleProof(file)
-	if err != nil {
-		return nil, common.NewError("get_merkle_proof_error", err.Error())
+	var nodes []byte
+	if !in.SkipCache {
+		nodes = fs.proofCache.get(in.AllocationID, in.Hash, in.FilestoreVersion)
+	}
+	if nodes == nil {
+		nodes, err = fmp.readNodes(file)
+		if err != nil {
+			return nil, common.NewError("get_merkle_proof_error", err.Error())
+		}
+		fs.proofCache.add(in.AllocationID, in.Hash, in.FilestoreVersion, nodes)
 	}
+	merkleProof := fmp.getMerkleProofFromNodes(nodes)
 
 	if in.FilestoreVersion == 0 {
 		_, err = file.Seek(-in.FileSize, io.SeekEnd)
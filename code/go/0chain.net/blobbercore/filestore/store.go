@@ -122,4 +122,6 @@ type ChallengeReadBlockInput struct {
 	AllocationID     string
 	IsPrecommit      bool
 	FilestoreVersion int
+	// SkipCache reads the fixed merkle tree nodes from disk even if they are cached.
+	SkipCache bool
 }
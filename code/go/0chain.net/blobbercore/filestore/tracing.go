@@ -0,0 +1,80 @@
+package filestore
+
+import (
+	"context"
+	"mime/multipart"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracedStore traces the writes, the commits, the moves and the block reads
+// of the file store as part of the trace of ctx. The writes and the block reads
+// stop once ctx is done.
+type tracedStore struct {
+	FileStorer
+	ctx context.Context
+}
+
+// WithContext returns the file store, tracing its operations as part of the
+// trace of ctx and giving up its writes and block reads once ctx is done.
+func WithContext(ctx context.Context) FileStorer {
+	return &tracedStore{FileStorer: GetFileStore(), ctx: ctx}
+}
+
+func (ts *tracedStore) WriteFile(allocID, connID string, fileData *FileInputData, infile multipart.File) (out *FileOutputData, err error) {
+	_, span := tracing.Start(ts.ctx, "filestore.WriteFile",
+		attribute.String("allocation_id", allocID),
+		attribute.String("connection_id", connID),
+		attribute.Int64("upload_offset", fileData.UploadOffset),
+		attribute.Bool("thumbnail", fileData.IsThumbnail))
+	defer func() { tracing.End(span, err) }()
+	if err = ts.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ts.FileStorer.WriteFile(allocID, connID, fileData, ctxFile{File: infile, ctx: ts.ctx})
+}
+
+func (ts *tracedStore) CommitWrite(allocID, connID string, fileData *FileInputData) (ok bool, err error) {
+	_, span := tracing.Start(ts.ctx, "filestore.CommitWrite",
+		attribute.String("allocation_id", allocID),
+		attribute.String("connection_id", connID),
+		attribute.Int64("size", fileData.Size),
+		attribute.Bool("thumbnail", fileData.IsThumbnail))
+	defer func() { tracing.End(span, err) }()
+	return ts.FileStorer.CommitWrite(allocID, connID, fileData)
+}
+
+func (ts *tracedStore) MoveToFilestore(allocID, hash string, version int) (err error) {
+	_, span := tracing.Start(ts.ctx, "filestore.MoveToFilestore",
+		attribute.String("allocation_id", allocID),
+		attribute.String("hash", hash))
+	defer func() { tracing.End(span, err) }()
+	return ts.FileStorer.MoveToFilestore(allocID, hash, version)
+}
+
+func (ts *tracedStore) GetFileBlock(readBlockIn *ReadBlockInput) (resp *FileDownloadResponse, err error) {
+	_, span := tracing.Start(ts.ctx, "filestore.GetFileBlock",
+		attribute.String("allocation_id", readBlockIn.AllocationID),
+		attribute.Int("block_num", readBlockIn.StartBlockNum),
+		attribute.Int("num_blocks", readBlockIn.NumBlocks),
+		attribute.Bool("verify", readBlockIn.VerifyDownload))
+	defer func() { tracing.End(span, err) }()
+	if err = ts.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ts.FileStorer.GetFileBlock(readBlockIn)
+}
+
+// ctxFile stops reading the file once ctx is done.
+type ctxFile struct {
+	multipart.File
+	ctx context.Context
+}
+
+func (f ctxFile) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.File.Read(p)
+}
@@ -140,9 +140,15 @@ func (fp *fixedMerkleTreeProof) CalculateLeafContentLevelForIndex() int {
 
 // GetMerkleProof is used to get merkle proof of leaf or index to be specific.
 func (fp fixedMerkleTreeProof) GetMerkleProof(r io.ReaderAt) (proof [][]byte, err error) {
-	var levelOffset int
-	totalLevelNodes := util.FixedMerkleLeaves
-	proof = make([][]byte, util.FixedMTDepth-1)
+	b, err := fp.readNodes(r)
+	if err != nil {
+		return nil, err
+	}
+	return fp.getMerkleProofFromNodes(b), nil
+}
+
+// readNodes reads the tree nodes of the fixed merkle tree, excluding the root node.
+func (fp fixedMerkleTreeProof) readNodes(r io.ReaderAt) ([]byte, error) {
 	b := make([]byte, FMTSize)
 	n, err := r.ReadAt(b, fp.offset)
 	if n != FMTSize {
@@ -151,6 +157,15 @@ func (fp fixedMerkleTreeProof) GetMerkleProof(r io.ReaderAt) (proof [][]byte, er
 	if err != nil {
 		return nil, err
 	}
+	return b, nil
+}
+
+// getMerkleProofFromNodes gets the merkle proof of the index from the tree nodes
+// read by readNodes.
+func (fp fixedMerkleTreeProof) getMerkleProofFromNodes(b []byte) (proof [][]byte) {
+	var levelOffset int
+	totalLevelNodes := util.FixedMerkleLeaves
+	proof = make([][]byte, util.FixedMTDepth-1)
 
 	var offset int
 	idx := fp.idx
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/admintoken"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+)
+
+// AuthenticateAdmin lets the calls to the admin api through when they carry an
+// admin token granting scope, as "Authorization: Bearer <token>". The calls
+// let through are recorded in the audit log with the name of their token; the
+// refused ones are only logged, as anyone could flood the audit log with them.
+func AuthenticateAdmin(scope string, handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret, ok := bearerToken(r)
+		if !ok {
+			http.Error(w, "Admin only api, an admin token is required", http.StatusUnauthorized)
+			return
+		}
+
+		token, err := admintoken.Authenticate(r.Context(), secret, scope)
+		switch {
+		case errors.Is(err, admintoken.ErrInvalidToken):
+			Logger.Warn("[admin]invalid token", zap.String("path", r.URL.Path))
+			http.Error(w, "Invalid, expired or revoked admin token", http.StatusUnauthorized)
+			return
+		case errors.Is(err, admintoken.ErrScope):
+			Logger.Warn("[admin]token out of scope", zap.String("token", token.Name),
+				zap.String("scope", scope), zap.String("path", r.URL.Path))
+			http.Error(w, "Admin token lacks the "+scope+" scope", http.StatusForbidden)
+			return
+		case err != nil:
+			Logger.Error("[admin]authenticate", zap.Error(err))
+			http.Error(w, "Could not check the admin token", http.StatusInternalServerError)
+			return
+		}
+
+		e := &auditlog.Entry{
+			Operation:    auditlog.OpAdmin,
+			ClientID:     token.Name,
+			IP:           remoteIP(r.Context(), r),
+			AllocationID: mux.Vars(r)["allocation"],
+			Message:      r.Method + " " + r.URL.Path + " (" + scope + ")",
+		}
+		defer auditlog.RecordSync(r.Context(), e)
+
+		aw := &auditWriter{ResponseWriter: w, code: http.StatusOK}
+		handler(aw, r)
+		if aw.code >= http.StatusBadRequest {
+			e.Result = auditlog.ResultFailure
+		} else {
+			e.Result = auditlog.ResultSuccess
+		}
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(auth[len(prefix):]), true
+}
+
+type auditWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (aw *auditWriter) WriteHeader(code int) {
+	if !aw.wroteHeader {
+		aw.code, aw.wroteHeader = code, true
+	}
+	aw.ResponseWriter.WriteHeader(code)
+}
+
+func (aw *auditWriter) Write(b []byte) (int, error) {
+	aw.wroteHeader = true
+	return aw.ResponseWriter.Write(b)
+}
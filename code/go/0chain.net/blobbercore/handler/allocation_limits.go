@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/ratelimit"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+)
+
+// allocationKey returns the allocation the limits of the request are keyed by,
+// the allocation of the route resolved to its id. The id of the header only
+// helps finding the allocation in the cache, so made up ids cannot dodge the
+// limits. The requests to allocations unknown to the blobber are only under
+// the limits of the blobber.
+func allocationKey(r *http.Request) string {
+	idOrTx := mux.Vars(r)["allocation"]
+	if idOrTx == "" {
+		return ""
+	}
+	id, err := allocation.Repo.GetIDByIdOrTx(r.Context(), r.Header.Get(common.AllocationIdHeader), idOrTx)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			Logger.Error("[ratelimit]resolve allocation", zap.String("allocation", idOrTx), zap.Error(err))
+		}
+		return ""
+	}
+	return id
+}
+
+type allocationKeyCtx struct{}
+
+// withAllocationKey resolves the allocation of the request once, for its
+// request limit and the shaping of its transfer. Nothing is resolved while no
+// allocation has limits.
+func withAllocationKey(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(allocationKeyCtx{}).(string); ok || !ratelimit.Limited() {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), allocationKeyCtx{}, allocationKey(r)))
+}
+
+// requestAllocation is the allocation resolved by withAllocationKey, empty
+// when the request is not under the limits of an allocation.
+func requestAllocation(r *http.Request) string {
+	id, _ := r.Context().Value(allocationKeyCtx{}).(string)
+	return id
+}
+
+// withAllocationLimit rejects the requests of a client over the requests per
+// second of their allocation. The limit is kept by client, as the requests are
+// counted before they are authenticated.
+func withAllocationLimit(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = withAllocationKey(r)
+		if !ratelimit.Allow(requestAllocation(r), r.Header.Get(common.ClientHeader)) {
+			http.Error(w, "Allocation request rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// ShapeUpload holds back the upload read from the body of the request to the
+// upload bandwidth of its allocation.
+func ShapeUpload(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = withAllocationKey(r)
+		if r.Body != nil {
+			r.Body = ratelimit.UploadReader(r.Context(), requestAllocation(r), r.Body)
+		}
+		handler(w, r)
+	}
+}
+
+// ShapeDownload holds back the download written to the response to the
+// download bandwidth of its allocation.
+func ShapeDownload(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = withAllocationKey(r)
+		handler(ratelimit.DownloadWriter(r.Context(), requestAllocation(r), w), r)
+	}
+}
+
+// AllocationRateLimits are the limits of the allocations of the blobber.
+// swagger:model AllocationRateLimits
+type AllocationRateLimits struct {
+	// Defaults are the limits of the config.
+	Defaults  ratelimit.Limits      `json:"defaults"`
+	Overrides []*ratelimit.Override `json:"overrides"`
+	// Counters are the requests rejected and the transfers slowed down by
+	// active allocation.
+	Counters []*ratelimit.Counters `json:"counters"`
+}
+
+// swagger:route GET /_allocations/limits GetAllocationRateLimits
+// Get allocation rate limits.
+//
+// Lists the limits of the config, the overrides of the allocations and the requests rejected and the transfers slowed down by allocation, for the allocations with requests in the last minutes.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//
+// responses:
+//
+//	200: AllocationRateLimits
+func GetAllocationRateLimits(ctx context.Context, r *http.Request) (interface{}, error) {
+	return &AllocationRateLimits{
+		Defaults:  ratelimit.GetLimits(""),
+		Overrides: ratelimit.GetOverrides(),
+		Counters:  ratelimit.GetCounters(),
+	}, nil
+}
+
+// swagger:route POST /_allocations/{allocation}/limits SetAllocationRateLimit
+// Override the limits of an allocation.
+//
+// The limits not given are the ones of the config, a limit of 0 is no limit. An override replaces the previous override of the allocation.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: allocation
+//	  in: path
+//	  type: string
+//	  required: true
+//	  description: Allocation id
+//	+name: rps
+//	  in: query
+//	  type: number
+//	  required: false
+//	  description: Requests per second of each client to the allocation
+//	+name: upload_bps
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Upload bandwidth of the allocation in bytes per second
+//	+name: download_bps
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Download bandwidth of the allocation in bytes per second
+//	+name: reason
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Why the limits of the allocation are overridden
+//
+// responses:
+//
+//	200: AllocationRateLimit
+func SetAllocationRateLimit(ctx context.Context, r *http.Request) (interface{}, error) {
+	o := &ratelimit.Override{
+		AllocationID: mux.Vars(r)["allocation"],
+		Reason:       r.URL.Query().Get("reason"),
+	}
+	if o.Reason == "" {
+		return nil, common.NewError("invalid_parameters", "reason parameter is required")
+	}
+
+	set := false
+	if value := r.URL.Query().Get("rps"); value != "" {
+		rps, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, common.NewError("invalid_parameters", "rps parameter is not valid")
+		}
+		o.RPS, set = &rps, true
+	}
+	for name, limit := range map[string]**int64{
+		"upload_bps":   &o.UploadBPS,
+		"download_bps": &o.DownloadBPS,
+	} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, common.NewErrorf("invalid_parameters", "%s parameter is not valid", name)
+		}
+		*limit, set = &v, true
+	}
+	if !set {
+		return nil, common.NewError("invalid_parameters", "at least one limit is required")
+	}
+
+	if err := ratelimit.SetOverride(ctx, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// swagger:route DELETE /_allocations/{allocation}/limits DeleteAllocationRateLimit
+// Restore the limits of the config for an allocation.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: allocation
+//	  in: path
+//	  type: string
+//	  required: true
+//	  description: Allocation id
+//
+// responses:
+//
+//	200:
+//	400:
+func DeleteAllocationRateLimit(ctx context.Context, r *http.Request) (interface{}, error) {
+	if err := ratelimit.DeleteOverride(ctx, mux.Vars(r)["allocation"]); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"message": "Allocation rate limit override removed"}, nil
+}
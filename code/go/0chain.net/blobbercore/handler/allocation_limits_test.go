@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/gorilla/mux"
+	gomocket "github.com/selvatico/go-mocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocationKey(t *testing.T) {
+	datastore.UseMocket(false)
+	gomocket.Catcher.Reset()
+	gomocket.Catcher.NewMock().
+		WithQuery(`SELECT "id" FROM "allocations" WHERE allocations.id = $1 OR allocations.tx = $2`).
+		WithArgs("known_tx", "known_tx").
+		WithReply([]map[string]interface{}{{"id": "known_alloc"}})
+
+	key := func(route, header string) string {
+		r := httptest.NewRequest(http.MethodGet, "/v1/file/download/"+route, nil)
+		r = mux.SetURLVars(r, map[string]string{"allocation": route})
+		if header != "" {
+			r.Header.Set(common.AllocationIdHeader, header)
+		}
+		return allocationKey(r)
+	}
+
+	require.Equal(t, "known_alloc", key("known_tx", ""))
+	// the limits are keyed by the allocation of the route, whatever the header
+	require.Equal(t, "known_alloc", key("known_tx", "made_up"))
+	require.Equal(t, "", key("unknown_tx", "made_up"))
+}
+
+func TestWithAllocationKey(t *testing.T) {
+	datastore.UseMocket(false)
+	gomocket.Catcher.Reset()
+	gomocket.Catcher.NewMock().
+		WithQuery(`SELECT "id" FROM "allocations" WHERE allocations.id = $1 OR allocations.tx = $2`).
+		WithReply([]map[string]interface{}{{"id": "known_alloc"}}).
+		OneTime()
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/file/download/known_tx", nil)
+	r = mux.SetURLVars(r, map[string]string{"allocation": "known_tx"})
+
+	// no allocation is looked up while no allocation has limits
+	require.Equal(t, "", requestAllocation(withAllocationKey(r)))
+
+	config.Configuration.AllocationRPS = 1
+	defer func() { config.Configuration.AllocationRPS = 0 }()
+
+	// the allocation is looked up once for the limit and the shapers
+	r = withAllocationKey(r)
+	require.Equal(t, "known_alloc", requestAllocation(r))
+	require.Equal(t, "known_alloc", requestAllocation(withAllocationKey(r)))
+}
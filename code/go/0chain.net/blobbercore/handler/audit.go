@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/didip/tollbooth/v6/libstring"
+	"go.uber.org/zap"
+
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+)
+
+// remoteIP is the address of the client of the request, behind the proxy when
+// the rate limiters are configured for one.
+func remoteIP(ctx context.Context, r *http.Request) string {
+	if r.RemoteAddr == "" {
+		// request built from a gRPC call
+		return grpcRemoteIP(ctx)
+	}
+	if generalRL != nil {
+		if ip := libstring.RemoteIP(generalRL.GetIPLookups(), generalRL.GetForwardedForIndexFromBehind(), r); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newAuditEntry(ctx context.Context, op string, r *http.Request) *auditlog.Entry {
+	return &auditlog.Entry{
+		Operation:    op,
+		ClientID:     r.Header.Get(common.ClientHeader),
+		IP:           remoteIP(ctx, r),
+		AllocationID: r.Header.Get(common.AllocationIdHeader),
+	}
+}
+
+// recordAudit records the entry of the operation, before returning for the
+// operations which cannot be undone.
+func recordAudit(ctx context.Context, e *auditlog.Entry) {
+	switch e.Operation {
+	case auditlog.OpRollback, auditlog.OpShareRevoke:
+		auditlog.RecordSync(ctx, e)
+	default:
+		auditlog.Record(ctx, e)
+	}
+}
+
+// withStatusAudit records the operation of the handler in the audit log, once
+// the handler and its transaction are over.
+func withStatusAudit(op string, handler common.StatusCodeResponderF) common.StatusCodeResponderF {
+	return func(ctx context.Context, r *http.Request) (interface{}, int, error) {
+		e := newAuditEntry(ctx, op, r)
+		resp, statusCode, err := handler(auditlog.WithEntry(ctx, e), r)
+		e.SetError(err)
+		recordAudit(ctx, e)
+		return resp, statusCode, err
+	}
+}
+
+// withAudit records the operation of the handler in the audit log, once the
+// handler and its transaction are over.
+func withAudit(op string, handler common.JSONResponderF) common.JSONResponderF {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		e := newAuditEntry(ctx, op, r)
+		resp, err := handler(auditlog.WithEntry(ctx, e), r)
+		e.SetError(err)
+		recordAudit(ctx, e)
+		return resp, err
+	}
+}
+
+func auditFilter(r *http.Request) (auditlog.Filter, error) {
+	query := r.URL.Query()
+	f := auditlog.Filter{
+		AllocationID: query.Get("allocation_id"),
+		ClientID:     query.Get("client_id"),
+		Operation:    query.Get("operation"),
+	}
+
+	if f.Operation != "" {
+		valid := false
+		for _, op := range auditlog.Operations {
+			if op == f.Operation {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return f, common.NewError("invalid_parameters", "operation parameter is not valid")
+		}
+	}
+
+	for name, t := range map[string]*common.Timestamp{"from": &f.From, "to": &f.To} {
+		if v := query.Get(name); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || ts < 0 {
+				return f, common.NewErrorf("invalid_parameters", "%s parameter is not a valid timestamp", name)
+			}
+			*t = common.Timestamp(ts)
+		}
+	}
+	return f, nil
+}
+
+// swagger:route GET /_audit_log GetAuditLog
+// Get audit log.
+//
+// Retrieve the entries of the audit log of the blobber: commits, rollbacks, shares, auth tickets, admin calls and config reloads.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//	+name: allocation_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this allocation
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this client
+//	+name: operation
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this operation, one of commit, rollback, share_insert, share_revoke, auth_ticket, admin or config_reload
+//	+name: from
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Only the entries recorded at or after this unix timestamp
+//	+name: to
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Only the entries recorded at or before this unix timestamp
+//	+name: offset
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination offset, start of the page to retrieve. Default is 0.
+//	+name: limit
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination limit, number of entries in the page to retrieve. Default is 20.
+//	+name: sort
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Direction of sorting based on the order of recording, either "asc" or "desc". Default is "asc"
+//
+// responses:
+//
+//	200: []AuditLogEntry
+func GetAuditLog(ctx context.Context, r *http.Request) (interface{}, error) {
+	f, err := auditFilter(r)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := common.GetOffsetLimitOrderParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return auditlog.GetEntries(f, limit)
+}
+
+// swagger:route GET /_audit_log/verify VerifyAuditLog
+// Verify audit log.
+//
+// Check the hash chain of the whole audit log and report the entries changed or removed.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//
+// responses:
+//
+//	200: AuditLogVerifyReport
+func VerifyAuditLog(ctx context.Context, r *http.Request) (interface{}, error) {
+	return auditlog.Verify()
+}
+
+// swagger:route GET /_audit_log/export ExportAuditLog
+// Export audit log.
+//
+// Download the entries of the audit log, with their hashes, as JSON lines or CSV.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//	+name: format
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Format of the export, either "jsonl" or "csv". Default is "jsonl"
+//	+name: allocation_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this allocation
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this client
+//	+name: operation
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the entries of this operation
+//	+name: from
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Only the entries recorded at or after this unix timestamp
+//	+name: to
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Only the entries recorded at or before this unix timestamp
+//
+// responses:
+//
+//	200:
+//	400:
+func ExportAuditLog(w http.ResponseWriter, r *http.Request) {
+	f, err := auditFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	contentType := "application/x-ndjson"
+	switch format {
+	case "", auditlog.FormatJSONL:
+		format = auditlog.FormatJSONL
+	case auditlog.FormatCSV:
+		contentType = "text/csv"
+	default:
+		http.Error(w, "format parameter is not valid", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment; filename=audit_log."+format)
+	if err := auditlog.Export(w, f, format); err != nil {
+		// the export may have been partly written already
+		Logger.Error("[auditlog]export", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
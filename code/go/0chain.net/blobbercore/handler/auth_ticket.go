@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
 	"github.com/0chain/common/core/common"
 	"net/http"
@@ -35,6 +36,8 @@ func GenerateAuthTicket(ctx context.Context, r *http.Request) (interface{}, erro
 	if clientID == "" {
 		return nil, common.NewError("missing_client_id", "client_id is required")
 	}
+	// the ticket is issued on behalf of the client, not the caller
+	auditlog.FromContext(ctx).ClientID = clientID
 
 	signature, err := node.Self.Sign(clientID)
 	if err != nil {
@@ -3,13 +3,16 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"regexp"
+	"net/http"
+	"strconv"
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/gosdk/constants"
+	"go.uber.org/zap"
 )
 
 // verifyAuthTicket verifies authTicket and returns authToken and error if any. For any error authToken is nil
@@ -33,7 +36,7 @@ func verifyAuthTicket(ctx context.Context, authTokenString string, allocationObj
 			return nil, err
 		}
 
-		if matched, _ := regexp.MatchString(fmt.Sprintf("^%v", authTokenRef.Path), refRequested.Path); !matched {
+		if !readmarker.CoversPath(authTokenRef.Path, refRequested.Path) {
 			return nil, common.NewError("invalid_parameters", "Auth ticket is not valid for the resource being requested")
 		}
 	}
@@ -55,3 +58,188 @@ func verifyAuthTicket(ctx context.Context, authTokenString string, allocationObj
 
 	return authToken, nil
 }
+
+// verifyOwnerRequest checks that the request is signed by the owner of the allocation.
+func verifyOwnerRequest(ctx context.Context, r *http.Request) (*allocation.Allocation, error) {
+	var (
+		allocationTx = ctx.Value(constants.ContextKeyAllocation).(string)
+		allocationID = ctx.Value(constants.ContextKeyAllocationID).(string)
+		clientID     = ctx.Value(constants.ContextKeyClient).(string)
+	)
+
+	allocationObj, err := storageHandler.verifyAllocation(ctx, allocationID, allocationTx, true)
+	if err != nil {
+		return nil, common.NewError("invalid_parameters", "Invalid allocation id passed."+err.Error())
+	}
+
+	sign := r.Header.Get(common.ClientSignatureHeader)
+	signV2 := r.Header.Get(common.ClientSignatureHeaderV2)
+	valid, err := verifySignatureFromRequest(allocationTx, sign, signV2, allocationObj.OwnerPublicKey)
+	if !valid || err != nil {
+		return nil, common.NewError("invalid_signature", "Invalid signature")
+	}
+
+	if clientID != allocationObj.OwnerID {
+		return nil, common.NewError("invalid_operation", "Operation needs to be performed by the owner of the allocation")
+	}
+	return allocationObj, nil
+}
+
+// swagger:route GET /v1/auth/tickets/{allocation} ListAuthTickets
+// List auth tickets.
+//
+// Lists the auth tickets of the allocation used on this blobber with their usage and limits.
+// Only the owner of the allocation can list them.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: TxHash of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: all
+//	  in: query
+//	  type: boolean
+//	  required: false
+//	  description: Also list revoked and expired tickets.
+//	+name: offset
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination offset, start of the page to retrieve. Default is 0.
+//	+name: limit
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination limit, number of entries in the page to retrieve. Default is 20.
+//	+name: sort
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Direction of sorting based on the first use of the ticket, either "asc" or "desc". Default is "asc"
+//
+// responses:
+//
+//	200: []AuthTicketRecord
+//	400:
+func ListAuthTickets(ctx context.Context, r *http.Request) (interface{}, error) {
+	ctx = setupHandlerContext(ctx, r)
+
+	limit, err := common.GetOffsetLimitOrderParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	allocationObj, err := verifyOwnerRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	tickets, err := readmarker.ListAuthTickets(ctx, allocationObj.ID, all, limit)
+	if err != nil {
+		Logger.Error("failed_to_list_auth_tickets", zap.Error(err))
+		return nil, common.NewError("failed_to_list_auth_tickets", "failed to list auth tickets")
+	}
+	return tickets, nil
+}
+
+// swagger:route POST /v1/auth/tickets/revoke/{allocation} RevokeAuthTickets
+// Revoke auth tickets.
+//
+// Adds the given auth tickets to the revocation list of the allocation. The ticket hash
+// is the hash of the signed data of the ticket. Only the owner of the allocation can revoke tickets.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: TxHash of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: ticket_hashes
+//	  description: JSON array of the hashes of the tickets to revoke.
+//	  in: formData
+//	  type: string
+//	  required: true
+//
+// responses:
+//
+//	200:
+//	400:
+func RevokeAuthTickets(ctx context.Context, r *http.Request) (interface{}, error) {
+	ctx = setupHandlerContext(ctx, r)
+
+	allocationObj, err := verifyOwnerRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	hashesString, _ := common.GetField(r, "ticket_hashes")
+	var hashes []string
+	if err := json.Unmarshal([]byte(hashesString), &hashes); err != nil || len(hashes) == 0 {
+		return nil, common.NewError("invalid_parameters", "ticket_hashes must be a non empty JSON array")
+	}
+	for _, h := range hashes {
+		if len(h) != 64 {
+			return nil, common.NewErrorf("invalid_parameters", "invalid ticket hash: %v", h)
+		}
+	}
+
+	if err := readmarker.RevokeAuthTickets(ctx, allocationObj.ID, allocationObj.OwnerID, hashes); err != nil {
+		Logger.Error("failed_to_revoke_auth_tickets", zap.Error(err))
+		return nil, common.NewError("failed_to_revoke_auth_tickets", "failed to revoke auth tickets")
+	}
+
+	return map[string]interface{}{
+		"status":  http.StatusOK,
+		"revoked": len(hashes),
+	}, nil
+}
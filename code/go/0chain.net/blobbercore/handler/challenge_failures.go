@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+// swagger:route GET /_challenge_failures GetChallengeFailures
+// Get challenge failures.
+//
+// Retrieve the root causes of the failed and cancelled challenges, with the evidence gathered, for the blobber admin.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//	+name: allocation_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the failures of the challenges of this allocation
+//	+name: category
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Only the failures of this root cause, one of missing_ref, merkle_mismatch, validator_unreachable, txn_submission_failure, deadline_missed, chain_verify_failure, internal_error or unknown
+//	+name: offset
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination offset, start of the page to retrieve. Default is 0.
+//	+name: limit
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination limit, number of entries in the page to retrieve. Default is 20.
+//	+name: sort
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Direction of sorting based on failure time, either "asc" or "desc". Default is "asc"
+//
+// responses:
+//
+//	200: []FailureDiagnosis
+func GetChallengeFailures(ctx context.Context, r *http.Request) (interface{}, error) {
+	var (
+		allocationID = r.URL.Query().Get("allocation_id")
+		category     = r.URL.Query().Get("category")
+	)
+
+	if category != "" {
+		valid := false
+		for _, c := range challenge.FailureCategories {
+			if c == category {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, common.NewError("invalid_parameters", "category parameter is not valid")
+		}
+	}
+
+	limit, err := common.GetOffsetLimitOrderParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge.GetChallengeFailures(allocationID, category, limit)
+}
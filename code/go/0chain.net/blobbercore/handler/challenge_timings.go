@@ -19,7 +19,7 @@ import (
 //     in: header
 //     type: string
 //     required: true
-//     description: Authorization header (Basic auth). MUST be provided to fulfil the request
+//     description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
 //   +name: from
 //     in: query
 //     type: integer
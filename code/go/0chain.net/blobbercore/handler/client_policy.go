@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+// Subjects of the access rules.
+const (
+	SubjectClient = "client"
+	SubjectIP     = "ip"
+)
+
+// Actions of the access rules.
+const (
+	AccessBlock = "block"
+	AccessAllow = "allow"
+)
+
+// AccessRule is a client or an IP blocked or allowed by the operator of the
+// blobber. It takes priority over the blacklist computed from the client stats.
+// swagger:model AccessRule
+type AccessRule struct {
+	SubjectType string `gorm:"column:subject_type;size:8;primaryKey" json:"subject_type"`
+	Subject     string `gorm:"column:subject;size:64;primaryKey" json:"subject"`
+	Action      string `gorm:"column:action;size:8;not null" json:"action"`
+	Reason      string `gorm:"column:reason" json:"reason"`
+	// ExpiresAt is zero for a rule which does not expire.
+	ExpiresAt common.Timestamp `gorm:"column:expires_at;not null;default:0" json:"expires_at,omitempty"`
+	CreatedAt common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (AccessRule) TableName() string {
+	return "client_access_rules"
+}
+
+// QuotaOverride replaces the limits of the config for a client. A limit not set
+// is the limit of the config.
+// swagger:model QuotaOverride
+type QuotaOverride struct {
+	ClientID           string `gorm:"column:client_id;size:64;primaryKey" json:"client_id"`
+	UploadLimitMonthly *int64 `gorm:"column:upload_limit_monthly" json:"upload_limit_monthly,omitempty"`
+	BlockLimitDaily    *int64 `gorm:"column:block_limit_daily" json:"block_limit_daily,omitempty"`
+	BlockLimitMonthly  *int64 `gorm:"column:block_limit_monthly" json:"block_limit_monthly,omitempty"`
+	CommitLimitDaily   *int64 `gorm:"column:commit_limit_daily" json:"commit_limit_daily,omitempty"`
+	CommitLimitMonthly *int64 `gorm:"column:commit_limit_monthly" json:"commit_limit_monthly,omitempty"`
+	Reason             string `gorm:"column:reason" json:"reason"`
+	// ExpiresAt is zero for an override which does not expire.
+	ExpiresAt common.Timestamp `gorm:"column:expires_at;not null;default:0" json:"expires_at,omitempty"`
+	CreatedAt common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (QuotaOverride) TableName() string {
+	return "client_quota_overrides"
+}
+
+// ClientLimits are the limits enforced on a client.
+// swagger:model ClientLimits
+type ClientLimits struct {
+	UploadLimitMonthly int64 `json:"upload_limit_monthly"`
+	BlockLimitDaily    int64 `json:"block_limit_daily"`
+	BlockLimitMonthly  int64 `json:"block_limit_monthly"`
+	CommitLimitDaily   int64 `json:"commit_limit_daily"`
+	CommitLimitMonthly int64 `json:"commit_limit_monthly"`
+}
+
+func activeAt(expiresAt, now common.Timestamp) bool {
+	return expiresAt == 0 || now < expiresAt
+}
+
+// the rules and the overrides of the operator, kept in memory
+var (
+	policyMu       sync.RWMutex
+	accessRules    = make(map[string]*AccessRule)
+	quotaOverrides = make(map[string]*QuotaOverride)
+)
+
+func accessRuleKey(subjectType, subject string) string {
+	return subjectType + ":" + subject
+}
+
+// loadClientPolicies reads the rules and the overrides of the operator from the
+// database.
+func loadClientPolicies(ctx context.Context) error {
+	var (
+		rules     []*AccessRule
+		overrides []*QuotaOverride
+	)
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		if err := tx.Find(&rules).Error; err != nil {
+			return err
+		}
+		return tx.Find(&overrides).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return common.NewError("client_policies", err.Error())
+	}
+
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	clear(accessRules)
+	for _, rule := range rules {
+		accessRules[accessRuleKey(rule.SubjectType, rule.Subject)] = rule
+	}
+	clear(quotaOverrides)
+	for _, o := range overrides {
+		quotaOverrides[o.ClientID] = o
+	}
+	return nil
+}
+
+func activeAccessRule(subjectType, subject string, now common.Timestamp) *AccessRule {
+	rule := accessRules[accessRuleKey(subjectType, subject)]
+	if rule == nil || !activeAt(rule.ExpiresAt, now) {
+		return nil
+	}
+	return rule
+}
+
+// accessDecision returns the rule of the operator applying to the client
+// calling from ip, the rule on the client first. It is nil when the operator
+// set none.
+func accessDecision(clientID, ip string) *AccessRule {
+	now := common.Now()
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	if rule := activeAccessRule(SubjectClient, clientID, now); rule != nil {
+		return rule
+	}
+	if ip != "" {
+		return activeAccessRule(SubjectIP, ip, now)
+	}
+	return nil
+}
+
+func activeQuotaOverride(clientID string, now common.Timestamp) *QuotaOverride {
+	o := quotaOverrides[clientID]
+	if o == nil || !activeAt(o.ExpiresAt, now) {
+		return nil
+	}
+	return o
+}
+
+// getClientLimits returns the limits of the client, the ones of the config
+// unless the operator overrode them.
+func getClientLimits(clientID string) ClientLimits {
+	limits := ClientLimits{
+		UploadLimitMonthly: config.Configuration.UploadLimitMonthly,
+		BlockLimitDaily:    config.Configuration.BlockLimitDaily,
+		BlockLimitMonthly:  config.Configuration.BlockLimitMonthly,
+		CommitLimitDaily:   config.Configuration.CommitLimitDaily,
+		CommitLimitMonthly: config.Configuration.CommitLimitMonthly,
+	}
+
+	policyMu.RLock()
+	o := activeQuotaOverride(clientID, common.Now())
+	policyMu.RUnlock()
+	if o == nil {
+		return limits
+	}
+	for _, l := range []struct {
+		override *int64
+		limit    *int64
+	}{
+		{o.UploadLimitMonthly, &limits.UploadLimitMonthly},
+		{o.BlockLimitDaily, &limits.BlockLimitDaily},
+		{o.BlockLimitMonthly, &limits.BlockLimitMonthly},
+		{o.CommitLimitDaily, &limits.CommitLimitDaily},
+		{o.CommitLimitMonthly, &limits.CommitLimitMonthly},
+	} {
+		if l.override != nil {
+			*l.limit = *l.override
+		}
+	}
+	return limits
+}
@@ -0,0 +1,460 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"gorm.io/gorm/clause"
+)
+
+// ClientEnforcement is the enforcement state of the clients of the blobber.
+// swagger:model ClientEnforcement
+type ClientEnforcement struct {
+	AccessRules    []*AccessRule    `json:"access_rules"`
+	QuotaOverrides []*QuotaOverride `json:"quota_overrides"`
+	// Blacklist lists the clients blocked for exceeding their limits.
+	Blacklist []string `json:"blacklist"`
+	// Client is the state of the client asked for, if any.
+	Client *ClientState `json:"client,omitempty"`
+}
+
+// ClientState is the enforcement state of a client.
+type ClientState struct {
+	ClientID string `json:"client_id"`
+	IP       string `json:"ip,omitempty"`
+	Blocked  bool   `json:"blocked"`
+	// Rule is the rule of the operator deciding the access of the client, if
+	// any. Otherwise the client is blocked when on the blacklist.
+	Rule              *AccessRule  `json:"rule,omitempty"`
+	Limits            ClientLimits `json:"limits"`
+	DailyBlocks       int64        `json:"daily_blocks"`
+	DailyWriteMarkers int64        `json:"daily_write_markers"`
+}
+
+// getExpiresAt reads the expires_in duration parameter as the expiry of a rule
+// or an override, zero when it does not expire.
+func getExpiresAt(r *http.Request) (common.Timestamp, error) {
+	value := r.URL.Query().Get("expires_in")
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, common.NewError("invalid_parameters", "expires_in parameter is not a valid duration")
+	}
+	return common.Now() + common.Timestamp(d/time.Second), nil
+}
+
+func getAccessSubject(r *http.Request) (string, string, error) {
+	subjectType, subject := r.URL.Query().Get("subject_type"), r.URL.Query().Get("subject")
+	switch subjectType {
+	case SubjectClient:
+		if subject == "" || len(subject) > 64 {
+			return "", "", common.NewError("invalid_parameters", "subject parameter is not a valid client id")
+		}
+	case SubjectIP:
+		ip := net.ParseIP(subject)
+		if ip == nil {
+			return "", "", common.NewError("invalid_parameters", "subject parameter is not a valid ip")
+		}
+		subject = ip.String()
+	default:
+		return "", "", common.NewError("invalid_parameters", "subject_type parameter must be client or ip")
+	}
+	return subjectType, subject, nil
+}
+
+func getClientIDParam(r *http.Request) (string, error) {
+	clientID := r.URL.Query().Get("client_id")
+	if clientID == "" || len(clientID) > 64 {
+		return "", common.NewError("invalid_parameters", "client_id parameter is not valid")
+	}
+	return clientID, nil
+}
+
+// policyClient returns the client of a rule, none for a rule on an ip.
+func policyClient(subjectType, subject string) string {
+	if subjectType == SubjectClient {
+		return subject
+	}
+	return ""
+}
+
+// savePolicy runs f in a transaction, reloads the rules and the overrides
+// enforced and, for a policy on a client, updates the blacklist for it.
+func savePolicy(ctx context.Context, clientID string, f func(ctx context.Context) error) error {
+	err := datastore.GetStore().WithNewTransaction(f, datastore.WithParent(ctx))
+	if err != nil {
+		return common.NewError("client_policies", err.Error())
+	}
+	if err := loadClientPolicies(ctx); err != nil {
+		return err
+	}
+	if clientID == "" {
+		return nil
+	}
+	if err := reevaluateClient(ctx, clientID); err != nil {
+		return common.NewError("client_policies", err.Error())
+	}
+	return nil
+}
+
+// swagger:route GET /_clients GetClientEnforcement
+// Get client enforcement state.
+//
+// Lists the access rules and the quota overrides in force and the clients blacklisted for exceeding their limits.
+// With client_id, also reports whether the client is blocked, by which rule, and its limits.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Client to report the state of
+//	+name: ip
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Address the client calls from, to account for the rules on ips
+//
+// responses:
+//
+//	200: ClientEnforcement
+func GetClientEnforcement(ctx context.Context, r *http.Request) (interface{}, error) {
+	now := common.Now()
+	state := &ClientEnforcement{
+		AccessRules:    []*AccessRule{},
+		QuotaOverrides: []*QuotaOverride{},
+		Blacklist:      []string{},
+	}
+
+	policyMu.RLock()
+	for _, rule := range accessRules {
+		if activeAt(rule.ExpiresAt, now) {
+			state.AccessRules = append(state.AccessRules, rule)
+		}
+	}
+	for _, o := range quotaOverrides {
+		if activeAt(o.ExpiresAt, now) {
+			state.QuotaOverrides = append(state.QuotaOverrides, o)
+		}
+	}
+	policyMu.RUnlock()
+
+	blMap.RLock()
+	for clientID := range blackListMap {
+		state.Blacklist = append(state.Blacklist, clientID)
+	}
+	blMap.RUnlock()
+
+	sort.Slice(state.AccessRules, func(i, j int) bool {
+		return accessRuleKey(state.AccessRules[i].SubjectType, state.AccessRules[i].Subject) <
+			accessRuleKey(state.AccessRules[j].SubjectType, state.AccessRules[j].Subject)
+	})
+	sort.Slice(state.QuotaOverrides, func(i, j int) bool {
+		return state.QuotaOverrides[i].ClientID < state.QuotaOverrides[j].ClientID
+	})
+	sort.Strings(state.Blacklist)
+
+	if clientID := r.URL.Query().Get("client_id"); clientID != "" {
+		ip := r.URL.Query().Get("ip")
+		state.Client = &ClientState{
+			ClientID:          clientID,
+			IP:                ip,
+			Blocked:           CheckBlacklist(clientID, ip),
+			Rule:              accessDecision(clientID, ip),
+			Limits:            getClientLimits(clientID),
+			DailyBlocks:       getDailyBlocks(clientID),
+			DailyWriteMarkers: GetWriteMarkerCount(clientID),
+		}
+	}
+	return state, nil
+}
+
+// swagger:route POST /_clients/access SetAccessRule
+// Block or allow a client or an ip.
+//
+// The rule takes priority over the blacklist computed from the client stats, the rules on clients over the ones on ips.
+// A rule replaces the previous rule on the same client or ip.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: subject_type
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Either "client" or "ip"
+//	+name: subject
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Client id or ip of the rule
+//	+name: action
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Either "block" or "allow"
+//	+name: reason
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Why the client or the ip is blocked or allowed
+//	+name: expires_in
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Validity of the rule as a duration, e.g. "72h". The rule does not expire by default.
+//
+// responses:
+//
+//	200: AccessRule
+func SetAccessRule(ctx context.Context, r *http.Request) (interface{}, error) {
+	subjectType, subject, err := getAccessSubject(r)
+	if err != nil {
+		return nil, err
+	}
+	action := r.URL.Query().Get("action")
+	if action != AccessBlock && action != AccessAllow {
+		return nil, common.NewError("invalid_parameters", "action parameter must be block or allow")
+	}
+	reason := r.URL.Query().Get("reason")
+	if reason == "" {
+		return nil, common.NewError("invalid_parameters", "reason parameter is required")
+	}
+	expiresAt, err := getExpiresAt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &AccessRule{
+		SubjectType: subjectType,
+		Subject:     subject,
+		Action:      action,
+		Reason:      reason,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   common.Now(),
+	}
+	err = savePolicy(ctx, policyClient(subjectType, subject), func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(rule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// swagger:route DELETE /_clients/access DeleteAccessRule
+// Remove the rule on a client or an ip.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: subject_type
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Either "client" or "ip"
+//	+name: subject
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Client id or ip of the rule
+//
+// responses:
+//
+//	200:
+//	400:
+func DeleteAccessRule(ctx context.Context, r *http.Request) (interface{}, error) {
+	subjectType, subject, err := getAccessSubject(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted int64
+	err = savePolicy(ctx, policyClient(subjectType, subject), func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		res := tx.Where("subject_type = ? AND subject = ?", subjectType, subject).Delete(&AccessRule{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, common.NewError("not_found", "no rule on this "+subjectType)
+	}
+	return map[string]interface{}{"message": "Access rule removed"}, nil
+}
+
+// swagger:route POST /_clients/quota SetQuotaOverride
+// Override the limits of a client.
+//
+// The limits not given are the ones of the config. An override replaces the previous override of the client.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Client of the override
+//	+name: upload_limit_monthly
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Bytes the client can upload in 30 days
+//	+name: block_limit_daily
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Blocks the client can download in a day
+//	+name: block_limit_monthly
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Blocks the client can download in 30 days
+//	+name: commit_limit_daily
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Write markers the client can commit in a day
+//	+name: commit_limit_monthly
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Write markers the client can commit in 30 days
+//	+name: reason
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Why the limits of the client are overridden
+//	+name: expires_in
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Validity of the override as a duration, e.g. "720h". The override does not expire by default.
+//
+// responses:
+//
+//	200: QuotaOverride
+func SetQuotaOverride(ctx context.Context, r *http.Request) (interface{}, error) {
+	clientID, err := getClientIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+	reason := r.URL.Query().Get("reason")
+	if reason == "" {
+		return nil, common.NewError("invalid_parameters", "reason parameter is required")
+	}
+	expiresAt, err := getExpiresAt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &QuotaOverride{
+		ClientID:  clientID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: common.Now(),
+	}
+	set := false
+	for name, limit := range map[string]**int64{
+		"upload_limit_monthly": &o.UploadLimitMonthly,
+		"block_limit_daily":    &o.BlockLimitDaily,
+		"block_limit_monthly":  &o.BlockLimitMonthly,
+		"commit_limit_daily":   &o.CommitLimitDaily,
+		"commit_limit_monthly": &o.CommitLimitMonthly,
+	} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || v < 0 {
+			return nil, common.NewErrorf("invalid_parameters", "%s parameter is not valid", name)
+		}
+		*limit = &v
+		set = true
+	}
+	if !set {
+		return nil, common.NewError("invalid_parameters", "at least one limit is required")
+	}
+
+	err = savePolicy(ctx, clientID, func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(o).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// swagger:route DELETE /_clients/quota DeleteQuotaOverride
+// Restore the limits of the config for a client.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: true
+//	  description: Client of the override
+//
+// responses:
+//
+//	200:
+//	400:
+func DeleteQuotaOverride(ctx context.Context, r *http.Request) (interface{}, error) {
+	clientID, err := getClientIDParam(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted int64
+	err = savePolicy(ctx, clientID, func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		res := tx.Where("client_id = ?", clientID).Delete(&QuotaOverride{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, common.NewError("not_found", "no quota override for this client")
+	}
+	return map[string]interface{}{"message": "Quota override removed"}, nil
+}
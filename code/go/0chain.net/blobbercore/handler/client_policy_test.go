@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/stretchr/testify/require"
+)
+
+func setClientPolicies(t *testing.T, rules []*AccessRule, overrides []*QuotaOverride) {
+	policyMu.Lock()
+	for _, rule := range rules {
+		accessRules[accessRuleKey(rule.SubjectType, rule.Subject)] = rule
+	}
+	for _, o := range overrides {
+		quotaOverrides[o.ClientID] = o
+	}
+	policyMu.Unlock()
+
+	t.Cleanup(func() {
+		policyMu.Lock()
+		clear(accessRules)
+		clear(quotaOverrides)
+		policyMu.Unlock()
+		blMap.Lock()
+		clear(blackListMap)
+		blMap.Unlock()
+	})
+}
+
+func TestCheckBlacklist_Priority(t *testing.T) {
+	now := common.Now()
+	setClientPolicies(t, []*AccessRule{
+		{SubjectType: SubjectClient, Subject: "partner", Action: AccessAllow},
+		{SubjectType: SubjectClient, Subject: "abuser", Action: AccessBlock},
+		{SubjectType: SubjectClient, Subject: "expired", Action: AccessBlock, ExpiresAt: now - 1},
+		{SubjectType: SubjectIP, Subject: "10.0.0.1", Action: AccessBlock},
+	}, nil)
+	SetBlacklist("partner")
+	SetBlacklist("computed")
+
+	// the rules of the operator take priority over the computed blacklist
+	require.False(t, CheckBlacklist("partner", ""))
+	require.True(t, CheckBlacklist("computed", ""))
+	require.True(t, CheckBlacklist("abuser", ""))
+	require.False(t, CheckBlacklist("expired", ""))
+
+	// the rules on clients take priority over the ones on ips
+	require.True(t, CheckBlacklist("other", "10.0.0.1"))
+	require.False(t, CheckBlacklist("partner", "10.0.0.1"))
+	require.False(t, CheckBlacklist("other", "10.0.0.2"))
+}
+
+func TestGetClientLimits(t *testing.T) {
+	config.Configuration.BlockLimitDaily = 100
+	config.Configuration.CommitLimitDaily = 10
+	daily := int64(1000)
+	setClientPolicies(t, nil, []*QuotaOverride{
+		{ClientID: "partner", BlockLimitDaily: &daily},
+		{ClientID: "expired", BlockLimitDaily: &daily, ExpiresAt: common.Now() - 1},
+	})
+
+	limits := getClientLimits("partner")
+	require.Equal(t, int64(1000), limits.BlockLimitDaily)
+	require.Equal(t, int64(10), limits.CommitLimitDaily)
+
+	require.Equal(t, int64(100), getClientLimits("expired").BlockLimitDaily)
+	require.Equal(t, int64(100), getClientLimits("other").BlockLimitDaily)
+}
+
+func TestRefreshBlacklist_Override(t *testing.T) {
+	config.Configuration.UploadLimitMonthly = 1000
+	config.Configuration.BlockLimitMonthly = 1000
+	config.Configuration.CommitLimitMonthly = 1000
+	config.Configuration.CommitLimitDaily = 10
+	config.Configuration.CommitZeroLimitDaily = 10
+	setClientPolicies(t, nil, nil)
+	monthly := clientUsage{Upload: 5000}
+
+	refreshBlacklist("heavy", monthly)
+	require.True(t, CheckBlacklist("heavy", ""))
+
+	// raising the limit of the client lifts its block right away
+	upload := int64(10000)
+	setClientPolicies(t, nil, []*QuotaOverride{{ClientID: "heavy", UploadLimitMonthly: &upload}})
+	refreshBlacklist("heavy", monthly)
+	require.False(t, CheckBlacklist("heavy", ""))
+
+	// the daily limits are checked as well
+	mpLock.Lock()
+	clientMap["heavy"] = &ClientStats{ClientID: "heavy", TotalWM: 11}
+	mpLock.Unlock()
+	t.Cleanup(func() {
+		mpLock.Lock()
+		clear(clientMap)
+		mpLock.Unlock()
+	})
+	refreshBlacklist("heavy", monthly)
+	require.True(t, CheckBlacklist("heavy", ""))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -83,7 +85,7 @@ func AddWriteMarkerCount(clientID string, zeroSizeWM bool) {
 	if zeroSizeWM {
 		cs.TotalZeroWM++
 	}
-	if cs.TotalZeroWM > config.Configuration.CommitZeroLimitDaily || cs.TotalWM > config.Configuration.CommitLimitDaily {
+	if cs.TotalZeroWM > config.Configuration.CommitZeroLimitDaily || cs.TotalWM > getClientLimits(clientID).CommitLimitDaily {
 		SetBlacklist(clientID)
 	}
 }
@@ -94,13 +96,84 @@ func SetBlacklist(clientID string) {
 	blMap.Unlock()
 }
 
-func CheckBlacklist(clientID string) bool {
+// CheckBlacklist reports whether the client, calling from ip, is blocked. The
+// rules of the operator on the client, then on the IP, take priority over the
+// blacklist computed from the client stats.
+func CheckBlacklist(clientID, ip string) bool {
+	if rule := accessDecision(clientID, ip); rule != nil {
+		return rule.Action == AccessBlock
+	}
+
 	blMap.RLock()
 	defer blMap.RUnlock()
 	_, ok := blackListMap[clientID]
 	return ok
 }
 
+// clientUsage is the usage of a client over the last Period.
+type clientUsage struct {
+	Upload       int64
+	Download     int64
+	WriteMarkers int64
+}
+
+// getMonthlyUsage returns the usage of the client over the last Period, the
+// one of the day not saved yet included.
+func getMonthlyUsage(ctx context.Context, clientID string) (clientUsage, error) {
+	var u clientUsage
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		tx := datastore.GetStore().GetTransaction(ctx)
+		return tx.Raw("SELECT COALESCE(sum(total_upload), 0) as upload, COALESCE(sum(total_download), 0) as download, COALESCE(sum(total_write_marker), 0) as write_markers "+
+			"from client_stats where client_id = ? and created_at > ?", clientID, common.Now()-Period).Scan(&u).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return u, err
+	}
+
+	mpLock.RLock()
+	if cs := clientMap[clientID]; cs != nil {
+		u.Upload += cs.TotalUpload
+		u.WriteMarkers += cs.TotalWM
+	}
+	mpLock.RUnlock()
+	u.Download += getDailyBlocks(clientID)
+	return u, nil
+}
+
+// refreshBlacklist blacklists the client, or lifts its block, from its limits
+// in force and its usage over the last Period and the day.
+func refreshBlacklist(clientID string, monthly clientUsage) {
+	limits := getClientLimits(clientID)
+	over := monthly.Upload > limits.UploadLimitMonthly ||
+		monthly.Download > limits.BlockLimitMonthly ||
+		monthly.WriteMarkers > limits.CommitLimitMonthly
+
+	mpLock.RLock()
+	if cs := clientMap[clientID]; cs != nil {
+		over = over || cs.TotalZeroWM > config.Configuration.CommitZeroLimitDaily || cs.TotalWM > limits.CommitLimitDaily
+	}
+	mpLock.RUnlock()
+
+	blMap.Lock()
+	defer blMap.Unlock()
+	if over {
+		blackListMap[clientID] = true
+	} else {
+		delete(blackListMap, clientID)
+	}
+}
+
+// reevaluateClient updates the blacklist for the client once its limits
+// changed, rather than waiting for the next run of the blacklist worker.
+func reevaluateClient(ctx context.Context, clientID string) error {
+	monthly, err := getMonthlyUsage(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	refreshBlacklist(clientID, monthly)
+	return nil
+}
+
 func saveClientStats() {
 	dbStats := make([]*ClientStats, 0, len(clientMap))
 	mpLock.Lock()
@@ -122,7 +195,12 @@ func saveClientStats() {
 	var blackList []string
 	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 		tx := datastore.GetStore().GetTransaction(ctx)
-		err := tx.Raw("SELECT client_id as blackList from (SELECT client_id,sum(total_upload) as upload,sum(total_download) as download, sum(total_write_marker) as writemarker from client_stats where created_at > ? group by client_id) as stats where stats.upload > ? or stats.download > ? or stats.writemarker > ?", common.Now()-Period, config.Configuration.UploadLimitMonthly, config.Configuration.BlockLimitMonthly, config.Configuration.CommitLimitMonthly).Scan(&blackList).Error
+		// the active quota overrides of the operator replace the monthly limits
+		now := common.Now()
+		err := tx.Raw("SELECT stats.client_id as blackList from (SELECT client_id,sum(total_upload) as upload,sum(total_download) as download, sum(total_write_marker) as writemarker from client_stats where created_at > ? group by client_id) as stats "+
+			"LEFT JOIN client_quota_overrides o ON o.client_id = stats.client_id AND (o.expires_at = 0 OR o.expires_at > ?) "+
+			"where stats.upload > COALESCE(o.upload_limit_monthly, ?) or stats.download > COALESCE(o.block_limit_monthly, ?) or stats.writemarker > COALESCE(o.commit_limit_monthly, ?)",
+			now-Period, now, config.Configuration.UploadLimitMonthly, config.Configuration.BlockLimitMonthly, config.Configuration.CommitLimitMonthly).Scan(&blackList).Error
 		return err
 	})
 	if err == nil {
@@ -140,6 +218,9 @@ func startBlackListWorker(ctx context.Context) {
 	if config.Development() {
 		BlackListWorkerTime = 10 * time.Second
 	}
+	if err := loadClientPolicies(ctx); err != nil {
+		logging.Logger.Error("[client_policies]load", zap.Error(err))
+	}
 	saveClientStats()
 
 	for {
@@ -149,24 +149,7 @@ func WithTxHandler(handler func(ctx *Context) (interface{}, error)) func(w http.
 		statusCode := 0
 		var result interface{}
 		err := datastore.GetStore().WithNewTransaction(func(c context.Context) error {
-			ctx := &Context{
-				Context: c,
-				Request: r,
-				Store:   datastore.GetStore(),
-			}
-
-			ctx.Vars = mux.Vars(r)
-			if ctx.Vars == nil {
-				ctx.Vars = make(map[string]string)
-			}
-
-			ctx.ClientID = r.Header.Get(common.ClientHeader)
-			ctx.ClientKey = r.Header.Get(common.ClientKeyHeader)
-			ctx.AllocationId = r.Header.Get(common.AllocationIdHeader)
-			ctx.Signature = r.Header.Get(common.ClientSignatureHeader)
-			ctx.SignatureV2 = r.Header.Get(common.ClientSignatureHeaderV2)
-
-			ctx, err := WithVerify(ctx, r)
+			ctx, err := newContext(c, r)
 			statusCode = ctx.StatusCode
 
 			if err != nil {
@@ -200,6 +183,28 @@ func WithTxHandler(handler func(ctx *Context) (interface{}, error)) func(w http.
 	}
 }
 
+// newContext creates the api context of the request and verifies it
+func newContext(c context.Context, r *http.Request) (*Context, error) {
+	ctx := &Context{
+		Context: c,
+		Request: r,
+		Store:   datastore.GetStore(),
+	}
+
+	ctx.Vars = mux.Vars(r)
+	if ctx.Vars == nil {
+		ctx.Vars = make(map[string]string)
+	}
+
+	ctx.ClientID = r.Header.Get(common.ClientHeader)
+	ctx.ClientKey = r.Header.Get(common.ClientKeyHeader)
+	ctx.AllocationId = r.Header.Get(common.AllocationIdHeader)
+	ctx.Signature = r.Header.Get(common.ClientSignatureHeader)
+	ctx.SignatureV2 = r.Header.Get(common.ClientSignatureHeaderV2)
+
+	return WithVerify(ctx, r)
+}
+
 // WithVerify verify allocation and signature
 func WithVerify(ctx *Context, r *http.Request) (*Context, error) {
 
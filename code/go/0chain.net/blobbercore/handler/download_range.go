@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+type rangeDownloadKey struct{}
+
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// ToRangeByteStream is ToByteStream for the download route. The plain data
+// downloads of the route honour the "Range" and "If-None-Match" headers of
+// standard HTTP clients, so the route can be served by a caching proxy.
+func ToRangeByteStream(handler common.JSONResponderF) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), rangeDownloadKey{}, true))
+		common.ToByteStream(func(ctx context.Context, r *http.Request) (interface{}, error) {
+			data, err := handler(ctx, r)
+			if rd, ok := data.(*rangeDownload); ok && err == nil {
+				rd.write(w)
+				return nil, nil
+			}
+			return data, err
+		})(w, r)
+	}
+}
+
+func isRangeDownload(ctx context.Context) bool {
+	ok, _ := ctx.Value(rangeDownloadKey{}).(bool)
+	return ok
+}
+
+// rangeDownload is a plain data download of the byte range [start, end] of a
+// file, or of the whole file.
+type rangeDownload struct {
+	etag    string
+	size    int64
+	start   int64
+	end     int64
+	partial bool
+	// ranges is set when byte ranges of the file can be served, which is
+	// neither the case of the thumbnails nor of the encrypted files.
+	ranges      bool
+	notModified bool
+	// unsatisfiable is set when the range starts beyond the end of the file.
+	unsatisfiable bool
+	// dataOffset is the offset in the file of the first byte read.
+	dataOffset int64
+	data       []byte
+}
+
+// newRangeDownload resolves the byte range requested onto the blocks of the
+// file, updating the block number and the number of blocks of the request so
+// the quota and the read markers account for the blocks read. A range over more
+// blocks than a request may read is cut to its first blocks, so a download of
+// a large file is served in parts. It returns nil for the downloads of the
+// block protocol.
+func newRangeDownload(ctx context.Context, dr *DownloadRequestHeader, fileref *reference.Ref) *rangeDownload {
+	if !isRangeDownload(ctx) || dr.VerifyDownload || dr.Receipt ||
+		dr.Get("X-Block-Num") != "" || dr.Get("X-Num-Blocks") != "" {
+		return nil
+	}
+
+	rd := &rangeDownload{
+		etag: strconv.Quote(fileref.ValidationRoot),
+		size: fileref.Size,
+	}
+	isThumb := dr.DownloadMode == DownloadContentThumb
+	if isThumb {
+		rd.etag = strconv.Quote(fileref.ThumbnailHash)
+		rd.size = fileref.ThumbnailSize
+	}
+	rd.end = rd.size - 1
+	rd.ranges = !isThumb && len(fileref.EncryptedKey) == 0
+
+	if dr.Range != "" {
+		start, end, err := parseByteRange(dr.Range, rd.size)
+		switch {
+		case errors.Is(err, errRangeNotSatisfiable):
+			rd.unsatisfiable = true
+			return rd
+		case err == nil:
+			rd.start, rd.end, rd.partial = start, end, true
+		}
+		// a malformed range is ignored and the whole file is sent
+	}
+
+	dr.BlockNum = rd.start / filestore.ChunkSize
+	dr.NumBlocks = 0
+	if rd.size > 0 {
+		dr.NumBlocks = rd.end/filestore.ChunkSize - dr.BlockNum + 1
+	}
+	if limit := config.Configuration.BlockLimitRequest; rd.ranges && dr.NumBlocks > limit {
+		dr.NumBlocks = limit
+		rd.end = (dr.BlockNum+limit)*filestore.ChunkSize - 1
+		rd.partial = true
+	}
+	// a thumbnail is always read whole
+	if !isThumb {
+		rd.dataOffset = dr.BlockNum * filestore.ChunkSize
+	}
+	return rd
+}
+
+// matches tells if the If-None-Match header of the request lists the ETag of
+// the file.
+func (rd *rangeDownload) matches(ifNoneMatch string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == rd.etag {
+			return true
+		}
+	}
+	return false
+}
+
+// setData keeps the bytes of the range out of the data of the blocks read.
+func (rd *rangeDownload) setData(data []byte) {
+	from := rd.start - rd.dataOffset
+	to := rd.end - rd.dataOffset + 1
+	if to > int64(len(data)) {
+		to = int64(len(data))
+	}
+	if from > to {
+		from = to
+	}
+	rd.data = data[from:to]
+}
+
+func (rd *rangeDownload) write(w http.ResponseWriter) {
+	w.Header().Set("ETag", rd.etag)
+	if rd.ranges {
+		w.Header().Set("Accept-Ranges", "bytes")
+	}
+	switch {
+	case rd.notModified:
+		w.WriteHeader(http.StatusNotModified)
+		return
+	case rd.unsatisfiable:
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", rd.size))
+		http.Error(w, errRangeNotSatisfiable.Error(), http.StatusRequestedRangeNotSatisfiable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(rd.data)))
+	if rd.partial {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", rd.start, rd.start+int64(len(rd.data))-1, rd.size))
+		w.WriteHeader(http.StatusPartialContent)
+	}
+	w.Write(rd.data) //nolint:errcheck
+}
+
+// parseByteRange parses a single "bytes=" range of a file of the given size
+// into the first and the last byte of the range. Multiple ranges are not
+// supported and are reported as malformed.
+func parseByteRange(header string, size int64) (start, end int64, err error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return 0, 0, common.NewError("invalid_range", "unsupported range unit")
+	}
+	spec := strings.TrimSpace(header[len(prefix):])
+	if strings.Contains(spec, ",") {
+		return 0, 0, common.NewError("invalid_range", "multiple ranges are not supported")
+	}
+	i := strings.Index(spec, "-")
+	if i < 0 {
+		return 0, 0, common.NewError("invalid_range", "invalid range: "+spec)
+	}
+	first, last := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+
+	if first == "" {
+		// the last n bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, common.NewError("invalid_range", "invalid suffix range: "+spec)
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, errRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size - 1, nil
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, common.NewError("invalid_range", "invalid range start: "+spec)
+	}
+	end = size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, common.NewError("invalid_range", "invalid range end: "+spec)
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	if start >= size {
+		return 0, 0, errRangeNotSatisfiable
+	}
+	return start, end, nil
+}
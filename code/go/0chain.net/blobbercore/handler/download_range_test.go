@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		header         string
+		start, end     int64
+		wantErr        bool
+		notSatisfiable bool
+	}{
+		{header: "bytes=0-99", start: 0, end: 99},
+		{header: "bytes=100-", start: 100, end: 999},
+		{header: "bytes=-100", start: 900, end: 999},
+		{header: "bytes=-5000", start: 0, end: 999},
+		{header: "bytes=500-5000", start: 500, end: 999},
+		{header: "bytes=1000-", notSatisfiable: true},
+		{header: "bytes=-0", notSatisfiable: true},
+		{header: "bytes=5-1", wantErr: true},
+		{header: "bytes=0-1,5-6", wantErr: true},
+		{header: "items=0-1", wantErr: true},
+		{header: "bytes=a-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, end, err := parseByteRange(tt.header, 1000)
+			if tt.notSatisfiable {
+				require.ErrorIs(t, err, errRangeNotSatisfiable)
+				return
+			}
+			if tt.wantErr {
+				require.Error(t, err)
+				require.NotErrorIs(t, err, errRangeNotSatisfiable)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.start, start)
+			require.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestRangeDownload(t *testing.T) {
+	ctx := context.WithValue(context.TODO(), rangeDownloadKey{}, true)
+	fileref := &reference.Ref{Size: 3 * filestore.ChunkSize, ValidationRoot: "root"}
+	config.Configuration.BlockLimitRequest = 500
+
+	newRequest := func(headers map[string]string) *DownloadRequestHeader {
+		r := httptest.NewRequest(http.MethodGet, "/v1/file/download/alloc", nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		dr, err := FromDownloadRequest("alloc", r, false)
+		require.NoError(t, err)
+		return dr
+	}
+
+	t.Run("block protocol", func(t *testing.T) {
+		dr := newRequest(map[string]string{"X-Path": "/a", "X-Block-Num": "1", "X-Num-Blocks": "1"})
+		require.Nil(t, newRangeDownload(ctx, dr, fileref))
+		require.Nil(t, newRangeDownload(context.TODO(), newRequest(map[string]string{"X-Path": "/a"}), fileref))
+	})
+
+	t.Run("whole file", func(t *testing.T) {
+		dr := newRequest(map[string]string{"X-Path": "/a"})
+		rd := newRangeDownload(ctx, dr, fileref)
+		require.NotNil(t, rd)
+		require.Equal(t, int64(0), dr.BlockNum)
+		require.Equal(t, int64(3), dr.NumBlocks)
+
+		rd.setData(make([]byte, fileref.Size))
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `"root"`, w.Header().Get("ETag"))
+		require.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		require.Equal(t, int(fileref.Size), w.Body.Len())
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		config.Configuration.BlockLimitRequest = 2
+		defer func() { config.Configuration.BlockLimitRequest = 500 }()
+
+		dr := newRequest(map[string]string{"X-Path": "/a"})
+		rd := newRangeDownload(ctx, dr, fileref)
+		require.Equal(t, int64(0), dr.BlockNum)
+		require.Equal(t, int64(2), dr.NumBlocks)
+
+		rd.setData(make([]byte, 2*filestore.ChunkSize))
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusPartialContent, w.Code)
+		require.Equal(t, "bytes 0-131071/196608", w.Header().Get("Content-Range"))
+
+		dr = newRequest(map[string]string{"X-Path": "/a", "Range": "bytes=65536-"})
+		newRangeDownload(ctx, dr, fileref)
+		require.Equal(t, int64(1), dr.BlockNum)
+		require.Equal(t, int64(2), dr.NumBlocks)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		config.Configuration.BlockLimitRequest = 2
+		defer func() { config.Configuration.BlockLimitRequest = 500 }()
+
+		encrypted := *fileref
+		encrypted.EncryptedKey = "key"
+		dr := newRequest(map[string]string{"X-Path": "/a"})
+		rd := newRangeDownload(ctx, dr, &encrypted)
+		// not cut into ranges, the handler refuses the blocks over the limit
+		require.Equal(t, int64(3), dr.NumBlocks)
+
+		rd.setData(make([]byte, encrypted.Size))
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, w.Header().Get("Accept-Ranges"))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		start := filestore.ChunkSize + 10
+		dr := newRequest(map[string]string{"X-Path": "/a", "Range": "bytes=65546-131081"})
+		rd := newRangeDownload(ctx, dr, fileref)
+		require.Equal(t, int64(1), dr.BlockNum)
+		require.Equal(t, int64(2), dr.NumBlocks)
+
+		data := make([]byte, 2*filestore.ChunkSize)
+		data[10] = 1
+		rd.setData(data)
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusPartialContent, w.Code)
+		require.Equal(t, "bytes 65546-131081/196608", w.Header().Get("Content-Range"))
+		require.Equal(t, int(131081-start+1), w.Body.Len())
+		require.Equal(t, byte(1), w.Body.Bytes()[0])
+	})
+
+	t.Run("not satisfiable", func(t *testing.T) {
+		dr := newRequest(map[string]string{"X-Path": "/a", "Range": "bytes=196608-"})
+		rd := newRangeDownload(ctx, dr, fileref)
+		require.True(t, rd.unsatisfiable)
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+		require.Equal(t, "bytes */196608", w.Header().Get("Content-Range"))
+	})
+
+	t.Run("if none match", func(t *testing.T) {
+		rd := newRangeDownload(ctx, newRequest(map[string]string{"X-Path": "/a"}), fileref)
+		require.True(t, rd.matches(`"other", W/"root"`))
+		require.True(t, rd.matches("*"))
+		require.False(t, rd.matches(`"other"`))
+		require.False(t, rd.matches(""))
+
+		rd.notModified = true
+		w := httptest.NewRecorder()
+		rd.write(w)
+		require.Equal(t, http.StatusNotModified, w.Code)
+		require.Equal(t, 0, w.Body.Len())
+	})
+}
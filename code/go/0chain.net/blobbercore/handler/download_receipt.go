@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/gosdk/constants"
+	"go.uber.org/zap"
+)
+
+// swagger:route GET /v1/receipts/{allocation} ListDownloadReceipts
+// List download receipts.
+//
+// Lists the receipts, signed by the blobber, of the blocks served to a client.
+// A client can list its own receipts and the owner of the allocation can list the receipts of any client.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: TxHash of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: client_id
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Client whose receipts are listed. Defaults to the client sending the request.
+//	+name: offset
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination offset, start of the page to retrieve. Default is 0.
+//	+name: limit
+//	  in: query
+//	  type: integer
+//	  required: false
+//	  description: Pagination limit, number of entries in the page to retrieve. Default is 20.
+//	+name: sort
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Direction of sorting based on the issue order, either "asc" or "desc". Default is "asc"
+//
+// responses:
+//
+//	200: DownloadReceipts
+//	400:
+func ListDownloadReceipts(ctx context.Context, r *http.Request) (interface{}, error) {
+	ctx = setupHandlerContext(ctx, r)
+
+	var (
+		allocationTx = ctx.Value(constants.ContextKeyAllocation).(string)
+		allocationID = ctx.Value(constants.ContextKeyAllocationID).(string)
+		clientID     = ctx.Value(constants.ContextKeyClient).(string)
+		clientKey    = ctx.Value(constants.ContextKeyClientKey).(string)
+	)
+
+	limit, err := common.GetOffsetLimitOrderParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	allocationObj, err := storageHandler.verifyAllocation(ctx, allocationID, allocationTx, true)
+	if err != nil {
+		return nil, common.NewError("invalid_parameters", "Invalid allocation id passed."+err.Error())
+	}
+
+	if clientID == "" {
+		return nil, common.NewError("invalid_operation", "Client id is required")
+	}
+
+	publicKey := clientKey
+	if clientID == allocationObj.OwnerID {
+		publicKey = allocationObj.OwnerPublicKey
+	} else {
+		clientKeyBytes, _ := hex.DecodeString(clientKey)
+		if encryption.Hash(clientKeyBytes) != clientID {
+			return nil, common.NewError("invalid_parameters", "Client key does not match the client id")
+		}
+	}
+
+	sign := r.Header.Get(common.ClientSignatureHeader)
+	signV2 := r.Header.Get(common.ClientSignatureHeaderV2)
+	valid, err := verifySignatureFromRequest(allocationTx, sign, signV2, publicKey)
+	if !valid || err != nil {
+		return nil, common.NewError("invalid_signature", "Invalid signature")
+	}
+
+	receiptsClientID := r.URL.Query().Get("client_id")
+	if receiptsClientID == "" {
+		receiptsClientID = clientID
+	}
+	if receiptsClientID != clientID && clientID != allocationObj.OwnerID {
+		return nil, common.NewError("invalid_operation", "Only the owner of the allocation can list the receipts of other clients")
+	}
+
+	receipts, err := readmarker.ListDownloadReceipts(ctx, allocationObj.ID, receiptsClientID, limit)
+	if err != nil {
+		Logger.Error("failed_to_list_download_receipts", zap.Error(err))
+		return nil, common.NewError("failed_to_list_download_receipts", "failed to list download receipts")
+	}
+	return receipts, nil
+}
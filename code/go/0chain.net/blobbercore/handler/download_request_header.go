@@ -23,9 +23,13 @@ type DownloadRequestHeader struct {
 	ReadMarker     readmarker.ReadMarker
 	AuthToken      string
 	VerifyDownload bool
+	Receipt        bool
 	DownloadMode   string
 	ConnectionID   string
 	Version        string
+	Range          string
+	IfNoneMatch    string
+	PresignedReads bool
 }
 
 func FromDownloadRequest(allocationID string, req *http.Request, isRedeem bool) (*DownloadRequestHeader, error) {
@@ -99,12 +103,18 @@ func (dr *DownloadRequestHeader) Parse(isRedeem bool) error {
 	} else if isRedeem {
 		return errors.Throw(common.ErrInvalidParameter, "X-Read-Marker")
 	}
+	if isRedeem {
+		dr.PresignedReads = dr.Get("X-Presigned-Reads") == "true"
+	}
 
 	dr.AuthToken = dr.Get("X-Auth-Token")
 
 	dr.DownloadMode = dr.Get("X-Mode")
 	dr.VerifyDownload = dr.Get("X-Verify-Download") == "true"
+	dr.Receipt = dr.Get("X-Download-Receipt") == "true"
 	dr.Version = dr.Get("X-Version")
+	dr.Range = dr.Get("Range")
+	dr.IfNoneMatch = dr.Get("If-None-Match")
 	return nil
 }
 
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+)
+
+// Statuses of the blobber, as reported by /_blobber_info. Clients should not
+// pick a draining blobber for new writes.
+const (
+	BlobberStatusActive   = "active"
+	BlobberStatusDraining = "draining"
+)
+
+// drainRetryAfter is the delay, in seconds, the clients refused while the
+// blobber drains are told to wait before retrying.
+const drainRetryAfter = 60
+
+// The drain state is kept in memory only, so a restart ends the drain.
+var drain struct {
+	sync.RWMutex
+	draining bool
+	since    common.Timestamp
+	reason   string
+}
+
+// DrainStatus is the drain state of the blobber.
+// swagger:model DrainStatus
+type DrainStatus struct {
+	Status string           `json:"status"`
+	Since  common.Timestamp `json:"since,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+	// HeldWriteMarkerLocks is the number of allocations with their write
+	// marker lock held, whose commits are let through. The blobber is drained
+	// once it is zero.
+	HeldWriteMarkerLocks int `json:"held_write_marker_locks"`
+}
+
+func isDraining() bool {
+	drain.RLock()
+	defer drain.RUnlock()
+	return drain.draining
+}
+
+func getDrainStatus() *DrainStatus {
+	drain.RLock()
+	defer drain.RUnlock()
+	ds := &DrainStatus{
+		Status:               BlobberStatusActive,
+		HeldWriteMarkerLocks: writemarker.WriteMarkerMutext.HeldCount(),
+	}
+	if drain.draining {
+		ds.Status = BlobberStatusDraining
+		ds.Since = drain.since
+		ds.Reason = drain.reason
+	}
+	return ds
+}
+
+func setDraining(draining bool, reason string) {
+	drain.Lock()
+	defer drain.Unlock()
+	if draining && !drain.draining {
+		drain.since = common.Now()
+	}
+	drain.draining = draining
+	drain.reason = reason
+	if !draining {
+		drain.since = 0
+	}
+	Logger.Info("[drain]blobber status changed", zap.Bool("draining", draining), zap.String("reason", reason))
+}
+
+// RefuseWhileDraining refuses the writes while the blobber drains, with a
+// retryable status, unless they come from the connection holding the write
+// marker lock of their allocation, so the commits under way finish. The
+// request is told apart without reading its body: the connection is the
+// "connection_id" query parameter or the X-Connection-ID header.
+func RefuseWhileDraining(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isDraining() && r.Method != http.MethodOptions {
+			allocationID := r.Header.Get(common.AllocationIdHeader)
+			if allocationID == "" {
+				allocationID = mux.Vars(r)["allocation"]
+			}
+			connectionID := r.URL.Query().Get("connection_id")
+			if connectionID == "" {
+				connectionID = r.Header.Get("X-Connection-ID")
+			}
+			if !writemarker.WriteMarkerMutext.Held(allocationID, connectionID) {
+				w.Header().Set("Retry-After", strconv.Itoa(drainRetryAfter))
+				http.Error(w, "Blobber is draining for maintenance, retry later or on another blobber",
+					http.StatusServiceUnavailable)
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
+// grpcDrainRefused reports whether the method is refused while the blobber
+// drains, as its HTTP route is.
+func grpcDrainRefused(ctx context.Context, fullMethod string, req interface{}) bool {
+	if !isDraining() {
+		return false
+	}
+	switch path.Base(fullMethod) {
+	case "UploadFile", "UploadFileStream", "DeleteFile", "CopyObject", "RenameObject", "MoveObject",
+		"CreateDir", "WriteMarkerLock", "Commit", "Rollback":
+	default:
+		return false
+	}
+	if c, ok := req.(interface{ GetConnectionId() string }); ok {
+		return !writemarker.WriteMarkerMutext.Held(getGRPCMetaDataFromCtx(ctx).AllocationID, c.GetConnectionId())
+	}
+	return true
+}
+
+var errDraining = status.Error(codes.Unavailable, "blobber is draining for maintenance, retry later or on another blobber")
+
+func unaryDrainInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if grpcDrainRefused(ctx, info.FullMethod, req) {
+			return nil, errDraining
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamDrainInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if grpcDrainRefused(ss.Context(), info.FullMethod, nil) {
+			return errDraining
+		}
+		return handler(srv, ss)
+	}
+}
+
+// swagger:route GET /_drain GetDrainStatus
+// Get drain status.
+//
+// Reports whether the blobber drains for maintenance and the write marker locks still held.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//
+// responses:
+//
+//	200: DrainStatus
+func GetDrainStatus(ctx context.Context, r *http.Request) (interface{}, error) {
+	return getDrainStatus(), nil
+}
+
+// swagger:route POST /_drain StartDrain
+// Drain the blobber for maintenance.
+//
+// New connections, uploads, other writes and write marker locks are refused with 503 and a Retry-After header.
+// The commits of the connections holding a write marker lock finish, reads, redemptions and challenges go on.
+// A commit is let through when it sends its connection id in the "connection_id" query parameter or the X-Connection-ID header.
+// The drain ends with DELETE /_drain or when the blobber restarts.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//	+name: reason
+//	  in: query
+//	  type: string
+//	  required: false
+//	  description: Why the blobber is drained
+//
+// responses:
+//
+//	200: DrainStatus
+func StartDrain(ctx context.Context, r *http.Request) (interface{}, error) {
+	setDraining(true, r.URL.Query().Get("reason"))
+	return getDrainStatus(), nil
+}
+
+// swagger:route DELETE /_drain EndDrain
+// End the drain of the blobber.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the operator scope. MUST be provided to fulfil the request
+//
+// responses:
+//
+//	200: DrainStatus
+func EndDrain(ctx context.Context, r *http.Request) (interface{}, error) {
+	setDraining(false, "")
+	return getDrainStatus(), nil
+}
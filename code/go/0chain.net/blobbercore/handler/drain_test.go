@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefuseWhileDraining(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 30 * time.Second
+	_, err := writemarker.WriteMarkerMutext.Lock(context.Background(), "drain_alloc", "holder")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = writemarker.WriteMarkerMutext.Unlock("drain_alloc", "holder")
+		setDraining(false, "")
+	})
+
+	h := RefuseWhileDraining(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	call := func(connectionID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/v1/connection/commit/drain_alloc?connection_id="+connectionID, nil)
+		r.Header.Set(common.AllocationIdHeader, "drain_alloc")
+		w := httptest.NewRecorder()
+		h(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, call("other").Code)
+
+	setDraining(true, "disk replacement")
+	ds := getDrainStatus()
+	require.Equal(t, BlobberStatusDraining, ds.Status)
+	require.Equal(t, "disk replacement", ds.Reason)
+	require.NotZero(t, ds.Since)
+	require.GreaterOrEqual(t, ds.HeldWriteMarkerLocks, 1)
+
+	// the commit under way finishes, the others are told to retry
+	require.Equal(t, http.StatusOK, call("holder").Code)
+	w := call("other")
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// the connection is also told by the header, and never by the body
+	r := httptest.NewRequest(http.MethodPost, "/v1/connection/commit/drain_alloc", strings.NewReader("connection_id=holder"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(common.AllocationIdHeader, "drain_alloc")
+	w = httptest.NewRecorder()
+	h(w, r)
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Nil(t, r.Form)
+	r.Header.Set("X-Connection-ID", "holder")
+	w = httptest.NewRecorder()
+	h(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	setDraining(false, "")
+	require.Equal(t, BlobberStatusActive, getDrainStatus().Status)
+	require.Equal(t, http.StatusOK, call("other").Code)
+}
+
+func TestGRPCDrainRefused(t *testing.T) {
+	t.Cleanup(func() { setDraining(false, "") })
+	ctx := context.Background()
+	upload := &blobbergrpc.UploadFileRequest{ConnectionId: "other"}
+
+	require.False(t, grpcDrainRefused(ctx, "/blobber.service.v1.BlobberService/UploadFile", upload))
+
+	setDraining(true, "")
+	require.True(t, grpcDrainRefused(ctx, "/blobber.service.v1.BlobberService/UploadFile", upload))
+	require.True(t, grpcDrainRefused(ctx, "/blobber.service.v1.BlobberService/UploadFileStream", nil))
+	require.False(t, grpcDrainRefused(ctx, "/blobber.service.v1.BlobberService/DownloadFile", nil))
+	require.False(t, grpcDrainRefused(ctx, "/blobber.service.v1.BlobberService/WriteMarkerUnlock", nil))
+}
@@ -123,10 +123,11 @@ func (cmd *UpdateFileCommand) ProcessContent(ctx context.Context, allocationObj
 		FilePathHash: filePathHash,
 		Size:         cmd.fileChanger.Size,
 	}
-	fileOutputData, err := filestore.GetFileStore().WriteFile(allocationObj.ID, connID, fileInputData, cmd.contentFile)
+	fileOutputData, err := filestore.WithContext(ctx).WriteFile(allocationObj.ID, connID, fileInputData, cmd.contentFile)
 	if err != nil {
 		return result, common.NewError("upload_error", "Failed to upload the file. "+err.Error())
 	}
+	countUploaded(fileOutputData.Size)
 
 	result.ValidationRoot = fileOutputData.ValidationRoot
 	result.FixedMerkleRoot = fileOutputData.FixedMerkleRoot
@@ -138,11 +138,12 @@ func (cmd *UploadFileCommand) ProcessContent(ctx context.Context, allocationObj
 		FilePathHash: cmd.fileChanger.PathHash,
 		Size:         cmd.fileChanger.Size,
 	}
-	fileOutputData, err := filestore.GetFileStore().WriteFile(allocationObj.ID, connectionID, fileInputData, cmd.contentFile)
+	fileOutputData, err := filestore.WithContext(ctx).WriteFile(allocationObj.ID, connectionID, fileInputData, cmd.contentFile)
 	if err != nil {
 		logging.Logger.Error("UploadFileCommand.ProcessContent", zap.Error(err))
 		return result, common.NewError("upload_error", "Failed to write file. "+err.Error())
 	}
+	countUploaded(fileOutputData.Size)
 	result.Filename = cmd.fileChanger.Filename
 	result.ValidationRoot = fileOutputData.ValidationRoot
 	result.Size = fileOutputData.Size
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
 	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
 )
@@ -31,10 +32,29 @@ func (b *blobberGRPCService) Commit(ctx context.Context, req *blobbergrpc.Commit
 	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
 	r.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, _, err := CommitHandler(ctx, r)
+	resp, _, err := withStatusAudit(auditlog.OpCommit, WithStatusConnectionForWM(CommitHandler))(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
 	return convert.CommitWriteResponseCreator(resp), nil
 }
+
+func (b *blobberGRPCService) Rollback(ctx context.Context, req *blobbergrpc.RollbackRequest) (*blobbergrpc.RollbackResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"connection_id": {req.ConnectionId},
+		"write_marker":  {req.WriteMarker},
+	}
+
+	resp, _, err := withStatusAudit(auditlog.OpRollback, WithStatusConnectionForWM(RollbackHandler))(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RollbackResponseCreator(resp), nil
+}
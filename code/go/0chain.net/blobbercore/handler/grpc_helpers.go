@@ -1,21 +1,26 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 
 	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/gorilla/mux"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
 type gRPCHeaderMetadata struct {
-	Client          string
-	ClientKey       string
-	ClientSignature string
+	Client            string
+	ClientKey         string
+	ClientSignature   string
+	ClientSignatureV2 string
+	AllocationID      string
 }
 
 func registerGRPCServices(r *mux.Router, server *grpc.Server) {
@@ -26,7 +31,53 @@ func registerGRPCServices(r *mux.Router, server *grpc.Server) {
 
 	blobbergrpc.RegisterBlobberServiceServer(server, blobberService)
 	_ = blobbergrpc.RegisterBlobberServiceHandlerServer(context.Background(), grpcGatewayHandler, blobberService)
-	r.PathPrefix("/").Handler(grpcGatewayHandler)
+	r.PathPrefix("/").Handler(withGatewayTransaction(grpcGatewayHandler))
+}
+
+// withGatewayTransaction runs the calls of the gateway, which reach the
+// service without the interceptors of the gRPC server, in a transaction of the
+// meta store. The response is held back until the transaction is committed, or
+// rolled back when the call failed.
+func withGatewayTransaction(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := GetMetaDataStore().CreateTransaction(r.Context())
+		gw := &gatewayWriter{header: make(http.Header), code: http.StatusOK}
+		h.ServeHTTP(gw, r.WithContext(ctx))
+
+		tx := GetMetaDataStore().GetTransaction(ctx)
+		if gw.code >= http.StatusBadRequest {
+			if err := tx.Rollback().Error; err != nil {
+				logging.Logger.Error("couldn't rollback", zap.Error(err))
+			}
+		} else if err := tx.Commit().Error; err != nil {
+			http.Error(w, "error committing to meta store: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		for k, v := range gw.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(gw.code)
+		w.Write(gw.body.Bytes()) // nolint
+	})
+}
+
+type gatewayWriter struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (gw *gatewayWriter) Header() http.Header {
+	return gw.header
+}
+
+func (gw *gatewayWriter) WriteHeader(code int) {
+	gw.code = code
+}
+
+func (gw *gatewayWriter) Write(b []byte) (int, error) {
+	return gw.body.Write(b)
 }
 
 func getGRPCMetaDataFromCtx(ctx context.Context) *gRPCHeaderMetadata {
@@ -48,6 +99,8 @@ func getGRPCMetaDataFromCtx(ctx context.Context) *gRPCHeaderMetadata {
 	metaData.Client = getMetaData(common.ClientHeader)
 	metaData.ClientKey = getMetaData(common.ClientKeyHeader)
 	metaData.ClientSignature = getMetaData(common.ClientSignatureHeader)
+	metaData.ClientSignatureV2 = getMetaData(common.ClientSignatureHeaderV2)
+	metaData.AllocationID = getMetaData(common.AllocationIdHeader)
 	return metaData
 }
 
@@ -55,18 +108,31 @@ func httpRequestWithMetaData(r *http.Request, md *gRPCHeaderMetadata, alloc stri
 	r.Header.Set(common.ClientHeader, md.Client)
 	r.Header.Set(common.ClientKeyHeader, md.ClientKey)
 	r.Header.Set(common.ClientSignatureHeader, md.ClientSignature)
+	r.Header.Set(common.ClientSignatureHeaderV2, md.ClientSignatureV2)
+	r.Header.Set(common.AllocationIdHeader, md.AllocationID)
 	*r = *mux.SetURLVars(r, map[string]string{"allocation": alloc})
 }
 
 func CustomMatcher(key string) (string, bool) {
-	switch key {
-	case common.ClientHeader:
-		return key, true
-	case common.ClientKeyHeader:
-		return key, true
-	case common.ClientSignatureHeader:
+	// the gateway passes the keys canonicalized, X-App-Client-Id for X-App-Client-ID
+	switch http.CanonicalHeaderKey(key) {
+	case http.CanonicalHeaderKey(common.ClientHeader),
+		http.CanonicalHeaderKey(common.ClientKeyHeader),
+		http.CanonicalHeaderKey(common.ClientSignatureHeader),
+		http.CanonicalHeaderKey(common.ClientSignatureHeaderV2),
+		http.CanonicalHeaderKey(common.AllocationIdHeader):
 		return key, true
 	default:
 		return runtime.DefaultHeaderMatcher(key)
 	}
 }
+
+// withGRPCContext runs a handler written for WithTxHandler in the transaction
+// the gRPC middleware opened for the call.
+func withGRPCContext(ctx context.Context, r *http.Request, handler func(ctx *Context) (interface{}, error)) (interface{}, error) {
+	c, err := newContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return handler(c)
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"path"
 	"time"
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
@@ -11,12 +13,21 @@ import (
 
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	tollbooth "github.com/didip/tollbooth/v6"
+	"github.com/didip/tollbooth/v6/limiter"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -47,6 +58,90 @@ func unaryDatabaseTransactionInjector() grpc.UnaryServerInterceptor {
 	}
 }
 
+// grpcRateLimiter returns the rate limiter the HTTP route of the method is
+// limited by.
+func grpcRateLimiter(fullMethod string) *limiter.Limiter {
+	switch path.Base(fullMethod) {
+	case "Commit", "Rollback":
+		return commitRL
+	case "DownloadFile", "UploadFile", "DownloadFileStream", "UploadFileStream":
+		return fileRL
+	case "GetReferencePath", "GetObjectTree":
+		return objectRL
+	default:
+		return generalRL
+	}
+}
+
+func grpcRemoteIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func grpcRateLimit(ctx context.Context, fullMethod string) error {
+	lmt := grpcRateLimiter(fullMethod)
+	if lmt == nil {
+		return nil
+	}
+
+	keys := [][]string{{grpcRemoteIP(ctx)}, {getGRPCMetaDataFromCtx(ctx).Client}}
+	for _, k := range keys {
+		if httpError := tollbooth.LimitByKeys(lmt, k); httpError != nil {
+			logging.Logger.Error("Rate limit error", zap.String("method", fullMethod), zap.Error(httpError))
+			metrics.RateLimited(fullMethod)
+			return status.Error(codes.ResourceExhausted, httpError.Message)
+		}
+	}
+	return nil
+}
+
+func unaryRateLimitInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := grpcRateLimit(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamRateLimitInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := grpcRateLimit(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func unaryTracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, span := tracing.StartRPC(ctx, info.FullMethod, md)
+		defer func() { tracing.End(span, err) }()
+
+		return handler(ctx, req)
+	}
+}
+
+func streamTracingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		ctx, span := tracing.StartRPC(ss.Context(), info.FullMethod, md)
+		defer func() { tracing.End(span, err) }()
+
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
 func unaryTimeoutInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		deadline := time.Now().Add(TIMEOUT_SECONDS * time.Second)
@@ -62,10 +157,16 @@ func NewGRPCServerWithMiddlewares(r *mux.Router) *grpc.Server {
 		grpc.ChainStreamInterceptor(
 			grpc_zap.StreamServerInterceptor(logging.Logger),
 			grpc_recovery.StreamServerInterceptor(),
+			streamTracingInterceptor(),
+			streamRateLimitInterceptor(),
+			streamDrainInterceptor(),
 		),
 		grpc.ChainUnaryInterceptor(
 			grpc_zap.UnaryServerInterceptor(logging.Logger),
 			grpc_recovery.UnaryServerInterceptor(),
+			unaryTracingInterceptor(),
+			unaryRateLimitInterceptor(),
+			unaryDrainInterceptor(),
 			unaryDatabaseTransactionInjector(),
 			unaryTimeoutInterceptor(), // should always be the lastest, to be "innermost"
 		),
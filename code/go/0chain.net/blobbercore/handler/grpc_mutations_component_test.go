@@ -0,0 +1,276 @@
+//go:build !integration_tests
+// +build !integration_tests
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/didip/tollbooth/v6/limiter"
+	"github.com/gorilla/mux"
+	gomocket "github.com/selvatico/go-mocket"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcTestOwner is the owner of the allocation of the gRPC component tests.
+type grpcTestOwner struct {
+	wallet *zcncrypto.Wallet
+	alloc  *allocation.Allocation
+}
+
+func setupGRPCMutationTest(t *testing.T) (blobbergrpc.BlobberServiceClient, *grpcTestOwner) {
+	setup(t)
+	datastore.UseMocket(false)
+	gomocket.Catcher.Reset()
+
+	wallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+
+	alloc := makeTestAllocation(common.Timestamp(time.Now().Add(time.Hour).Unix()))
+	alloc.OwnerID = wallet.ClientID
+	alloc.OwnerPublicKey = wallet.ClientKey
+	allocation.Repo.DeleteAllocation(alloc.ID)
+	t.Cleanup(func() { allocation.Repo.DeleteAllocation(alloc.ID) })
+
+	gomocket.Catcher.NewMock().
+		WithQuery(`SELECT * FROM "allocations" WHERE`).
+		WithReply([]map[string]interface{}{{
+			"id":               alloc.ID,
+			"tx":               alloc.Tx,
+			"owner_id":         alloc.OwnerID,
+			"owner_public_key": alloc.OwnerPublicKey,
+			"expiration_date":  alloc.Expiration,
+			"file_options":     alloc.FileOptions,
+			"blobber_size":     int64(1 << 30),
+			"allocation_root":  "allocation_root",
+		}})
+
+	// the calls of the tests all come from the same client and address
+	rls := []*limiter.Limiter{commitRL, fileRL, objectRL, generalRL}
+	commitRL, fileRL, objectRL, generalRL = nil, nil, nil, nil
+	t.Cleanup(func() { commitRL, fileRL, objectRL, generalRL = rls[0], rls[1], rls[2], rls[3] })
+
+	startGRPCServer(t)
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return grpcCl, &grpcTestOwner{wallet: wallet, alloc: alloc}
+}
+
+func (o *grpcTestOwner) sign(t *testing.T, wallet *zcncrypto.Wallet, hash string) string {
+	scheme := zcncrypto.NewSignatureScheme("bls0chain")
+	require.NoError(t, scheme.SetPrivateKey(wallet.Keys[0].PrivateKey))
+	sign, err := scheme.Sign(hash)
+	require.NoError(t, err)
+	return sign
+}
+
+// ctx returns the context of a request of the client signed with its keys.
+func (o *grpcTestOwner) ctx(t *testing.T, wallet *zcncrypto.Wallet) context.Context {
+	sign := o.sign(t, wallet, encryption.Hash(o.alloc.Tx))
+	return metadata.NewOutgoingContext(context.Background(), metadata.New(map[string]string{
+		common.ClientHeader:          wallet.ClientID,
+		common.ClientKeyHeader:       wallet.ClientKey,
+		common.ClientSignatureHeader: sign,
+		common.AllocationIdHeader:    o.alloc.ID,
+	}))
+}
+
+func TestBlobberGRPCService_CreateDir(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+
+	req := &blobbergrpc.CreateDirRequest{Allocation: owner.alloc.Tx, DirPath: "/dir", ConnectionId: "connection_id"}
+	resp, err := grpcCl.CreateDir(owner.ctx(t, owner.wallet), req)
+	require.NoError(t, err)
+	require.Equal(t, "/dir", resp.Filename)
+}
+
+func TestBlobberGRPCService_Rollback(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+	config.Configuration.MaxChainLength = 32
+	config.Configuration.MarkerRedeemInterval = time.Minute
+
+	latest := writemarker.WriteMarker{
+		AllocationRoot:         "allocation_root",
+		PreviousAllocationRoot: "",
+		AllocationID:           owner.alloc.ID,
+		ChainHash:              "chain_hash",
+		BlobberID:              node.Self.ID,
+		Timestamp:              common.Now(),
+		ClientID:               owner.wallet.ClientID,
+	}
+	gomocket.Catcher.NewMock().
+		WithQuery(`SELECT * FROM "write_markers" WHERE allocation_root=`).
+		WithReply([]map[string]interface{}{{
+			"allocation_root":      latest.AllocationRoot,
+			"prev_allocation_root": latest.PreviousAllocationRoot,
+			"allocation_id":        latest.AllocationID,
+			"chain_hash":           latest.ChainHash,
+			"blobber_id":           latest.BlobberID,
+			"timestamp":            latest.Timestamp,
+			"client_id":            latest.ClientID,
+			"status":               writemarker.Committed,
+		}})
+
+	wm := writemarker.WriteMarker{
+		AllocationRoot:         latest.PreviousAllocationRoot,
+		PreviousAllocationRoot: latest.PreviousAllocationRoot,
+		AllocationID:           owner.alloc.ID,
+		ChainHash:              writemarker.CalculateChainHash(latest.ChainHash, ""),
+		BlobberID:              node.Self.ID,
+		Timestamp:              latest.Timestamp,
+		ClientID:               owner.wallet.ClientID,
+	}
+	wm.Signature = owner.sign(t, owner.wallet, encryption.Hash(wm.GetHashData()))
+	wmBytes, err := json.Marshal(wm)
+	require.NoError(t, err)
+
+	resp, err := grpcCl.Rollback(owner.ctx(t, owner.wallet), &blobbergrpc.RollbackRequest{
+		Allocation:   owner.alloc.Tx,
+		ConnectionId: "connection_id",
+		WriteMarker:  string(wmBytes),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Success)
+}
+
+func TestBlobberGRPCService_MutationsRefuseNonOwner(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+
+	other, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	ctx := owner.ctx(t, other)
+	tx := owner.alloc.Tx
+
+	calls := map[string]func() error{
+		"CreateDir": func() error {
+			_, err := grpcCl.CreateDir(ctx, &blobbergrpc.CreateDirRequest{Allocation: tx, DirPath: "/dir", ConnectionId: "connection_id"})
+			return err
+		},
+		"Rollback": func() error {
+			_, err := grpcCl.Rollback(ctx, &blobbergrpc.RollbackRequest{Allocation: tx, ConnectionId: "connection_id", WriteMarker: "{}"})
+			return err
+		},
+		"MoveObject": func() error {
+			_, err := grpcCl.MoveObject(ctx, &blobbergrpc.MoveObjectRequest{Allocation: tx, Path: "/file", Dest: "/dir", ConnectionId: "connection_id"})
+			return err
+		},
+		"DeleteFile": func() error {
+			_, err := grpcCl.DeleteFile(ctx, &blobbergrpc.DeleteFileRequest{Allocation: tx, Path: "/file", ConnectionId: "connection_id"})
+			return err
+		},
+		"WriteMarkerLock": func() error {
+			_, err := grpcCl.WriteMarkerLock(ctx, &blobbergrpc.WriteMarkerLockRequest{Allocation: tx, ConnectionId: "connection_id"})
+			return err
+		},
+		"InsertShareInfo": func() error {
+			_, err := grpcCl.InsertShareInfo(ctx, &blobbergrpc.InsertShareInfoRequest{Allocation: tx, AuthTicket: "{}"})
+			return err
+		},
+		"RevokeShareInfo": func() error {
+			_, err := grpcCl.RevokeShareInfo(ctx, &blobbergrpc.RevokeShareInfoRequest{Allocation: tx, Path: "/file", RefereeClientId: "referee"})
+			return err
+		},
+		"GetPlaylist": func() error {
+			_, err := grpcCl.GetPlaylist(ctx, &blobbergrpc.GetPlaylistRequest{Allocation: tx, Path: "/"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, call())
+		})
+	}
+}
+
+func TestBlobberGRPCService_DeleteFile_Blacklisted(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+
+	blMap.Lock()
+	blackListMap[owner.wallet.ClientID] = true
+	blMap.Unlock()
+	t.Cleanup(func() {
+		blMap.Lock()
+		delete(blackListMap, owner.wallet.ClientID)
+		blMap.Unlock()
+	})
+
+	_, err := grpcCl.DeleteFile(owner.ctx(t, owner.wallet), &blobbergrpc.DeleteFileRequest{
+		Allocation:   owner.alloc.Tx,
+		Path:         "/file",
+		ConnectionId: "connection_id",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "blacklist")
+}
+
+func TestBlobberGRPCService_GetPlaylist(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+
+	resp, err := grpcCl.GetPlaylist(owner.ctx(t, owner.wallet), &blobbergrpc.GetPlaylistRequest{
+		Allocation: owner.alloc.Tx,
+		Path:       "/",
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Files)
+}
+
+func TestBlobberGRPCService_WriteMarkerLock(t *testing.T) {
+	grpcCl, owner := setupGRPCMutationTest(t)
+
+	resp, err := grpcCl.WriteMarkerLock(owner.ctx(t, owner.wallet), &blobbergrpc.WriteMarkerLockRequest{
+		Allocation:   owner.alloc.Tx,
+		ConnectionId: "connection_id",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(writemarker.LockStatusOK), resp.Status)
+}
+
+// The gateway routes of the mutating RPCs reach the same handlers as the gRPC calls.
+func TestBlobberGRPCGateway_CreateDir(t *testing.T) {
+	_, owner := setupGRPCMutationTest(t)
+
+	router := mux.NewRouter()
+	NewGRPCServerWithMiddlewares(router)
+
+	sign := owner.sign(t, owner.wallet, encryption.Hash(owner.alloc.Tx))
+	newRequest := func(wallet *zcncrypto.Wallet, sign string) *http.Request {
+		body := `{"dir_path":"/dir","connection_id":"connection_id"}`
+		r := httptest.NewRequest(http.MethodPost, "/v2/dir/"+owner.alloc.Tx, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set(common.ClientHeader, wallet.ClientID)
+		r.Header.Set(common.ClientKeyHeader, wallet.ClientKey)
+		r.Header.Set(common.ClientSignatureHeader, sign)
+		r.Header.Set(common.AllocationIdHeader, owner.alloc.ID)
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(owner.wallet, sign))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), "/dir")
+
+	other, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newRequest(other, owner.sign(t, other, encryption.Hash(owner.alloc.Tx))))
+	require.NotEqual(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "invalid_signature")
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
+)
+
+func (b *blobberGRPCService) GetPlaylist(ctx context.Context, req *blobbergrpc.GetPlaylistRequest) (*blobbergrpc.GetPlaylistResponse, error) {
+	q := url.Values{}
+	q.Set("path", req.Path)
+	q.Set("since", req.Since)
+	q.Set("auth_token", req.AuthToken)
+	q.Set("lookup_hash", req.LookupHash)
+
+	r, err := http.NewRequest("GET", "?"+q.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+
+	resp, err := withGRPCContext(ctx, r, LoadPlaylist)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.GetPlaylistResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) GetPlaylistFile(ctx context.Context, req *blobbergrpc.GetPlaylistFileRequest) (*blobbergrpc.GetPlaylistFileResponse, error) {
+	q := url.Values{}
+	q.Set("lookup_hash", req.LookupHash)
+	q.Set("auth_token", req.AuthToken)
+
+	r, err := http.NewRequest("GET", "?"+q.Encode(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+
+	resp, err := withGRPCContext(ctx, r, LoadPlaylistFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.GetPlaylistFileResponseCreator(resp), nil
+}
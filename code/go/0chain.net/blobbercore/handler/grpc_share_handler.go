@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
+)
+
+func (b *blobberGRPCService) InsertShareInfo(ctx context.Context, req *blobbergrpc.InsertShareInfoRequest) (*blobbergrpc.InsertShareInfoResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"auth_ticket":           {req.AuthTicket},
+		"encryption_public_key": {req.EncryptionPublicKey},
+		"available_after":       {req.AvailableAfter},
+	}
+
+	resp, err := withAudit(auditlog.OpShareInsert, InsertShare)(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.InsertShareInfoResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) RevokeShareInfo(ctx context.Context, req *blobbergrpc.RevokeShareInfoRequest) (*blobbergrpc.RevokeShareInfoResponse, error) {
+	r, err := http.NewRequest("DELETE", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"path":            {req.Path},
+		"refereeClientID": {req.RefereeClientId},
+	}
+
+	resp, err := withAudit(auditlog.OpShareRevoke, RevokeShare)(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RevokeShareInfoResponseCreator(resp), nil
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+// DefaultStreamBlocksPerMessage is the number of blocks sent in each message
+// of a download stream when the client does not ask for another number.
+const DefaultStreamBlocksPerMessage = 16
+
+// the handlers of the messages of the streams, replaced in tests
+var (
+	streamDownloadHandler common.JSONResponderF = grpcDownloadHandler
+	streamUploadHandler   common.JSONResponderF = grpcUploadHandler
+)
+
+// withStreamTransaction handles a message of a stream in a transaction of the
+// stream context, unlike WithConnection, so that the filestore gives up the
+// read or the write of the message once the client cancels the stream.
+func withStreamTransaction(ctx context.Context, handler common.JSONResponderF, r *http.Request) (interface{}, error) {
+	ctx = datastore.GetStore().CreateTransaction(ctx)
+	tx := datastore.GetStore().GetTransaction(ctx)
+
+	resp, err := handler(ctx, r)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, common.NewErrorf("commit_error", "error committing to meta store: %v", err)
+	}
+	return resp, nil
+}
+
+// DownloadFileStream sends the requested blocks in batches of
+// blocks_per_message. Each batch is a download of its own, so quota, read
+// markers and auth tickets are accounted for as on the download endpoint. The
+// next batch is read from the filestore only once the previous one is sent, so
+// reading waits while Send is blocked by the flow control of the stream, and
+// stops once Send fails or the client cancels the stream.
+func (b *blobberGRPCService) DownloadFileStream(req *blobbergrpc.DownloadFileStreamRequest, stream blobbergrpc.BlobberService_DownloadFileStreamServer) error {
+	ctx := stream.Context()
+	md := getGRPCMetaDataFromCtx(ctx)
+
+	if req.BlockNum < 0 || req.NumBlocks <= 0 {
+		return common.NewError("download_file", "invalid block range")
+	}
+	batch := req.BlocksPerMessage
+	if batch <= 0 {
+		batch = DefaultStreamBlocksPerMessage
+	}
+
+	end := req.BlockNum + req.NumBlocks
+	for blockNum := req.BlockNum; blockNum < end; blockNum += batch {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		numBlocks := batch
+		if blockNum+numBlocks > end {
+			numBlocks = end - blockNum
+		}
+
+		r, err := convert.DownloadFileStreamGRPCToHTTP(req, blockNum, numBlocks)
+		if err != nil {
+			return err
+		}
+		httpRequestWithMetaData(r, md, req.Allocation)
+
+		resp, err := withStreamTransaction(ctx, streamDownloadHandler, r)
+		if err != nil {
+			return err
+		}
+
+		msg := convert.DownloadFileStreamResponseCreator(resp, blockNum, numBlocks)
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+
+		// the file ends before the requested range does
+		if int64(len(msg.Data)) < numBlocks*filestore.ChunkSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// UploadFileStream writes each chunk of the stream as UploadFile does, so the
+// chunks go through the same commit hasher as the chunks uploaded over HTTP.
+// A cancelled stream stops the write of the current chunk and leaves the chunks
+// written so far in the connection, to be resumed from their upload offset.
+func (b *blobberGRPCService) UploadFileStream(stream blobbergrpc.BlobberService_UploadFileStreamServer) error {
+	ctx := stream.Context()
+
+	result := &blobbergrpc.UploadFileStreamResponse{}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(result)
+		}
+		if err != nil {
+			return err
+		}
+		if req.Chunk == nil {
+			return common.NewError("invalid_parameters", "Missing chunk in upload stream")
+		}
+
+		r, err := grpcUploadRequest(ctx, req.Chunk)
+		if err != nil {
+			return err
+		}
+
+		resp, err := withStreamTransaction(ctx, streamUploadHandler, r)
+		if err != nil {
+			return err
+		}
+
+		result.NumChunks++
+		result.Result = convert.UploadFileResponseCreator(resp)
+		if result.Result != nil {
+			result.Size += result.Result.Size
+		}
+	}
+}
+
+func grpcUploadRequest(ctx context.Context, req *blobbergrpc.UploadFileRequest) (*http.Request, error) {
+	r, err := convert.WriteFileGRPCToHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"path":          {req.Path},
+		"connection_id": {req.ConnectionId},
+		"uploadMeta":    {req.UploadMeta},
+		"updateMeta":    {req.UpdateMeta},
+	}
+	return r, nil
+}
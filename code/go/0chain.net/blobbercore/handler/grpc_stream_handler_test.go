@@ -0,0 +1,182 @@
+//go:build !integration_tests
+// +build !integration_tests
+
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DownloadFileStream_InvalidRange(t *testing.T) {
+	setup(t)
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := grpcCl.DownloadFileStream(context.TODO(), &blobbergrpc.DownloadFileStreamRequest{
+		Allocation: "allocation_tx",
+		Path:       "/file.txt",
+		BlockNum:   0,
+		NumBlocks:  0,
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid block range")
+}
+
+func Test_DownloadFileStream_Batches(t *testing.T) {
+	setup(t)
+	datastore.UseMocket(false)
+	defer func() { streamDownloadHandler = grpcDownloadHandler }()
+
+	var blocks []string
+	streamDownloadHandler = func(ctx context.Context, r *http.Request) (interface{}, error) {
+		blocks = append(blocks, r.Header.Get("X-Block-Num")+"+"+r.Header.Get("X-Num-Blocks"))
+		numBlocks, _ := strconv.Atoi(r.Header.Get("X-Num-Blocks"))
+		return make([]byte, numBlocks*filestore.ChunkSize), nil
+	}
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := grpcCl.DownloadFileStream(context.TODO(), &blobbergrpc.DownloadFileStreamRequest{
+		Allocation:       "allocation_tx",
+		Path:             "/file.txt",
+		BlockNum:         1,
+		NumBlocks:        5,
+		BlocksPerMessage: 2,
+	})
+	require.NoError(t, err)
+
+	var got []int64
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.Len(t, msg.Data, int(msg.NumBlocks)*filestore.ChunkSize)
+		got = append(got, msg.BlockNum, msg.NumBlocks)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 2, 5, 1}, got)
+	assert.Equal(t, []string{"1+2", "3+2", "5+1"}, blocks)
+}
+
+func Test_DownloadFileStream_Cancel(t *testing.T) {
+	setup(t)
+	datastore.UseMocket(false)
+	defer func() { streamDownloadHandler = grpcDownloadHandler }()
+
+	var calls int32
+	cancelled := make(chan error, 1)
+	streamDownloadHandler = func(ctx context.Context, r *http.Request) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			// the cancellation of the stream reaches the batch being read
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return nil, ctx.Err()
+		}
+		return make([]byte, filestore.ChunkSize), nil
+	}
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	stream, err := grpcCl.DownloadFileStream(ctx, &blobbergrpc.DownloadFileStreamRequest{
+		Allocation:       "allocation_tx",
+		Path:             "/file.txt",
+		NumBlocks:        10,
+		BlocksPerMessage: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case err := <-cancelled:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream context was not cancelled")
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls), "no batch is read once the stream is cancelled")
+}
+
+func Test_UploadFileStream(t *testing.T) {
+	setup(t)
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Run("Empty", func(t *testing.T) {
+		stream, err := grpcCl.UploadFileStream(context.TODO())
+		require.NoError(t, err)
+
+		resp, err := stream.CloseAndRecv()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), resp.NumChunks)
+	})
+
+	t.Run("Missing_Chunk_ERR", func(t *testing.T) {
+		stream, err := grpcCl.UploadFileStream(context.TODO())
+		require.NoError(t, err)
+
+		err = stream.Send(&blobbergrpc.UploadFileStreamRequest{})
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+
+		_, err = stream.CloseAndRecv()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Missing chunk")
+	})
+}
+
+func Test_DownloadFileStreamResponseCreator(t *testing.T) {
+	resp := convert.DownloadFileStreamResponseCreator(&filestore.FileDownloadResponse{
+		Data:    []byte("data"),
+		Nodes:   [][][]byte{{[]byte("a"), []byte("b")}, {[]byte("c")}},
+		Indexes: [][]int{{0, 1}, {2}},
+	}, 4, 2)
+
+	assert.Equal(t, int64(4), resp.BlockNum)
+	assert.Equal(t, int64(2), resp.NumBlocks)
+	assert.Equal(t, []byte("data"), resp.Data)
+	require.Len(t, resp.Proofs, 2)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, resp.Proofs[0].Nodes)
+	assert.Equal(t, []int64{2}, resp.Proofs[1].Indexes)
+
+	resp = convert.DownloadFileStreamResponseCreator([]byte("raw"), 0, 1)
+	assert.Equal(t, []byte("raw"), resp.Data)
+	assert.Empty(t, resp.Proofs)
+}
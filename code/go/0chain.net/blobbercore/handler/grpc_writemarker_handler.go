@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
+	"github.com/gorilla/mux"
+)
+
+func (b *blobberGRPCService) WriteMarkerLock(ctx context.Context, req *blobbergrpc.WriteMarkerLockRequest) (*blobbergrpc.WriteMarkerLockResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"connection_id": {req.ConnectionId},
+	}
+
+	resp, err := withGRPCContext(ctx, r, LockWriteMarker)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.WriteMarkerLockResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) WriteMarkerUnlock(ctx context.Context, req *blobbergrpc.WriteMarkerUnlockRequest) (*blobbergrpc.WriteMarkerUnlockResponse, error) {
+	r, err := http.NewRequest("DELETE", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r = mux.SetURLVars(r, map[string]string{
+		"allocation": req.Allocation,
+		"connection": req.ConnectionId,
+	})
+
+	if _, err := withGRPCContext(ctx, r, UnlockWriteMarker); err != nil {
+		return nil, err
+	}
+
+	return &blobbergrpc.WriteMarkerUnlockResponse{}, nil
+}
@@ -0,0 +1,88 @@
+//go:build !integration_tests
+// +build !integration_tests
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_GRPCMetaDataForwarded(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.New(map[string]string{
+		common.ClientHeader:            "client",
+		common.ClientKeyHeader:         "key",
+		common.ClientSignatureHeader:   "sig",
+		common.ClientSignatureHeaderV2: "sig2",
+		common.AllocationIdHeader:      "allocation_id",
+	}))
+
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	require.NoError(t, err)
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), "allocation_tx")
+
+	assert.Equal(t, "client", r.Header.Get(common.ClientHeader))
+	assert.Equal(t, "key", r.Header.Get(common.ClientKeyHeader))
+	assert.Equal(t, "sig", r.Header.Get(common.ClientSignatureHeader))
+	assert.Equal(t, "sig2", r.Header.Get(common.ClientSignatureHeaderV2))
+	assert.Equal(t, "allocation_id", r.Header.Get(common.AllocationIdHeader))
+}
+
+func Test_WriteMarkerUnlock(t *testing.T) {
+	setup(t)
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	generalRL = common.GetRateLimiter(1, nil, true, time.Minute)
+	defer func() { generalRL = nil }()
+
+	mock := datastore.MockTheStore(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	req := &blobbergrpc.WriteMarkerUnlockRequest{Allocation: "allocation_tx", ConnectionId: "connection_id"}
+	_, err = grpcCl.WriteMarkerUnlock(context.TODO(), req)
+	require.NoError(t, err)
+
+	// the client is limited as on the HTTP route
+	_, err = grpcCl.WriteMarkerUnlock(context.TODO(), req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_Rollback_NoAllocationID(t *testing.T) {
+	setup(t)
+
+	startGRPCServer(t)
+
+	grpcCl, conn, err := makeTestClient()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	mock := datastore.MockTheStore(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	_, err = grpcCl.Rollback(context.TODO(), &blobbergrpc.RollbackRequest{
+		Allocation:   "allocation_tx",
+		ConnectionId: "connection_id",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_allocation_id")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -28,6 +28,8 @@ import (
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/admintoken"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
@@ -35,6 +37,8 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/stats"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
+
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
 )
 
@@ -108,19 +112,19 @@ func ConfigRateLimits() {
 }
 
 func RateLimitByFileRL(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
-	return common.RateLimit(handler, fileRL)
+	return common.RateLimit(withAllocationLimit(handler), fileRL)
 }
 
 func RateLimitByCommmitRL(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
-	return common.RateLimit(handler, commitRL)
+	return common.RateLimit(withAllocationLimit(handler), commitRL)
 }
 
 func RateLimitByObjectRL(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
-	return common.RateLimit(handler, objectRL)
+	return common.RateLimit(withAllocationLimit(handler), objectRL)
 }
 
 func RateLimitByGeneralRL(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
-	return common.RateLimit(handler, generalRL)
+	return common.RateLimit(withAllocationLimit(handler), generalRL)
 }
 
 func SetupSwagger() {
@@ -152,13 +156,13 @@ func WithBlobberRegistered(h http.Handler) http.Handler {
 func setupHandlers(s *mux.Router) {
 	ConfigRateLimits()
 
-	s.Use(UseRecovery, UseCors, WithBlobberRegistered)
+	s.Use(metrics.UseHTTP, UseRecovery, UseCors, WithBlobberRegistered)
 
 	s.HandleFunc("/_stats", RateLimitByCommmitRL(StatsHandler))
 
 	//object operations
 	s.HandleFunc("/v1/connection/create/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(CreateConnectionHandler)))).
+		RateLimitByGeneralRL(RefuseWhileDraining(common.ToJSONResponse(WithConnection(CreateConnectionHandler))))).
 		Methods(http.MethodPost)
 
 	s.HandleFunc("/v1/connection/redeem/{allocation}",
@@ -166,26 +170,26 @@ func setupHandlers(s *mux.Router) {
 		Methods(http.MethodPost, http.MethodOptions)
 
 	s.HandleFunc("/v1/file/rename/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(RenameHandler)))).
+		RateLimitByGeneralRL(RefuseWhileDraining(common.ToJSONResponse(WithConnection(RenameHandler))))).
 		Methods(http.MethodPost, http.MethodOptions)
 
 	s.HandleFunc("/v1/file/copy/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(CopyHandler)))).
+		RateLimitByGeneralRL(RefuseWhileDraining(common.ToJSONResponse(WithConnection(CopyHandler))))).
 		Methods(http.MethodPost, http.MethodOptions)
 
 	s.HandleFunc("/v1/file/move/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(MoveHandler)))).
+		RateLimitByGeneralRL(RefuseWhileDraining(common.ToJSONResponse(WithConnection(MoveHandler))))).
 		Methods(http.MethodPost, http.MethodOptions)
 
 	s.HandleFunc("/v1/dir/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(CreateDirHandler)))).
+		RateLimitByGeneralRL(RefuseWhileDraining(common.ToJSONResponse(WithConnection(CreateDirHandler))))).
 		Methods(http.MethodPost, http.MethodDelete, http.MethodOptions)
 
 	s.HandleFunc("/v1/connection/commit/{allocation}",
-		RateLimitByCommmitRL(common.ToStatusCode(WithStatusConnectionForWM(CommitHandler))))
+		RateLimitByCommmitRL(RefuseWhileDraining(common.ToStatusCode(withStatusAudit(auditlog.OpCommit, WithStatusConnectionForWM(CommitHandler))))))
 
 	s.HandleFunc("/v1/connection/rollback/{allocation}",
-		RateLimitByCommmitRL(common.ToStatusCode(WithStatusConnectionForWM(RollbackHandler))))
+		RateLimitByCommmitRL(RefuseWhileDraining(common.ToStatusCode(withStatusAudit(auditlog.OpRollback, WithStatusConnectionForWM(RollbackHandler))))))
 
 	//object info related apis
 	s.HandleFunc("/allocation",
@@ -197,6 +201,10 @@ func setupHandlers(s *mux.Router) {
 	s.HandleFunc("/v1/file/stats/{allocation}",
 		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(FileStatsHandler))))
 
+	s.HandleFunc("/v1/file/preflight/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(PreflightHandler)))).
+		Methods(http.MethodPost, http.MethodOptions)
+
 	s.HandleFunc("/v1/file/referencepath/{allocation}",
 		RateLimitByObjectRL(common.ToJSONResponse(WithReadOnlyConnection(ReferencePathHandler))))
 
@@ -215,35 +223,108 @@ func setupHandlers(s *mux.Router) {
 		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(RecentRefsRequestHandler)))).
 		Methods(http.MethodGet, http.MethodOptions)
 
-	// admin related
-	// Allowing admin api for debugging purpose only. Later on commented out line should be
-	// uncommented and line below it should be deleted
-
-	s.HandleFunc("/_debug", common.AuthenticateAdmin(common.ToJSONResponse(DumpGoRoutines)))
-	// s.HandleFunc("/_debug", RateLimitByCommmitRL(common.ToJSONResponse(DumpGoRoutines)))
-	s.HandleFunc("/_config", common.AuthenticateAdmin(common.ToJSONResponse(GetConfig)))
-	// s.HandleFunc("/_config", RateLimitByCommmitRL(common.ToJSONResponse(GetConfig)))
-	// s.HandleFunc("/_stats", common.AuthenticateAdmin(StatsHandler)))
+	// admin related, each call needs an admin token granting the scope of the route
+	s.HandleFunc("/_debug", AuthenticateAdmin(admintoken.ScopeDebug, common.ToJSONResponse(DumpGoRoutines)))
+	s.HandleFunc("/_config", AuthenticateAdmin(admintoken.ScopeConfig, common.ToJSONResponse(GetConfig)))
+	// s.HandleFunc("/_stats", AuthenticateAdmin(admintoken.ScopeReadStats, StatsHandler)))
 	s.HandleFunc("/objectlimit", RateLimitByCommmitRL(common.ToJSONResponse(GetObjectLimit)))
 
 	s.HandleFunc("/_logs", RateLimitByCommmitRL(common.ToJSONResponse(GetLogs)))
 
-	// s.HandleFunc("/_cleanupdisk", common.AuthenticateAdmin(common.ToJSONResponse(WithReadOnlyConnection(CleanupDiskHandler)))))
-	// s.HandleFunc("/_cleanupdisk", RateLimitByCommmitRL(common.ToJSONResponse(WithReadOnlyConnection(CleanupDiskHandler)))))
-	s.HandleFunc("/challengetimings", common.AuthenticateAdmin(common.ToJSONResponse(GetChallengeTimings)))
-	// s.HandleFunc("/challengetimings", RateLimitByCommmitRL(common.ToJSONResponse(GetChallengeTimings)))
+	s.HandleFunc("/_cleanupdisk", AuthenticateAdmin(admintoken.ScopeGC, common.ToJSONResponse(WithConnection(CleanupDiskHandler)))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/challengetimings", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetChallengeTimings)))
 	s.HandleFunc("/challenge-timings-by-challengeId", RateLimitByCommmitRL(common.ToJSONResponse(GetChallengeTiming)))
+	s.HandleFunc("/_challenge_failures", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetChallengeFailures))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_self_challenge", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetSelfChallengeResults))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_self_challenge/{allocation}", AuthenticateAdmin(admintoken.ScopeDebug, common.ToJSONResponse(WithReadOnlyConnection(RunSelfChallenge)))).
+		Methods(http.MethodPost)
+
+	s.HandleFunc("/_audit_log", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetAuditLog))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_audit_log/verify", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(VerifyAuditLog))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_audit_log/export", AuthenticateAdmin(admintoken.ScopeReadStats, ExportAuditLog)).
+		Methods(http.MethodGet)
+
+	// access rules and quota overrides of the clients
+	s.HandleFunc("/_clients", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetClientEnforcement))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_clients/access", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(SetAccessRule))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_clients/access", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(DeleteAccessRule))).
+		Methods(http.MethodDelete)
+	s.HandleFunc("/_clients/quota", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(SetQuotaOverride))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_clients/quota", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(DeleteQuotaOverride))).
+		Methods(http.MethodDelete)
+
+	// maintenance drain
+	s.HandleFunc("/_drain", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetDrainStatus))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_drain", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(StartDrain))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_drain", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(EndDrain))).
+		Methods(http.MethodDelete)
+
+	// rate limits of the allocations
+	s.HandleFunc("/_allocations/limits", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(GetAllocationRateLimits))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_allocations/{allocation}/limits", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(SetAllocationRateLimit))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_allocations/{allocation}/limits", AuthenticateAdmin(admintoken.ScopeOperator, common.ToJSONResponse(DeleteAllocationRateLimit))).
+		Methods(http.MethodDelete)
+
+	// write marker redemption
+	s.HandleFunc("/_writemarkers/{allocation}", AuthenticateAdmin(admintoken.ScopeReadStats, common.ToJSONResponse(WithReadOnlyConnection(GetWriteMarkerRedeemState)))).
+		Methods(http.MethodGet)
+	s.HandleFunc("/_writemarkers/{allocation}/redeem", AuthenticateAdmin(admintoken.ScopeRedemptionControl, common.ToJSONResponse(ForceRedeemWriteMarkers))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_writemarkers/{allocation}/pause", AuthenticateAdmin(admintoken.ScopeRedemptionControl, common.ToJSONResponse(PauseWriteMarkerRedeem))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_writemarkers/{allocation}/resume", AuthenticateAdmin(admintoken.ScopeRedemptionControl, common.ToJSONResponse(ResumeWriteMarkerRedeem))).
+		Methods(http.MethodPost)
+	s.HandleFunc("/_writemarkers/{allocation}/resync", AuthenticateAdmin(admintoken.ScopeRedemptionControl, common.ToJSONResponse(ResyncWriteMarkerRedeem))).
+		Methods(http.MethodPost)
 
 	// Generate auth ticket
-	s.HandleFunc("/v1/auth/generate", Authenticate0Box(common.ToJSONResponse(GenerateAuthTicket)))
+	s.HandleFunc("/v1/auth/generate", Authenticate0Box(common.ToJSONResponse(withAudit(auditlog.OpAuthTicket, GenerateAuthTicket))))
+
+	// auth tickets of an allocation
+	s.HandleFunc("/v1/auth/tickets/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(ListAuthTickets)))).
+		Methods(http.MethodOptions, http.MethodGet)
+
+	s.HandleFunc("/v1/auth/tickets/revoke/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(RevokeAuthTickets)))).
+		Methods(http.MethodOptions, http.MethodPost)
+
+	// pre-signed download urls
+	s.HandleFunc("/v1/file/presigned/{allocation}",
+		RateLimitByFileRL(ShapeDownload(common.ToByteStream(WithConnection(PresignedDownloadHandler))))).
+		Methods(http.MethodOptions, http.MethodGet)
+
+	s.HandleFunc("/v1/file/presign/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(PresignDownloadURL)))).
+		Methods(http.MethodOptions, http.MethodPost)
+
+	s.HandleFunc("/v1/file/presigned/revoke/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(RevokePresignedURLs)))).
+		Methods(http.MethodOptions, http.MethodPost)
+
+	s.HandleFunc("/v1/receipts/{allocation}",
+		RateLimitByGeneralRL(common.ToJSONResponse(WithReadOnlyConnection(ListDownloadReceipts)))).
+		Methods(http.MethodOptions, http.MethodGet)
 
 	//marketplace related
 	s.HandleFunc("/v1/marketplace/shareinfo/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(InsertShare)))).
+		RateLimitByGeneralRL(common.ToJSONResponse(withAudit(auditlog.OpShareInsert, WithConnection(InsertShare))))).
 		Methods(http.MethodOptions, http.MethodPost)
 
 	s.HandleFunc("/v1/marketplace/shareinfo/{allocation}",
-		RateLimitByGeneralRL(common.ToJSONResponse(WithConnection(RevokeShare)))).
+		RateLimitByGeneralRL(common.ToJSONResponse(withAudit(auditlog.OpShareRevoke, WithConnection(RevokeShare))))).
 		Methods(http.MethodOptions, http.MethodDelete)
 
 	// list files shared in this allocation
@@ -254,7 +335,7 @@ func setupHandlers(s *mux.Router) {
 	// lightweight http handler without heavy postgres transaction to improve performance
 
 	s.HandleFunc("/v1/writemarker/lock/{allocation}",
-		RateLimitByGeneralRL(WithTxHandler(LockWriteMarker))).
+		RateLimitByGeneralRL(RefuseWhileDraining(WaitWriteMarkerLock(WithTxHandler(LockWriteMarker))))).
 		Methods(http.MethodPost, http.MethodOptions)
 
 	s.HandleFunc("/v1/writemarker/lock/{allocation}/{connection}",
@@ -301,7 +382,7 @@ func WithConnection(handler common.JSONResponderF) common.JSONResponderF {
 			resp, err = handler(ctx, r)
 
 			return err
-		})
+		}, datastore.WithParent(ctx))
 		return resp, err
 	}
 }
@@ -581,6 +662,10 @@ func downloadHandler(ctx context.Context, r *http.Request) (interface{}, error)
 //	    description: Digital signature of the client used to verify the request if the X-Version is "v2"
 //	    in: header
 //	    type: string
+//	 +name: X-Presigned-Reads
+//	    description: If "true", the session of the read marker of the allocation owner pays for the reads of its pre-signed urls instead of a download.
+//	    in: header
+//	    type: string
 //
 // responses:
 //
@@ -1618,6 +1703,7 @@ func HandleShutdown(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			Logger.Info("Shutting down server")
+			auditlog.Close()
 			datastore.GetStore().Close()
 		}
 	}()
@@ -1726,6 +1812,7 @@ func RevokeShare(ctx context.Context, r *http.Request) (interface{}, error) {
 	if path == "" {
 		return nil, common.NewError("invalid_parameters", "Invalid file path")
 	}
+	auditlog.FromContext(ctx).Paths = []string{path}
 	refereeClientID, _ := common.GetField(r, "refereeClientID")
 	filePathHash := fileref.GetReferenceLookup(allocationID, path)
 	_, err = reference.GetLimitedRefFieldsByLookupHash(ctx, allocationID, filePathHash, []string{"id", "type"})
@@ -1854,6 +1941,7 @@ func InsertShare(ctx context.Context, r *http.Request) (interface{}, error) {
 	if err != nil {
 		return nil, common.NewError("invalid_parameters", "Invalid file path. "+err.Error())
 	}
+	auditlog.FromContext(ctx).Paths = []string{fileRef.Path}
 
 	authToken, err := storageHandler.verifyAuthTicket(ctx, authTicketString, allocationObj, fileRef, authTicket.ClientID, false)
 	if authToken == nil {
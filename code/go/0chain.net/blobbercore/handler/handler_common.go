@@ -15,7 +15,9 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/lock"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/0chain/gosdk/zcncore"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
@@ -41,15 +43,19 @@ func objectTreeHandler(ctx context.Context, r *http.Request) (interface{}, int,
 func commitHandler(ctx context.Context, r *http.Request) (interface{}, int, error) {
 	ctx = setupHandlerContext(ctx, r)
 
+	start := time.Now()
 	response, err := storageHandler.CommitWrite(ctx, r)
 	if err != nil {
 		if errors.Is(common.ErrFileWasDeleted, err) {
+			commitDuration.WithLabelValues("deleted").Observe(time.Since(start).Seconds())
 			return response, http.StatusNoContent, nil
 		}
+		commitDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
 		Logger.Error("commitHandler_request_failed", zap.Error(err))
 		return nil, http.StatusBadRequest, err
 	}
 
+	commitDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
 	return response, http.StatusOK, nil
 }
 
@@ -99,6 +105,7 @@ type BlobberInfo struct {
 	BlobberId        string      `json:"blobber_id"`
 	BlobberPublicKey string      `json:"public_key"`
 	BuildTag         string      `json:"build_tag"`
+	Status           string      `json:"status"`
 	Stats            interface{} `json:"stats"`
 }
 
@@ -110,6 +117,10 @@ func GetBlobberInfoJson() BlobberInfo {
 		BlobberId:        node.Self.ID,
 		BlobberPublicKey: node.Self.PublicKey,
 		BuildTag:         build.BuildTag,
+		Status:           BlobberStatusActive,
+	}
+	if isDraining() {
+		blobberInfo.Status = BlobberStatusDraining
 	}
 
 	return blobberInfo
@@ -131,8 +142,13 @@ func WithStatusConnectionForWM(handler common.StatusCodeResponderF) common.Statu
 		if !wmSet {
 			return nil, http.StatusBadRequest, common.NewError("pending_markers", "Committing marker set failed")
 		}
+		_, lockSpan := tracing.Start(ctx, "allocation.Lock", attribute.String("allocation_id", allocationID))
 		mutex.Lock()
+		lockSpan.End()
 		defer mutex.Unlock()
+
+		ctx, span := tracing.Start(ctx, "datastore.WithStatusConnectionForWM")
+		defer func() { tracing.End(span, err) }()
 		ctx = GetMetaDataStore().CreateTransaction(ctx)
 		tx := GetMetaDataStore().GetTransaction(ctx)
 		resp, statusCode, err = handler(ctx, r)
@@ -638,6 +638,12 @@ func TestHandlers_Download(t *testing.T) {
 							AddRow(reEncryptionKey, guestPublicEncryptedKey),
 					)
 
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "revoked","max_downloads","max_bytes","downloads","bytes_downloaded" FROM "auth_tickets"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}))
+
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "auth_tickets"`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				mock.ExpectCommit()
 			},
 			wantCode: http.StatusOK,
@@ -756,6 +762,12 @@ func TestHandlers_Download(t *testing.T) {
 							AddRow(reEncryptionKey, gpbk),
 					)
 
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "revoked","max_downloads","max_bytes","downloads","bytes_downloaded" FROM "auth_tickets"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}))
+
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "auth_tickets"`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				mock.ExpectCommit()
 			},
 			wantCode: http.StatusOK,
@@ -876,6 +888,12 @@ func TestHandlers_Download(t *testing.T) {
 							AddRow(reEncryptionKey, gpbk),
 					)
 
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "revoked","max_downloads","max_bytes","downloads","bytes_downloaded" FROM "auth_tickets"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"revoked"}))
+
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "auth_tickets"`)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
 				mock.ExpectCommit()
 			},
 			wantCode: http.StatusOK,
@@ -986,6 +1004,121 @@ func TestHandlers_Download(t *testing.T) {
 			wantCode: http.StatusBadRequest,
 			wantBody: "{\"code\":\"invalid_authticket\",\"error\":\"invalid_authticket: cannot verify auth ticket: invalid_parameters: Auth ticket is not valid for the resource being requested\"}\n\n",
 		},
+		{
+			name: "DownloadFile_Encrypted_Revoked_AuthTicket_Refused",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					handlerName := handlers["/v1/file/download/{allocation}"]
+					url, err := router.Get(handlerName).URL("allocation", alloc.Tx)
+					if err != nil {
+						t.Fatal()
+					}
+
+					remotePath := "/file.txt"
+					connectionID := zboxutil.NewConnectionId()
+					pathHash := fileref.GetReferenceLookup(alloc.Tx, remotePath)
+					authTicket, err := GetAuthTicketForEncryptedFile(ownerClient, alloc.ID, remotePath, pathHash, guestClient.ClientID, "")
+					if err != nil {
+						t.Fatal(err)
+					}
+					r, err := http.NewRequest(http.MethodGet, url.String(), nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					hash := encryption.Hash(alloc.Tx)
+					sign, err := guestClient.Sign(hash, signScheme)
+					if err != nil {
+						t.Fatal(err)
+					}
+					r.Header.Set("X-Path-Hash", pathHash)
+					r.Header.Set("X-Block-Num", fmt.Sprintf("%d", 1))
+					r.Header.Set("X-Num-Blocks", fmt.Sprintf("%d", 1))
+					r.Header.Set("X-Verify-Download", fmt.Sprint(true))
+					r.Header.Set("X-Connection-ID", connectionID)
+					r.Header.Set("X-Mode", DownloadContentFull)
+					r.Header.Set("X-Auth-Token", base64.StdEncoding.EncodeToString([]byte(authTicket)))
+					r.Header.Set(common.ClientSignatureHeader, sign)
+					r.Header.Set(common.ClientHeader, guestClient.ClientID)
+					r.Header.Set(common.ClientKeyHeader, guestClient.ClientKey)
+					r.Header.Set(common.AllocationIdHeader, alloc.ID)
+
+					return r
+				}(),
+			},
+			alloc: alloc,
+			begin: func() {
+				dataToEncrypt := "data_to_encrypt"
+				encMsg, err := ownerScheme.Encrypt([]byte(dataToEncrypt))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				header := make([]byte, EncryptionHeaderSize)
+				copy(header, encMsg.MessageChecksum+encMsg.OverallChecksum)
+				data := append(header, encMsg.EncryptedData...)
+				setMockFileBlock(data)
+			},
+			end: func() {
+				resetMockFileBlock()
+			},
+			setupDbMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "allocations" WHERE`)).
+					WithArgs(alloc.Tx).
+					WillReturnRows(
+						sqlmock.NewRows(
+							[]string{
+								"id", "tx", "expiration_date", "owner_public_key", "owner_id", "blobber_size",
+							},
+						).
+							AddRow(
+								alloc.ID, alloc.Tx, alloc.Expiration, alloc.OwnerPublicKey, alloc.OwnerID, int64(1<<30),
+							),
+					)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "terms" WHERE`)).
+					WithArgs(alloc.ID).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "allocation_id"}).
+							AddRow(alloc.Terms[0].ID, alloc.Terms[0].AllocationID),
+					)
+
+				filePathHash := fileref.GetReferenceLookup(alloc.Tx, "/file.txt")
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reference_objects" WHERE`)).
+					WithArgs(filePathHash).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"path", "type", "path_hash", "lookup_hash", "validation_root", "encrypted_key", "chunk_size"}).
+							AddRow("/file.txt", "f", filePathHash, filePathHash, "validation_root", ownerScheme.GetEncryptedKey(), 65536),
+					)
+
+				guestPublicEncryptedKey, err := guestScheme.GetPublicKey()
+				if err != nil {
+					t.Fatal(err)
+				}
+				reEncryptionKey, err := ownerScheme.GetReGenKey(guestPublicEncryptedKey, "filetype:audio")
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "marketplace_share_info" WHERE`)).
+					WithArgs(guestClient.ClientID, filePathHash).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"re_encryption_key", "client_encryption_public_key"}).
+							AddRow(reEncryptionKey, guestPublicEncryptedKey),
+					)
+
+				mock.ExpectQuery(regexp.QuoteMeta(`SELECT "revoked","max_downloads","max_bytes","downloads","bytes_downloaded" FROM "auth_tickets"`)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"revoked", "max_downloads", "max_bytes", "downloads", "bytes_downloaded"}).
+							AddRow(true, 0, 0, 1, 65536),
+					)
+			},
+			wantCode: http.StatusBadRequest,
+			wantBody: "{\"code\":\"auth_ticket_revoked\",\"error\":\"auth_ticket_revoked: auth ticket revoked\"}\n\n",
+		},
 	}
 
 	tests := append(positiveTests, negativeTests...)
@@ -28,9 +28,9 @@ func SetupHandlers(r *mux.Router) {
 		RateLimitByObjectRL(common.ToJSONOrNotResponse(WithConnectionNotRespond(ListHandler)))).
 		Methods(http.MethodGet, http.MethodOptions)
 	s.HandleFunc("/v1/file/upload/{allocation}",
-		RateLimitByFileRL(common.ToJSONOrNotResponse(WithConnectionNotRespond(UploadHandler))))
+		RateLimitByFileRL(RefuseWhileDraining(ShapeUpload(common.ToJSONOrNotResponse(WithConnectionNotRespond(UploadHandler))))))
 	s.HandleFunc("/v1/file/download/{allocation}",
-		RateLimitByFileRL(ToByteStreamOrNot(WithConnectionNotRespond(DownloadHandler)))).
+		RateLimitByFileRL(ShapeDownload(ToByteStreamOrNot(WithConnectionNotRespond(DownloadHandler))))).
 		Methods(http.MethodGet, http.MethodOptions)
 }
 
@@ -75,13 +75,18 @@ func WithConnectionNotRespond(handler common.JSONResponderOrNotF) common.JSONRes
 
 func ToByteStreamOrNot(handler common.JSONResponderOrNotF) common.ReqRespHandlerf {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+		ctx := context.WithValue(r.Context(), rangeDownloadKey{}, true)
 		data, err, shouldRespond := handler(ctx, r)
 
 		if !shouldRespond {
 			return
 		}
 
+		if rd, ok := data.(*rangeDownload); ok && err == nil {
+			rd.write(w)
+			return
+		}
+
 		if err != nil {
 			if cerr, ok := err.(*common.Error); ok {
 				w.Header().Set(common.AppErrorHeader, cerr.Code)
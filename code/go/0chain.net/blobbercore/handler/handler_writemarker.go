@@ -1,11 +1,47 @@
 package handler
 
 import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"go.uber.org/zap"
 )
 
+// maxLockWait caps the long poll of the write marker lock, under the write
+// timeout of the server.
+const maxLockWait = 25 * time.Second
+
+// WaitWriteMarkerLock long polls the write marker lock. With wait, a connection
+// queued for the lock waits for its turn, for at most wait seconds, before
+// asking for the lock again, instead of polling. The allocation is the one of
+// the request verified as for LockWriteMarker, and the wait happens out of the
+// transaction of the handler.
+func WaitWriteMarkerLock(handler common.ReqRespHandlerf) common.ReqRespHandlerf {
+	return func(w http.ResponseWriter, r *http.Request) {
+		common.TryParseForm(r)
+		if wait, err := strconv.Atoi(r.FormValue("wait")); err == nil && wait > 0 {
+			var allocationID string
+			err := datastore.GetStore().WithNewTransaction(func(c context.Context) error {
+				ctx, err := newContext(c, r)
+				allocationID = ctx.AllocationId
+				return err
+			}, datastore.WithParent(r.Context()))
+			// a request failing the verification is refused by the handler
+			if err == nil {
+				d := min(time.Duration(wait)*time.Second, maxLockWait)
+				writemarker.WriteMarkerMutext.Wait(r.Context(), allocationID, r.FormValue("connection_id"), d)
+			}
+		}
+		handler(w, r)
+	}
+}
+
 // swagger:route POST /v1/writemarker/lock/{allocation} PostLockWriteMarker
 // Lock a write marker.
 // LockWriteMarker try to lock writemarker for specified allocation id.
@@ -44,6 +80,11 @@ import (
 //     in: query
 //     type: string
 //     required: true
+//  +name: wait
+//     description: Seconds a connection queued for the lock waits for its turn before the lock is asked for again, at most 25. The lock is asked for at once by default.
+//     in: query
+//     type: integer
+//     required: false
 //
 // responses:
 //   200: WriteMarkerLockResult
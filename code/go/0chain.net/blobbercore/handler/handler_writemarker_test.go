@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"mime/multipart"
 	"net/http"
@@ -10,10 +11,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/gorilla/mux"
+	gomocket "github.com/selvatico/go-mocket"
 	"github.com/stretchr/testify/require"
 )
 
@@ -92,3 +98,57 @@ func TestWriteMarkerHandlers_Unlock(t *testing.T) {
 	require.Equal(t, http.StatusOK, rr.Code)
 
 }
+
+func TestWaitWriteMarkerLock(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 30 * time.Second
+	datastore.UseMocket(false)
+	gomocket.Catcher.Reset()
+
+	wallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	alloc := &allocation.Allocation{
+		ID:         "wait_alloc",
+		Tx:         "wait_alloc",
+		Expiration: common.Timestamp(time.Now().Add(time.Hour).Unix()),
+	}
+	allocation.Repo.DeleteAllocation(alloc.ID)
+	t.Cleanup(func() { allocation.Repo.DeleteAllocation(alloc.ID) })
+	gomocket.Catcher.NewMock().
+		WithQuery(`SELECT * FROM "allocations" WHERE`).
+		WithReply([]map[string]interface{}{{
+			"id":               alloc.ID,
+			"tx":               alloc.Tx,
+			"owner_id":         wallet.ClientID,
+			"owner_public_key": wallet.ClientKey,
+			"expiration_date":  alloc.Expiration,
+		}})
+
+	ctx := context.Background()
+	_, err = writemarker.WriteMarkerMutext.Lock(ctx, alloc.ID, "holder")
+	require.NoError(t, err)
+	_, err = writemarker.WriteMarkerMutext.Lock(ctx, alloc.ID, "queued")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = writemarker.WriteMarkerMutext.Unlock(alloc.ID, "holder") })
+
+	scheme := zcncrypto.NewSignatureScheme("bls0chain")
+	require.NoError(t, scheme.SetPrivateKey(wallet.Keys[0].PrivateKey))
+	sign, err := scheme.Sign(encryption.Hash(alloc.Tx))
+	require.NoError(t, err)
+
+	wait := func(sign string) time.Duration {
+		r := httptest.NewRequest(http.MethodPost, "/v1/writemarker/lock/"+alloc.Tx+"?wait=1&connection_id=queued", nil)
+		r = mux.SetURLVars(r, map[string]string{"allocation": alloc.Tx})
+		r.Header.Set(common.ClientHeader, wallet.ClientID)
+		r.Header.Set(common.ClientKeyHeader, wallet.ClientKey)
+		r.Header.Set(common.ClientSignatureHeader, sign)
+		r.Header.Set(common.AllocationIdHeader, alloc.ID)
+		start := time.Now()
+		WaitWriteMarkerLock(func(w http.ResponseWriter, r *http.Request) {})(httptest.NewRecorder(), r)
+		return time.Since(start)
+	}
+
+	// a queued connection of the allocation waits for its turn
+	require.GreaterOrEqual(t, wait(sign), time.Second)
+	// an unverified request does not
+	require.Less(t, wait("invalid"), time.Second)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	transferredBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blobber_transferred_bytes_total",
+		Help: "Bytes of file data uploaded to and downloaded from the blobber.",
+	}, []string{"direction"})
+
+	commitDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blobber_commit_duration_seconds",
+		Help:    "Duration of the commits of the write markers, by result.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"result"})
+)
+
+func countUploaded(n int64) {
+	transferredBytes.WithLabelValues("upload").Add(float64(n))
+}
+
+func countDownloaded(n int) {
+	transferredBytes.WithLabelValues("download").Add(float64(n))
+}
@@ -49,3 +49,61 @@ func (b *blobberGRPCService) RenameObject(ctx context.Context, req *blobbergrpc.
 
 	return convert.RenameObjectResponseCreator(resp), nil
 }
+
+func (b *blobberGRPCService) MoveObject(ctx context.Context, req *blobbergrpc.MoveObjectRequest) (*blobbergrpc.MoveObjectResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"path":          {req.Path},
+		"path_hash":     {req.PathHash},
+		"connection_id": {req.ConnectionId},
+		"dest":          {req.Dest},
+	}
+
+	resp, err := MoveHandler(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.MoveObjectResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) CreateDir(ctx context.Context, req *blobbergrpc.CreateDirRequest) (*blobbergrpc.CreateDirResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Form = map[string][]string{
+		"dir_path":      {req.DirPath},
+		"connection_id": {req.ConnectionId},
+		"custom_meta":   {req.CustomMeta},
+	}
+
+	resp, err := CreateDirHandler(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.CreateDirResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) RedeemReadMarker(ctx context.Context, req *blobbergrpc.RedeemReadMarkerRequest) (*blobbergrpc.RedeemReadMarkerResponse, error) {
+	r, err := http.NewRequest("POST", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
+	r.Header.Set("X-Connection-ID", req.ConnectionId)
+	r.Header.Set("X-Read-Marker", req.ReadMarker)
+
+	resp, err := RedeemHandler(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RedeemReadMarkerResponseCreator(resp), nil
+}
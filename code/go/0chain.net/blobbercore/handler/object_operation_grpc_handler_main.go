@@ -5,6 +5,7 @@ package handler
 
 import (
 	"context"
+	"net/http"
 
 	blobbergrpc "github.com/0chain/blobber/code/go/0chain.net/blobbercore/blobbergrpc/proto"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/convert"
@@ -27,17 +28,28 @@ func (b *blobberGRPCService) DownloadFile(ctx context.Context, req *blobbergrpc.
 }
 
 func (b *blobberGRPCService) UploadFile(ctx context.Context, req *blobbergrpc.UploadFileRequest) (*blobbergrpc.UploadFileResponse, error) {
-	r, err := convert.WriteFileGRPCToHTTP(req)
+	r, err := grpcUploadRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	resp, err := UploadHandler(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.UploadFileResponseCreator(resp), nil
+}
+
+func (b *blobberGRPCService) DeleteFile(ctx context.Context, req *blobbergrpc.DeleteFileRequest) (*blobbergrpc.DeleteFileResponse, error) {
+	r, err := http.NewRequest("DELETE", "", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
 	httpRequestWithMetaData(r, getGRPCMetaDataFromCtx(ctx), req.Allocation)
 	r.Form = map[string][]string{
 		"path":          {req.Path},
 		"connection_id": {req.ConnectionId},
-		"uploadMeta":    {req.UploadMeta},
-		"updateMeta":    {req.UpdateMeta},
 	}
 
 	resp, err := UploadHandler(ctx, r)
@@ -45,5 +57,13 @@ func (b *blobberGRPCService) UploadFile(ctx context.Context, req *blobbergrpc.Up
 		return nil, err
 	}
 
-	return convert.UploadFileResponseCreator(resp), nil
+	return convert.DeleteFileResponseCreator(resp), nil
+}
+
+func grpcDownloadHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	return DownloadHandler(ctx, r)
+}
+
+func grpcUploadHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	return UploadHandler(ctx, r)
 }
@@ -19,6 +19,8 @@ import (
 	"github.com/0chain/gosdk/constants"
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/auditlog"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
@@ -66,7 +68,7 @@ func readPreRedeem(
 
 	requiredBalance := alloc.GetRequiredReadBalance(blobberID, numBlocks+pendNumBlocks)
 	if float64(readPoolBalance) < requiredBalance {
-		rp, err := allocation.RequestReadPoolStat(payerID)
+		rp, err := allocation.RequestReadPoolStat(ctx, payerID)
 		if err != nil {
 			return common.NewErrorf("read_pre_redeem", "can't request read pools from sharders: %v", err)
 		}
@@ -124,7 +126,7 @@ func writePreRedeem(ctx context.Context, alloc *allocation.Allocation, writeMark
 	requiredBalance = alloc.GetRequiredWriteBalance(blobberID, pendingWriteSize+writeMarker.Size, writeMarker.Timestamp)
 
 	if writePoolBalance < requiredBalance {
-		wp, err = allocation.RequestWritePool(alloc.ID)
+		wp, err = allocation.RequestWritePool(ctx, alloc.ID)
 		if err != nil {
 			return common.NewErrorf("write_pre_redeem", "can't request write pools from sharders: %v", err)
 		}
@@ -216,6 +218,10 @@ func (fsh *StorageHandler) RedeemReadMarker(ctx context.Context, r *http.Request
 		}
 	}
 
+	if dr.PresignedReads && clientID != alloc.OwnerID {
+		return nil, common.NewError("redeem_readmarker", "only the owner of the allocation pays for pre-signed reads")
+	}
+
 	// check out read pool tokens if read_price > 0
 	err = readPreRedeem(ctx, alloc, dr.ReadMarker.SessionRC, pendNumBlocks, clientID)
 	if err != nil {
@@ -247,11 +253,23 @@ func (fsh *StorageHandler) RedeemReadMarker(ctx context.Context, r *http.Request
 		return nil, common.NewError("redeem_readmarker", "couldn't save latest read marker")
 	}
 
-	quotaManager.createOrUpdateQuota(dr.ReadMarker.SessionRC, dr.ConnectionID)
+	// the session of a marker paying for pre-signed reads is added to the
+	// blocks the pre-signed urls of the allocation are served from
+	var presignedBlocks int64
+	if dr.PresignedReads {
+		presignedBlocks, err = readmarker.AddPresignedBlocks(ctx, alloc.ID, dr.ReadMarker.SessionRC)
+		if err != nil {
+			Logger.Error(err.Error())
+			return nil, common.NewError("redeem_readmarker", "couldn't save pre-signed url blocks")
+		}
+	} else {
+		quotaManager.createOrUpdateQuota(dr.ReadMarker.SessionRC, dr.ConnectionID)
+	}
 	Logger.Info("readmarker_saved", zap.Any("rmObj", rmObj))
 	return &blobberhttp.DownloadResponse{
-		Success:  true,
-		LatestRM: &dr.ReadMarker,
+		Success:         true,
+		LatestRM:        &dr.ReadMarker,
+		PresignedBlocks: presignedBlocks,
 	}, nil
 }
 
@@ -260,6 +278,9 @@ func (fsh *StorageHandler) RedeemReadMarker(ctx context.Context, r *http.Request
 //
 // Download Handler (downloadFile). The response is either a byte stream or a FileDownloadResponse, which contains the file data or the thumbnail data, and the merkle proof if the download is verified.
 // This depends on the "X-Verify-Download" header. If the header is set to "true", the response is a FileDownloadResponse, otherwise it is a byte stream.
+// A byte stream download without "X-Block-Num" and "X-Num-Blocks" headers is a download of the whole file, or of the byte range of the "Range" header, mapped onto 64 KB blocks.
+// A file or a range over more blocks than a request may read is answered with 206 and the first blocks of it, in "Content-Range".
+// Such downloads carry the validation root of the file as ETag.
 //
 // parameters:
 //   +name: allocation
@@ -326,6 +347,10 @@ func (fsh *StorageHandler) RedeemReadMarker(ctx context.Context, r *http.Request
 //     description: If set to "true", the download should be verified. If the mode is "thumbnail", the thumbnail hash stored in the db is compared with the hash of the actual file. If the mode is "full", merkle proof is calculated and returned in the response.
 //     in: header
 //     type: string
+//  +name: X-Download-Receipt
+//     description: If set to "true", the response is a DownloadReceiptResponse carrying the data along with a receipt of the served blocks signed by the blobber.
+//     in: header
+//     type: string
 //  +name: X-Version
 //     description: If its value is "v2" then both allocation_id and blobber url base are hashed and verified using X-App-Client-Signature-V2.
 //     in: header
@@ -338,11 +363,22 @@ func (fsh *StorageHandler) RedeemReadMarker(ctx context.Context, r *http.Request
 //     description: Digital signature of the client used to verify the request if the X-Version is "v2"
 //     in: header
 //     type: string
+//  +name: Range
+//     description: A single "bytes=" range of the file to download. Ignored if "X-Block-Num" or "X-Num-Blocks" is set.
+//     in: header
+//     type: string
+//  +name: If-None-Match
+//     description: The ETags of the cached copies of the file. Nothing is downloaded, and no quota is used, if one of them is the ETag of the file.
+//     in: header
+//     type: string
 //
 // responses:
 //
 //   200: FileDownloadResponse
+//   206:
+//   304:
 //   400:
+//   416:
 
 func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (interface{}, error) {
 	// get client and allocation ids
@@ -361,7 +397,7 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		return nil, common.NewError("download_file", "invalid client")
 	}
 
-	if ok := CheckBlacklist(clientID); ok {
+	if ok := CheckBlacklist(clientID, remoteIP(ctx, r)); ok {
 		return nil, common.NewError("blacklisted_client", "Client is blacklisted: "+clientID)
 	}
 
@@ -375,15 +411,6 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		return nil, err
 	}
 
-	if dr.NumBlocks > config.Configuration.BlockLimitRequest {
-		return nil, common.NewErrorf("download_file", "too many blocks requested: %v, max limit is %v", dr.NumBlocks, config.Configuration.BlockLimitRequest)
-	}
-
-	dailyBlocksConsumed := getDailyBlocks(clientID)
-	if dailyBlocksConsumed+dr.NumBlocks > config.Configuration.BlockLimitDaily {
-		return nil, common.NewErrorf("download_file", "daily block limit reached: %v, max limit is %v", dailyBlocksConsumed, config.Configuration.BlockLimitDaily)
-	}
-
 	fileref, err := reference.GetReferenceByLookupHash(ctx, alloc.ID, dr.PathHash)
 	if err != nil {
 		return nil, common.NewErrorf("download_file", "invalid file path: %v", err)
@@ -393,6 +420,18 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		return nil, common.NewErrorf("download_file", "path is not a file: %v", err)
 	}
 
+	// the blocks of a byte range download are known once the file is
+	rd := newRangeDownload(ctx, dr, fileref)
+
+	if dr.NumBlocks > config.Configuration.BlockLimitRequest {
+		return nil, common.NewErrorf("download_file", "too many blocks requested: %v, max limit is %v", dr.NumBlocks, config.Configuration.BlockLimitRequest)
+	}
+
+	dailyBlocksConsumed := getDailyBlocks(clientID)
+	if blockLimitDaily := getClientLimits(clientID).BlockLimitDaily; dailyBlocksConsumed+dr.NumBlocks > blockLimitDaily {
+		return nil, common.NewErrorf("download_file", "daily block limit reached: %v, max limit is %v", dailyBlocksConsumed, blockLimitDaily)
+	}
+
 	isOwner := clientID == alloc.OwnerID
 
 	var authToken *readmarker.AuthTicket
@@ -431,6 +470,11 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 			return nil, common.NewErrorf("download_file", "the file is not available until: %v", shareInfo.AvailableAt.UTC().Format("2006-01-02T15:04:05"))
 		}
 
+		newDownload := dr.DownloadMode != DownloadContentThumb && dr.BlockNum == 0
+		if err := readmarker.CheckAuthTicket(ctx, authToken, newDownload); err != nil {
+			return nil, err
+		}
+
 	} else {
 		if dr.Version == "v2" {
 			valid, err := verifySignatureFromRequest(allocationTx, r.Header.Get(common.ClientSignatureHeader), r.Header.Get(common.ClientSignatureHeaderV2), alloc.OwnerPublicKey)
@@ -440,6 +484,20 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		}
 	}
 
+	if rd != nil {
+		if rd.matches(dr.IfNoneMatch) {
+			rd.notModified = true
+			return rd, nil
+		}
+		if rd.unsatisfiable {
+			return rd, nil
+		}
+		// the plaintext offsets of the range do not map onto the ciphertext blocks
+		if rd.partial && len(fileref.EncryptedKey) > 0 {
+			return nil, common.NewError("download_file", "range requests are not supported for encrypted files")
+		}
+	}
+
 	isReadFree := alloc.IsReadFree(blobberID)
 	var dq *DownloadQuota
 
@@ -481,7 +539,7 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		}
 
 		logging.Logger.Info("calling GetFileBlock for thumb", zap.Any("rbi", rbi))
-		fileDownloadResponse, err = filestore.GetFileStore().GetFileBlock(rbi)
+		fileDownloadResponse, err = filestore.WithContext(ctx).GetFileBlock(rbi)
 		if err != nil {
 			return nil, common.NewErrorf("download_file", "couldn't get thumbnail block: %v", err)
 		}
@@ -502,7 +560,7 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 			FilestoreVersion: fileref.FilestoreVersion,
 		}
 		logging.Logger.Info("calling GetFileBlock", zap.Any("rbi", rbi))
-		fileDownloadResponse, err = filestore.GetFileStore().GetFileBlock(rbi)
+		fileDownloadResponse, err = filestore.WithContext(ctx).GetFileBlock(rbi)
 		if err != nil {
 			return nil, common.NewErrorf("download_file", "couldn't get file block: %v", err)
 		}
@@ -524,6 +582,14 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 		return nil, err
 	}
 
+	if authToken != nil {
+		// a download of the whole file starts with its first block
+		newDownload := downloadMode != DownloadContentThumb && dr.BlockNum == 0
+		if err := readmarker.UseAuthTicket(ctx, authToken, int64(len(chunkData)), newDownload); err != nil {
+			return nil, err
+		}
+	}
+
 	if !isReadFree {
 		err = quotaManager.consumeQuota(dr.ConnectionID, dr.NumBlocks)
 		if err != nil {
@@ -532,10 +598,25 @@ func (fsh *StorageHandler) DownloadFile(ctx context.Context, r *http.Request) (i
 	}
 
 	fileDownloadResponse.Data = chunkData
+	countDownloaded(len(chunkData))
 	reference.FileBlockDownloaded(ctx, fileref, dr.NumBlocks)
 	go func() {
 		addDailyBlocks(clientID, dr.NumBlocks)
 	}()
+	if rd != nil {
+		rd.setData(fileDownloadResponse.Data)
+		return rd, nil
+	}
+	if dr.Receipt {
+		receipt, err := readmarker.IssueDownloadReceipt(ctx, alloc.ID, clientID, dr.PathHash, dr.BlockNum, dr.NumBlocks)
+		if err != nil {
+			return nil, common.NewErrorf("download_file", "couldn't issue download receipt: %v", err)
+		}
+		return &blobberhttp.DownloadReceiptResponse{
+			FileDownloadResponse: fileDownloadResponse,
+			Receipt:              receipt,
+		}, nil
+	}
 	if !dr.VerifyDownload {
 		return fileDownloadResponse.Data, nil
 	}
@@ -606,7 +687,7 @@ func (fsh *StorageHandler) CommitWrite(ctx context.Context, r *http.Request) (*b
 		return nil, common.NewError("invalid_parameters", "Please provide clientID and clientKey")
 	}
 
-	if ok := CheckBlacklist(clientID); ok {
+	if ok := CheckBlacklist(clientID, remoteIP(ctx, r)); ok {
 		return nil, common.NewError("blacklisted_client", "Client is blacklisted: "+clientID)
 	}
 
@@ -650,6 +731,10 @@ func (fsh *StorageHandler) CommitWrite(ctx context.Context, r *http.Request) (*b
 			"Invalid connection id. Connection does not have any changes.")
 	}
 
+	ae := auditlog.FromContext(ctx)
+	ae.AllocationID = allocationID
+	ae.Paths = connectionObj.AffectedPaths()
+
 	elapsedGetConnObj := time.Since(startTime) - elapsedAllocation - elapsedGetLock
 
 	if allocationObj.OwnerID != clientID || encryption.Hash(clientKeyBytes) != clientID {
@@ -667,6 +752,7 @@ func (fsh *StorageHandler) CommitWrite(ctx context.Context, r *http.Request) (*b
 			"Invalid parameters. Error parsing the writemarker for commit: %v",
 			err)
 	}
+	ae.WriteMarkerRoot = writeMarker.AllocationRoot
 
 	var result blobberhttp.CommitResult
 	var latestWriteMarkerEntity *writemarker.WriteMarkerEntity
@@ -831,6 +917,7 @@ func (fsh *StorageHandler) CommitWrite(ctx context.Context, r *http.Request) (*b
 	if err = allocation.Repo.UpdateAllocation(ctx, allocationObj, updateMap, updateOption); err != nil {
 		return nil, common.NewError("allocation_write_error", "Error persisting the allocation object")
 	}
+	challenge.InvalidateObjectPaths(allocationID)
 
 	elapsedSaveAllocation := time.Since(startTime) - elapsedAllocation - elapsedGetLock -
 		elapsedGetConnObj - elapsedVerifyWM - elapsedWritePreRedeem - elapsedApplyChanges
@@ -1343,7 +1430,7 @@ func (fsh *StorageHandler) WriteFile(ctx context.Context, r *http.Request) (*all
 	if clientID == "" {
 		return nil, common.NewError("invalid_operation", "Operation needs to be performed by the owner or the payer of the allocation")
 	}
-	if ok := CheckBlacklist(clientID); ok {
+	if ok := CheckBlacklist(clientID, remoteIP(ctx, r)); ok {
 		return nil, common.NewError("blacklisted_client", "Client is blacklisted: "+clientID)
 	}
 	elapsedParseForm := time.Since(startTime)
@@ -1471,6 +1558,8 @@ func (fsh *StorageHandler) Rollback(ctx context.Context, r *http.Request) (*blob
 	elapsedAllocation := time.Since(startTime)
 
 	allocationID := allocationObj.ID
+	ae := auditlog.FromContext(ctx)
+	ae.AllocationID = allocationID
 	connectionID, ok := common.GetField(r, "connection_id")
 	if !ok {
 		return nil, common.NewError("invalid_parameters", "Invalid connection id passed")
@@ -1494,6 +1583,7 @@ func (fsh *StorageHandler) Rollback(ctx context.Context, r *http.Request) (*blob
 			"Invalid parameters. Error parsing the writemarker for commit: %v",
 			err)
 	}
+	ae.WriteMarkerRoot = writeMarker.AllocationRoot
 
 	var result blobberhttp.CommitResult
 
@@ -1632,6 +1722,7 @@ func (fsh *StorageHandler) Rollback(ctx context.Context, r *http.Request) (*blob
 	if err != nil {
 		Logger.Error("Error committing the rollback for allocation", zap.Error(err))
 	}
+	challenge.InvalidateObjectPaths(allocationID)
 
 	elapsedCommitRollback := time.Since(startTime) - elapsedAllocation - elapsedGetLock - elapsedVerifyWM - elapsedWritePreRedeem
 	result.AllocationRoot = allocationObj.AllocationRoot
@@ -0,0 +1,690 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/gosdk/constants"
+	"gorm.io/gorm"
+
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
+)
+
+// maxPreflightOperations is the maximum number of operations checked by a
+// preflight.
+const maxPreflightOperations = 1000
+
+// PreflightOperation is an operation a client intends to run on the
+// allocation.
+// swagger:model PreflightOperation
+type PreflightOperation struct {
+	// Operation is one of insert, update, delete, rename, copy, move and createdir.
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	// Size is the size of the data stored on this blobber, for an insert or an update.
+	Size int64 `json:"size,omitempty"`
+	// Dest is the directory the object is copied or moved to.
+	Dest string `json:"dest,omitempty"`
+	// NewName is the new name of a renamed object.
+	NewName string `json:"new_name,omitempty"`
+}
+
+// PreflightProblem is a reason the operation would be refused. Its code is the
+// code of the error the operation would get.
+type PreflightProblem struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PreflightOperationResult is the outcome of an operation of the preflight.
+type PreflightOperationResult struct {
+	Operation string              `json:"operation"`
+	Path      string              `json:"path"`
+	Problems  []*PreflightProblem `json:"problems,omitempty"`
+}
+
+// PreflightResult is the outcome of the operations, run one after the other
+// as they would be in a connection.
+// swagger:model PreflightResult
+type PreflightResult struct {
+	// OK is true when none of the operations has a problem.
+	OK         bool                        `json:"ok"`
+	Operations []*PreflightOperationResult `json:"operations"`
+	// UsedSize is the size used by the allocation on this blobber.
+	UsedSize int64 `json:"used_size"`
+	// ProjectedUsedSize is the size used once the operations without problems are committed.
+	ProjectedUsedSize int64 `json:"projected_used_size"`
+	// AllocationSize is the size of the allocation on this blobber.
+	AllocationSize int64 `json:"allocation_size"`
+	// WriteCost is the write pool balance the commit of the operations requires, from the terms of the allocation.
+	WriteCost uint64 `json:"write_cost"`
+}
+
+// preflightStore reads the objects of the allocation as they are committed.
+type preflightStore interface {
+	// getRef returns the path, type and size of the object at the path, nil
+	// when there is none.
+	getRef(p string) (*reference.Ref, error)
+	countChildren(dir string) (int64, error)
+}
+
+type dbPreflightStore struct {
+	ctx          context.Context
+	allocationID string
+}
+
+func (s *dbPreflightStore) getRef(p string) (*reference.Ref, error) {
+	ref, err := reference.GetLimitedRefFieldsByPath(s.ctx, s.allocationID, p, []string{"path", "type", "size"})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return ref, err
+}
+
+func (s *dbPreflightStore) countChildren(dir string) (int64, error) {
+	return reference.CountChildren(s.ctx, s.allocationID, dir)
+}
+
+// preflightObject is an object changed by an operation of the preflight.
+type preflightObject struct {
+	typ     string
+	size    int64 // of a file
+	copied  int64 // size of a directory copied or moved here
+	deleted bool
+	// delta is the change of the used size made by the operations on the
+	// object and the ones below it which are no longer tracked.
+	delta int64
+}
+
+// preflight runs the operations on the objects of the allocation, keeping the
+// changes in memory. The content of a directory created, copied, moved or
+// renamed by an earlier operation is only known through the later operations,
+// and the objects below a copied directory are not counted against
+// max_dirs_files.
+type preflight struct {
+	store    preflightStore
+	objects  map[string]*preflightObject
+	stored   map[string]*reference.Ref
+	children map[string]int64
+
+	refs, size, allocationSize int64
+
+	// quota is the standing of the client against its limits, none when the
+	// client is not checked.
+	quota *preflightQuota
+}
+
+// preflightQuota is the standing of the client running the operations.
+type preflightQuota struct {
+	clientID    string
+	blacklisted bool
+	// uploaded is the number of blocks uploaded by the client over the last
+	// Period, and uploadLimit the number it can upload.
+	uploaded, uploadLimit int64
+}
+
+// uploadBlocks returns the blocks counted against the upload limit of the
+// client for a file of the size.
+func uploadBlocks(size int64) int64 {
+	return (size + reference.CHUNK_SIZE - 1) / reference.CHUNK_SIZE
+}
+
+// checkUpload returns the problem of uploading a file of the size over the
+// monthly upload limit of the client.
+func (p *preflight) checkUpload(size int64) *PreflightProblem {
+	if p.quota == nil {
+		return nil
+	}
+	if blocks := uploadBlocks(size); p.quota.uploaded+blocks > p.quota.uploadLimit {
+		return &PreflightProblem{"upload_limit_reached", fmt.Sprintf("the upload of %d blocks exceeds the monthly upload limit of the client, %d of %d blocks used",
+			blocks, p.quota.uploaded, p.quota.uploadLimit)}
+	}
+	return nil
+}
+
+// uploaded counts the upload of a file of the size against the monthly
+// upload limit of the client.
+func (p *preflight) uploaded(size int64) {
+	if p.quota != nil {
+		p.quota.uploaded += uploadBlocks(size)
+	}
+}
+
+func newPreflight(store preflightStore, refs, size, allocationSize int64) *preflight {
+	return &preflight{
+		store:          store,
+		objects:        make(map[string]*preflightObject),
+		stored:         make(map[string]*reference.Ref),
+		children:       make(map[string]int64),
+		refs:           refs,
+		size:           size,
+		allocationSize: allocationSize,
+	}
+}
+
+// committed reports whether the object at the path is the committed one,
+// neither it nor a directory above it being changed by an operation.
+func (p *preflight) committed(path string) bool {
+	for cur := path; cur != "/"; cur = filepath.Dir(cur) {
+		if _, ok := p.objects[cur]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *preflight) storedRef(path string) (*reference.Ref, error) {
+	if ref, ok := p.stored[path]; ok {
+		return ref, nil
+	}
+	ref, err := p.store.getRef(path)
+	if err != nil {
+		return nil, err
+	}
+	p.stored[path] = ref
+	return ref, nil
+}
+
+// get returns the type and, for a file, the size of the object at the path,
+// nil when there is none.
+func (p *preflight) get(path string) (*preflightObject, error) {
+	if path == "/" {
+		return &preflightObject{typ: reference.DIRECTORY}, nil
+	}
+	if o, ok := p.objects[path]; ok {
+		if o.deleted {
+			return nil, nil
+		}
+		return o, nil
+	}
+	if !p.committed(path) {
+		return nil, nil
+	}
+	ref, err := p.storedRef(path)
+	if err != nil || ref == nil {
+		return nil, err
+	}
+	return &preflightObject{typ: ref.Type, size: ref.Size}, nil
+}
+
+// below returns the paths of the changed objects below the directory.
+func (p *preflight) below(dir string) []string {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var paths []string
+	for path := range p.objects {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// sizeOf returns the size of the object at the path, the content of a
+// directory included.
+func (p *preflight) sizeOf(path string, o *preflightObject) (int64, error) {
+	if o.typ == reference.FILE {
+		return o.size, nil
+	}
+	size := o.copied
+	if p.committed(path) {
+		ref, err := p.storedRef(path)
+		if err != nil {
+			return 0, err
+		}
+		if ref != nil {
+			size += ref.Size
+		}
+	}
+	for _, below := range p.below(path) {
+		size += p.objects[below].delta
+	}
+	return size, nil
+}
+
+func (p *preflight) childCount(dir string) (int64, error) {
+	if n, ok := p.children[dir]; ok {
+		return n, nil
+	}
+	var n int64
+	if p.committed(dir) {
+		var err error
+		if n, err = p.store.countChildren(dir); err != nil {
+			return 0, err
+		}
+	}
+	p.children[dir] = n
+	return n, nil
+}
+
+// put sets the object at the path, adding size to the used size.
+func (p *preflight) put(path string, o *preflightObject, size int64) error {
+	parent := filepath.Dir(path)
+	n, err := p.childCount(parent)
+	if err != nil {
+		return err
+	}
+	if prev, ok := p.objects[path]; ok {
+		o.delta = prev.delta
+	}
+	o.delta += size
+	p.objects[path] = o
+	p.children[parent] = n + 1
+	p.refs++
+	p.size += size
+	return nil
+}
+
+// remove deletes the object at the path, and what is below it.
+func (p *preflight) remove(path string, o *preflightObject) error {
+	freed, err := p.sizeOf(path, o)
+	if err != nil {
+		return err
+	}
+	parent := filepath.Dir(path)
+	n, err := p.childCount(parent)
+	if err != nil {
+		return err
+	}
+
+	var delta int64
+	if prev, ok := p.objects[path]; ok {
+		delta = prev.delta
+	}
+	for _, below := range p.below(path) {
+		delta += p.objects[below].delta
+		delete(p.objects, below)
+		delete(p.children, below)
+	}
+	delete(p.children, path)
+	p.objects[path] = &preflightObject{deleted: true, delta: delta - freed}
+	p.children[parent] = n - 1
+	p.refs--
+	p.size -= freed
+	return nil
+}
+
+// missingDirs returns the directories above the path to be created with it,
+// or a problem when one of them is a file.
+func (p *preflight) missingDirs(path string) ([]string, *PreflightProblem, error) {
+	parents, err := common.GetParentPaths(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var missing []string
+	for _, parent := range parents {
+		o, err := p.get(parent)
+		if err != nil {
+			return nil, nil, err
+		}
+		if o == nil {
+			missing = append(missing, parent)
+			continue
+		}
+		if o.typ == reference.FILE {
+			return nil, &PreflightProblem{"invalid_path", fmt.Sprintf("parent path %v is of file type", parent)}, nil
+		}
+	}
+	return missing, nil, nil
+}
+
+// checkCreate returns the problems of creating an object of the size at the
+// path, and the directories to be created with it. src is the path of the
+// object moved to the path, if any.
+func (p *preflight) checkCreate(path string, size int64, src string) ([]string, []*PreflightProblem, error) {
+	var problems []*PreflightProblem
+	missing, problem, err := p.missingDirs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problem != nil {
+		problems = append(problems, problem)
+	}
+
+	o, err := p.get(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o != nil {
+		problems = append(problems, &PreflightProblem{"duplicate_file", fmt.Sprintf("File at path :%s: already exists", path)})
+	}
+
+	refs := p.refs + int64(len(missing)) + 1
+	if src != "" {
+		refs--
+	}
+	if maxRefs := int64(config.Configuration.MaxAllocationDirFiles); refs > maxRefs {
+		problems = append(problems, &PreflightProblem{"max_alloc_dir_files_reached",
+			fmt.Sprintf("maximum files and directories already reached: %v", maxRefs)})
+	}
+
+	dir := filepath.Dir(path)
+	if len(missing) > 0 {
+		dir = filepath.Dir(missing[0])
+	}
+	n, err := p.childCount(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if src != "" && filepath.Dir(src) == dir {
+		n--
+	}
+	if n >= int64(config.Configuration.MaxObjectsInDir) {
+		problems = append(problems, &PreflightProblem{"max_objects_in_dir_reached",
+			fmt.Sprintf("maximum objects in directory %s reached: %v", dir, config.Configuration.MaxObjectsInDir)})
+	}
+
+	if p.size+size > p.allocationSize {
+		problems = append(problems, &PreflightProblem{"max_allocation_size", "Max size reached for the allocation with this blobber"})
+	}
+	return missing, problems, nil
+}
+
+func (p *preflight) create(missing []string, path string, o *preflightObject, size int64) error {
+	for _, dir := range missing {
+		if err := p.put(dir, &preflightObject{typ: reference.DIRECTORY}, 0); err != nil {
+			return err
+		}
+	}
+	return p.put(path, o, size)
+}
+
+// existing returns the object at the path, or a problem when there is none.
+func (p *preflight) existing(path string) (*preflightObject, []*PreflightProblem, error) {
+	o, err := p.get(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o == nil {
+		return nil, []*PreflightProblem{{"invalid_parameters", fmt.Sprintf("Invalid file path. %v does not exist", path)}}, nil
+	}
+	return o, nil, nil
+}
+
+// run checks the operation and, when it has no problem, applies it.
+func (p *preflight) run(op *PreflightOperation) ([]*PreflightProblem, error) {
+	if op.Path == "" || !filepath.IsAbs(op.Path) {
+		return []*PreflightProblem{{"invalid_path", fmt.Sprintf("%v is not absolute path", op.Path)}}, nil
+	}
+	path := filepath.Clean(op.Path)
+	if path == "/" && op.Operation != constants.FileOperationCreateDir {
+		return []*PreflightProblem{{"invalid_path", "Invalid path. Cannot operate on the root directory"}}, nil
+	}
+	if p.quota != nil && p.quota.blacklisted {
+		return []*PreflightProblem{{"blacklisted_client", "Client is blacklisted: " + p.quota.clientID}}, nil
+	}
+
+	switch op.Operation {
+	case constants.FileOperationInsert:
+		if op.Size < 0 {
+			return []*PreflightProblem{{"invalid_parameters", "size cannot be negative"}}, nil
+		}
+		missing, problems, err := p.checkCreate(path, op.Size, "")
+		if err != nil {
+			return nil, err
+		}
+		if op.Size > config.StorageSCConfig.MaxFileSize {
+			problems = append(problems, &PreflightProblem{"max_file_size",
+				fmt.Sprintf("file size %d should not be greater than %d", op.Size, config.StorageSCConfig.MaxFileSize)})
+		}
+		if problem := p.checkUpload(op.Size); problem != nil {
+			problems = append(problems, problem)
+		}
+		if len(problems) > 0 {
+			return problems, nil
+		}
+		p.uploaded(op.Size)
+		return nil, p.create(missing, path, &preflightObject{typ: reference.FILE, size: op.Size}, op.Size)
+
+	case constants.FileOperationUpdate:
+		if op.Size < 0 {
+			return []*PreflightProblem{{"invalid_parameters", "size cannot be negative"}}, nil
+		}
+		o, problems, err := p.existing(path)
+		if err != nil || len(problems) > 0 {
+			return problems, err
+		}
+		if o.typ != reference.FILE {
+			problems = append(problems, &PreflightProblem{"invalid_parameters", "Path is not a file."})
+		}
+		if op.Size > config.StorageSCConfig.MaxFileSize {
+			problems = append(problems, &PreflightProblem{"max_file_size",
+				fmt.Sprintf("file size %d should not be greater than %d", op.Size, config.StorageSCConfig.MaxFileSize)})
+		}
+		if p.size+op.Size-o.size > p.allocationSize {
+			problems = append(problems, &PreflightProblem{"max_allocation_size", "Max size reached for the allocation with this blobber"})
+		}
+		if problem := p.checkUpload(op.Size); problem != nil {
+			problems = append(problems, problem)
+		}
+		if len(problems) > 0 {
+			return problems, nil
+		}
+		p.uploaded(op.Size)
+		updated := &preflightObject{typ: reference.FILE, size: op.Size}
+		if prev, ok := p.objects[path]; ok {
+			updated.delta = prev.delta
+		}
+		updated.delta += op.Size - o.size
+		p.objects[path] = updated
+		p.size += op.Size - o.size
+		return nil, nil
+
+	case constants.FileOperationDelete:
+		o, problems, err := p.existing(path)
+		if err != nil || len(problems) > 0 {
+			return problems, err
+		}
+		return nil, p.remove(path, o)
+
+	case constants.FileOperationCreateDir:
+		o, err := p.get(path)
+		if err != nil {
+			return nil, err
+		}
+		if o != nil && o.typ == reference.DIRECTORY {
+			return []*PreflightProblem{{"directory_exists", "Directory already exists"}}, nil
+		}
+		missing, problems, err := p.checkCreate(path, 0, "")
+		if err != nil || len(problems) > 0 {
+			return problems, err
+		}
+		return nil, p.create(missing, path, &preflightObject{typ: reference.DIRECTORY}, 0)
+
+	case constants.FileOperationRename, constants.FileOperationCopy, constants.FileOperationMove:
+		var dest string
+		if op.Operation == constants.FileOperationRename {
+			if op.NewName == "" || strings.Contains(op.NewName, "/") {
+				return []*PreflightProblem{{"invalid_parameters", "Invalid name"}}, nil
+			}
+			dest = filepath.Join(filepath.Dir(path), op.NewName)
+		} else {
+			if op.Dest == "" || !filepath.IsAbs(op.Dest) {
+				return []*PreflightProblem{{"invalid_parameters", "Invalid destination for operation"}}, nil
+			}
+			dest = filepath.Join(op.Dest, filepath.Base(path))
+			if filepath.Clean(op.Dest) == filepath.Dir(path) {
+				return []*PreflightProblem{{"invalid_parameters", "Invalid destination path. Cannot copy to the same parent directory."}}, nil
+			}
+			if dest == path || strings.HasPrefix(dest, path+"/") {
+				return []*PreflightProblem{{"invalid_parameters", "Invalid destination path. Cannot copy or move an object into itself."}}, nil
+			}
+		}
+
+		o, problems, err := p.existing(path)
+		if err != nil || len(problems) > 0 {
+			return problems, err
+		}
+		size, err := p.sizeOf(path, o)
+		if err != nil {
+			return nil, err
+		}
+		added, src := size, ""
+		if op.Operation != constants.FileOperationCopy {
+			added, src = 0, path
+		}
+		missing, problems, err := p.checkCreate(dest, added, src)
+		if err != nil || len(problems) > 0 {
+			return problems, err
+		}
+
+		if src != "" {
+			if err := p.remove(src, o); err != nil {
+				return nil, err
+			}
+		}
+		moved := &preflightObject{typ: o.typ, size: o.size}
+		if o.typ == reference.DIRECTORY {
+			moved.copied = size
+		}
+		return nil, p.create(missing, dest, moved, size)
+
+	default:
+		return []*PreflightProblem{{"invalid_operation", fmt.Sprintf("unknown operation %q", op.Operation)}}, nil
+	}
+}
+
+// swagger:route POST /v1/file/preflight/{allocation} PostPreflight
+// Preflight operations.
+// Checks a list of operations the client intends to run on the allocation, without changing anything.
+// The operations are checked one after the other, as they would run in a connection, and for each of them
+// the problems it would run into are returned, such as an existing object, a parent path of file type,
+// a lack of space, a client over its monthly upload limit or blacklisted. The projected used size and the write cost are the ones of the operations without problems.
+// Can only be run by the owner or the repairer of the allocation.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: the allocation ID
+//	  required: true
+//	  in: path
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: operations
+//	  description: JSON array of the operations, each with its operation (insert, update, delete, rename, copy, move or createdir), path, size for an insert or an update, dest for a copy or a move and new_name for a rename.
+//	  in: body
+//	  type: string
+//	  required: true
+//
+// responses:
+//
+//	200: PreflightResult
+//	400:
+func PreflightHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	ctx = setupHandlerContext(ctx, r)
+	return storageHandler.Preflight(ctx, r)
+}
+
+func (fsh *StorageHandler) Preflight(ctx context.Context, r *http.Request) (*PreflightResult, error) {
+	allocationId := ctx.Value(constants.ContextKeyAllocationID).(string)
+	allocationTx := ctx.Value(constants.ContextKeyAllocation).(string)
+	allocationObj, err := fsh.verifyAllocation(ctx, allocationId, allocationTx, true)
+	if err != nil {
+		return nil, common.NewError("invalid_parameters", "Invalid allocation id passed."+err.Error())
+	}
+
+	clientSign, _ := ctx.Value(constants.ContextKeyClientSignatureHeaderKey).(string)
+	clientSignV2, _ := ctx.Value(constants.ContextKeyClientSignatureHeaderV2Key).(string)
+	valid, err := verifySignatureFromRequest(allocationTx, clientSign, clientSignV2, allocationObj.OwnerPublicKey)
+	if !valid || err != nil {
+		return nil, common.NewError("invalid_signature", "Invalid signature")
+	}
+
+	clientID := ctx.Value(constants.ContextKeyClient).(string)
+	if clientID == "" || (allocationObj.OwnerID != clientID && allocationObj.RepairerID != clientID) {
+		return nil, common.NewError("invalid_operation", "Operation needs to be performed by the owner or the payer of the allocation")
+	}
+
+	var ops []*PreflightOperation
+	if err := json.Unmarshal([]byte(r.FormValue("operations")), &ops); err != nil {
+		return nil, common.NewError("invalid_parameters", "Invalid operations. "+err.Error())
+	}
+	if len(ops) == 0 || len(ops) > maxPreflightOperations {
+		return nil, common.NewErrorf("invalid_parameters", "between 1 and %d operations can be checked", maxPreflightOperations)
+	}
+
+	totalRefs, err := reference.CountRefs(ctx, allocationObj.ID)
+	if err != nil {
+		Logger.Error("[preflight]count refs", zap.String("allocation_id", allocationObj.ID), zap.Error(err))
+		return nil, common.NewError("database_error", "Got db error while counting refs")
+	}
+
+	usage, err := getMonthlyUsage(ctx, clientID)
+	if err != nil {
+		Logger.Error("[preflight]client usage", zap.String("client_id", clientID), zap.Error(err))
+		return nil, common.NewError("database_error", "Got db error while reading the usage of the client")
+	}
+
+	store := &dbPreflightStore{ctx: ctx, allocationID: allocationObj.ID}
+	p := newPreflight(store, totalRefs, allocationObj.BlobberSizeUsed, allocationObj.BlobberSize)
+	p.quota = &preflightQuota{
+		clientID:    clientID,
+		blacklisted: CheckBlacklist(clientID, remoteIP(ctx, r)),
+		uploaded:    usage.Upload,
+		uploadLimit: getClientLimits(clientID).UploadLimitMonthly,
+	}
+	return runPreflight(p, allocationObj, ops)
+}
+
+func runPreflight(p *preflight, alloc *allocation.Allocation, ops []*PreflightOperation) (*PreflightResult, error) {
+	result := &PreflightResult{
+		OK:             true,
+		Operations:     make([]*PreflightOperationResult, 0, len(ops)),
+		UsedSize:       p.size,
+		AllocationSize: p.allocationSize,
+	}
+	for _, op := range ops {
+		problems, err := p.run(op)
+		if err != nil {
+			Logger.Error("[preflight]run operation", zap.String("allocation_id", alloc.ID),
+				zap.String("operation", op.Operation), zap.String("path", op.Path), zap.Error(err))
+			return nil, common.NewError("database_error", "Got db error while checking the operations")
+		}
+		if len(problems) > 0 {
+			result.OK = false
+		}
+		result.Operations = append(result.Operations, &PreflightOperationResult{
+			Operation: op.Operation,
+			Path:      op.Path,
+			Problems:  problems,
+		})
+	}
+
+	result.ProjectedUsedSize = p.size
+	if written := result.ProjectedUsedSize - result.UsedSize; written > 0 {
+		result.WriteCost = alloc.GetRequiredWriteBalance(node.Self.ID, written, common.Now())
+	}
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/gosdk/constants"
+	"github.com/stretchr/testify/require"
+)
+
+// memPreflightStore is a committed tree of objects, by path.
+type memPreflightStore map[string]*reference.Ref
+
+func (s memPreflightStore) getRef(p string) (*reference.Ref, error) {
+	return s[p], nil
+}
+
+func (s memPreflightStore) countChildren(dir string) (int64, error) {
+	var n int64
+	for p := range s {
+		if p != "/" && filepath.Dir(p) == dir {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func setupPreflight(t *testing.T) (*preflight, *allocation.Allocation) {
+	config.Configuration.MaxAllocationDirFiles = 10
+	config.Configuration.MaxObjectsInDir = 4
+	config.StorageSCConfig.MaxFileSize = 1 << 30
+	t.Cleanup(func() {
+		config.Configuration.MaxAllocationDirFiles = 0
+		config.Configuration.MaxObjectsInDir = 0
+		config.StorageSCConfig.MaxFileSize = 0
+	})
+	node.Self.ID = "blobber"
+
+	store := memPreflightStore{
+		"/":             {Path: "/", Type: reference.DIRECTORY, Size: 300},
+		"/docs":         {Path: "/docs", Type: reference.DIRECTORY, Size: 300},
+		"/docs/a.txt":   {Path: "/docs/a.txt", Type: reference.FILE, Size: 100},
+		"/docs/b.txt":   {Path: "/docs/b.txt", Type: reference.FILE, Size: 200},
+		"/docs/archive": {Path: "/docs/archive", Type: reference.DIRECTORY},
+	}
+	alloc := &allocation.Allocation{
+		ID:         "alloc",
+		Expiration: common.Timestamp(time.Now().Add(10 * time.Hour).Unix()),
+		TimeUnit:   time.Hour,
+		Terms:      []*allocation.Terms{{BlobberID: "blobber", WritePrice: 1e10}},
+	}
+	return newPreflight(store, int64(len(store)), 300, 1000), alloc
+}
+
+func problemCodes(res *PreflightResult) [][]string {
+	codes := make([][]string, 0, len(res.Operations))
+	for _, op := range res.Operations {
+		var list []string
+		for _, p := range op.Problems {
+			list = append(list, p.Code)
+		}
+		codes = append(codes, list)
+	}
+	return codes
+}
+
+func TestPreflight(t *testing.T) {
+	p, alloc := setupPreflight(t)
+
+	res, err := runPreflight(p, alloc, []*PreflightOperation{
+		{Operation: constants.FileOperationInsert, Path: "/docs/a.txt", Size: 10},
+		{Operation: constants.FileOperationInsert, Path: "/docs/a.txt/c.txt", Size: 10},
+		{Operation: constants.FileOperationInsert, Path: "/photos/p.png", Size: 500},
+		{Operation: constants.FileOperationInsert, Path: "/photos/q.png", Size: 500},
+		{Operation: constants.FileOperationUpdate, Path: "/docs/b.txt", Size: 50},
+		{Operation: constants.FileOperationDelete, Path: "/nothing"},
+		{Operation: constants.FileOperationCreateDir, Path: "/docs"},
+		{Operation: "chmod", Path: "/docs"},
+	})
+	require.NoError(t, err)
+	require.False(t, res.OK)
+	require.Equal(t, [][]string{
+		{"duplicate_file"},
+		{"invalid_path"},
+		nil,
+		{"max_allocation_size"},
+		nil,
+		{"invalid_parameters"},
+		{"directory_exists"},
+		{"invalid_operation"},
+	}, problemCodes(res))
+
+	require.EqualValues(t, 300, res.UsedSize)
+	require.EqualValues(t, 300+500-150, res.ProjectedUsedSize)
+	require.EqualValues(t, 1000, res.AllocationSize)
+	require.NotZero(t, res.WriteCost)
+}
+
+func TestPreflight_FollowsEarlierOperations(t *testing.T) {
+	p, alloc := setupPreflight(t)
+
+	res, err := runPreflight(p, alloc, []*PreflightOperation{
+		// the file is moved out of the way before the upload
+		{Operation: constants.FileOperationRename, Path: "/docs/a.txt", NewName: "old.txt"},
+		{Operation: constants.FileOperationInsert, Path: "/docs/a.txt", Size: 400},
+		// the directory is full
+		{Operation: constants.FileOperationInsert, Path: "/docs/c.txt", Size: 1},
+		{Operation: constants.FileOperationDelete, Path: "/docs"},
+		{Operation: constants.FileOperationCopy, Path: "/docs/b.txt", Dest: "/backup"},
+		{Operation: constants.FileOperationCreateDir, Path: "/docs/new"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{nil, nil, {"max_objects_in_dir_reached"}, nil, {"invalid_parameters"}, nil}, problemCodes(res))
+	// the directory is deleted with what was uploaded to it
+	require.EqualValues(t, 0, res.ProjectedUsedSize)
+	require.Zero(t, res.WriteCost)
+}
+
+func TestPreflight_MoveDirectory(t *testing.T) {
+	p, alloc := setupPreflight(t)
+
+	res, err := runPreflight(p, alloc, []*PreflightOperation{
+		{Operation: constants.FileOperationMove, Path: "/docs", Dest: "/docs/archive"},
+		{Operation: constants.FileOperationMove, Path: "/docs", Dest: "/old"},
+		{Operation: constants.FileOperationInsert, Path: "/docs/a.txt", Size: 1},
+		{Operation: constants.FileOperationCopy, Path: "/old/docs", Dest: "/backup"},
+		{Operation: constants.FileOperationDelete, Path: "/old"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"invalid_parameters"}, nil, nil, nil, nil}, problemCodes(res))
+	require.EqualValues(t, 301, res.ProjectedUsedSize)
+}
+
+func TestPreflight_ClientQuota(t *testing.T) {
+	p, alloc := setupPreflight(t)
+	p.quota = &preflightQuota{clientID: "client", uploaded: 8, uploadLimit: 10}
+
+	res, err := runPreflight(p, alloc, []*PreflightOperation{
+		{Operation: constants.FileOperationInsert, Path: "/docs/c.txt", Size: 1},
+		{Operation: constants.FileOperationUpdate, Path: "/docs/b.txt", Size: 2},
+		{Operation: constants.FileOperationInsert, Path: "/d.txt", Size: 1},
+		{Operation: constants.FileOperationDelete, Path: "/docs/a.txt"},
+	})
+	require.NoError(t, err)
+	// the blocks of the earlier uploads count against the limit of the later ones
+	require.Equal(t, [][]string{nil, nil, {"upload_limit_reached"}, nil}, problemCodes(res))
+	require.EqualValues(t, 10, p.quota.uploaded)
+
+	p, alloc = setupPreflight(t)
+	p.quota = &preflightQuota{clientID: "client", blacklisted: true, uploadLimit: 10}
+	res, err = runPreflight(p, alloc, []*PreflightOperation{
+		{Operation: constants.FileOperationDelete, Path: "/docs/a.txt"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"blacklisted_client"}}, problemCodes(res))
+}
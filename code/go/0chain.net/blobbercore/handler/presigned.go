@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"go.uber.org/zap"
+)
+
+// PresignedURLResult is the pre-signed url issued by the blobber.
+// swagger:model PresignedURLResult
+type PresignedURLResult struct {
+	URL        string           `json:"url"`
+	URLID      string           `json:"url_id"`
+	Expiration common.Timestamp `json:"expiration"`
+}
+
+// swagger:route GET /v1/file/presigned/{allocation} GetPresignedDownload
+// Download a file using a pre-signed url.
+//
+// Returns the data of the block range of the file granted by the url as a byte stream.
+// No client headers are required, so the url can be used with plain http clients.
+// The blocks served are taken from the blocks the allocation owner paid in advance, by sending
+// read markers to the redeem endpoint with the X-Presigned-Reads header, and count against the
+// daily pre-signed url budget of the owner. Both are reserved before the blocks are read.
+//
+// The url is signed either by the owner with the wallet keys ("bls" scheme), over the hash of
+// "allocation_id:path_hash:start_block:end_block:expires", or by this blobber ("hmac" scheme)
+// when issued with the presign endpoint.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: ID of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: path_hash
+//	  description: Lookup hash of the file.
+//	  in: query
+//	  required: true
+//	  type: string
+//	+name: start_block
+//	  description: First block of the range, starting at 0.
+//	  in: query
+//	  required: true
+//	  type: integer
+//	+name: end_block
+//	  description: Last block of the range, inclusive.
+//	  in: query
+//	  required: true
+//	  type: integer
+//	+name: expires
+//	  description: Unix timestamp after which the url is no longer valid.
+//	  in: query
+//	  required: true
+//	  type: integer
+//	+name: scheme
+//	  description: Signature scheme, either "bls" or "hmac". Default is "bls".
+//	  in: query
+//	  required: false
+//	  type: string
+//	+name: sig
+//	  description: Signature of the url.
+//	  in: query
+//	  required: true
+//	  type: string
+//
+// responses:
+//
+//	200:
+//	400:
+func PresignedDownloadHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	if !config.Configuration.PresignedURLEnabled {
+		return nil, common.NewError("presigned_url_disabled", "pre-signed urls are disabled on this blobber")
+	}
+
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	pu, err := readmarker.PresignedURLFromQuery(allocationID, r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	alloc, err := allocation.Repo.GetById(ctx, allocationID)
+	if err != nil {
+		return nil, common.NewErrorf("download_file", "invalid allocation id passed: %v", err)
+	}
+	if alloc.Expiration < common.Now() {
+		return nil, common.NewError("download_file", "use of expired allocation")
+	}
+
+	if err := pu.Verify(alloc); err != nil {
+		return nil, err
+	}
+
+	fileref, err := reference.GetReferenceByLookupHash(ctx, alloc.ID, pu.PathHash)
+	if err != nil {
+		return nil, common.NewErrorf("download_file", "invalid file path: %v", err)
+	}
+	if fileref.Type != reference.FILE {
+		return nil, common.NewError("download_file", "path is not a file")
+	}
+	if len(fileref.EncryptedKey) > 0 {
+		return nil, common.NewError("download_file", "encrypted files cannot be downloaded with pre-signed urls")
+	}
+
+	numBlocks := pu.NumBlocks()
+	paid := !alloc.IsReadFree(node.Self.ID)
+	if err := readmarker.ReservePresignedRead(ctx, alloc.ID, alloc.OwnerID, numBlocks, paid); err != nil {
+		return nil, err
+	}
+
+	rbi := &filestore.ReadBlockInput{
+		AllocationID:     alloc.ID,
+		FileSize:         fileref.Size,
+		Hash:             fileref.ValidationRoot,
+		StartBlockNum:    int(pu.StartBlock),
+		NumBlocks:        int(numBlocks),
+		IsPrecommit:      fileref.IsPrecommit && fileref.ValidationRoot != fileref.PrevValidationRoot,
+		FilestoreVersion: fileref.FilestoreVersion,
+	}
+	fileDownloadResponse, err := filestore.WithContext(ctx).GetFileBlock(rbi)
+	if err != nil {
+		if err := readmarker.ReleasePresignedRead(ctx, alloc.ID, alloc.OwnerID, numBlocks, paid); err != nil {
+			Logger.Error("presigned_download:release_read", zap.Error(err), zap.String("allocation_id", alloc.ID))
+		}
+		return nil, common.NewErrorf("download_file", "couldn't get file block: %v", err)
+	}
+
+	reference.FileBlockDownloaded(ctx, fileref, numBlocks)
+	return fileDownloadResponse.Data, nil
+}
+
+// swagger:route POST /v1/file/presign/{allocation} PresignDownloadURL
+// Issue a pre-signed download url.
+//
+// Issues a url signed by this blobber granting the download of a block range of a file
+// until it expires. Only the owner of the allocation can issue urls.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: TxHash of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: path
+//	  description: Path of the file. Required if path_hash is not provided.
+//	  in: formData
+//	  type: string
+//	+name: path_hash
+//	  description: Lookup hash of the file.
+//	  in: formData
+//	  type: string
+//	+name: start_block
+//	  description: First block of the range, starting at 0.
+//	  in: formData
+//	  type: integer
+//	  required: true
+//	+name: end_block
+//	  description: Last block of the range, inclusive.
+//	  in: formData
+//	  type: integer
+//	  required: true
+//	+name: expires_in
+//	  description: Lifetime of the url in seconds. Default is one hour.
+//	  in: formData
+//	  type: integer
+//
+// responses:
+//
+//	200: PresignedURLResult
+//	400:
+func PresignDownloadURL(ctx context.Context, r *http.Request) (interface{}, error) {
+	if !config.Configuration.PresignedURLEnabled {
+		return nil, common.NewError("presigned_url_disabled", "pre-signed urls are disabled on this blobber")
+	}
+
+	ctx = setupHandlerContext(ctx, r)
+
+	allocationObj, err := verifyOwnerRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	pathHash, err := pathHashFromReq(r, allocationObj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pu := &readmarker.PresignedURL{AllocationID: allocationObj.ID, PathHash: pathHash}
+	if pu.StartBlock, err = strconv.ParseInt(r.FormValue("start_block"), 10, 64); err != nil {
+		return nil, common.NewError("invalid_parameters", "invalid start_block")
+	}
+	if pu.EndBlock, err = strconv.ParseInt(r.FormValue("end_block"), 10, 64); err != nil {
+		return nil, common.NewError("invalid_parameters", "invalid end_block")
+	}
+
+	expiresIn := int64(time.Hour / time.Second)
+	if v := r.FormValue("expires_in"); v != "" {
+		if expiresIn, err = strconv.ParseInt(v, 10, 64); err != nil || expiresIn <= 0 {
+			return nil, common.NewError("invalid_parameters", "invalid expires_in")
+		}
+	}
+	pu.Expiration = common.Now() + common.Timestamp(expiresIn)
+	if err := pu.Validate(common.Now()); err != nil {
+		return nil, err
+	}
+
+	fileref, err := reference.GetLimitedRefFieldsByLookupHash(ctx, allocationObj.ID, pathHash, []string{"id", "type"})
+	if err != nil {
+		return nil, common.NewErrorf("invalid_parameters", "invalid file path: %v", err)
+	}
+	if fileref.Type != reference.FILE {
+		return nil, common.NewError("invalid_parameters", "path is not a file")
+	}
+
+	pu.SignHMAC()
+	return &PresignedURLResult{
+		URL:        node.Self.GetURLBase() + "/v1/file/presigned/" + allocationObj.ID + "?" + pu.Query().Encode(),
+		URLID:      pu.ID(),
+		Expiration: pu.Expiration,
+	}, nil
+}
+
+// swagger:route POST /v1/file/presigned/revoke/{allocation} RevokePresignedURLs
+// Revoke pre-signed download urls.
+//
+// Adds the given urls to the revocation list of the allocation. The url id is the hash
+// of the signed data of the url. Only the owner of the allocation can revoke urls.
+//
+// parameters:
+//
+//	+name: allocation
+//	  description: TxHash of the allocation in question.
+//	  in: path
+//	  required: true
+//	  type: string
+//	+name: X-App-Client-ID
+//	  description: The ID/Wallet address of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Key
+//	  description: The key of the client sending the request.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: ALLOCATION-ID
+//	  description: The ID of the allocation in question.
+//	  in: header
+//	  type: string
+//	  required: true
+//	+name: X-App-Client-Signature
+//	  description: Digital signature of the client used to verify the request if the X-Version is not "v2"
+//	  in: header
+//	  type: string
+//	+name: X-App-Client-Signature-V2
+//	  description: Digital signature of the client used to verify the request if the X-Version is "v2"
+//	  in: header
+//	  type: string
+//	+name: url_ids
+//	  description: JSON array of the ids of the urls to revoke.
+//	  in: formData
+//	  type: string
+//	  required: true
+//
+// responses:
+//
+//	200:
+//	400:
+func RevokePresignedURLs(ctx context.Context, r *http.Request) (interface{}, error) {
+	ctx = setupHandlerContext(ctx, r)
+
+	allocationObj, err := verifyOwnerRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	idsString, _ := common.GetField(r, "url_ids")
+	var urlIDs []string
+	if err := json.Unmarshal([]byte(idsString), &urlIDs); err != nil || len(urlIDs) == 0 {
+		return nil, common.NewError("invalid_parameters", "url_ids must be a non empty JSON array")
+	}
+	for _, id := range urlIDs {
+		if len(id) != 64 {
+			return nil, common.NewErrorf("invalid_parameters", "invalid url id: %v", id)
+		}
+	}
+
+	if err := readmarker.RevokePresignedURLs(ctx, allocationObj.ID, urlIDs); err != nil {
+		Logger.Error("failed_to_revoke_presigned_urls", zap.Error(err))
+		return nil, common.NewError("failed_to_revoke_presigned_urls", "failed to revoke pre-signed urls")
+	}
+
+	return map[string]interface{}{
+		"status":  http.StatusOK,
+		"revoked": len(urlIDs),
+	}, nil
+}
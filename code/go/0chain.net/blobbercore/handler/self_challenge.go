@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
+)
+
+// swagger:route GET /_self_challenge GetSelfChallengeResults
+// Get self challenge results.
+//
+// Lists the readiness of each allocation to be challenged, as found by the latest self challenge on it.
+// The allocations that are not ready are listed first.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//
+// responses:
+//
+//	200: []SelfChallengeResult
+func GetSelfChallengeResults(ctx context.Context, r *http.Request) (interface{}, error) {
+	return challenge.GetSelfChallengeResults(), nil
+}
+
+// swagger:route POST /_self_challenge/{allocation} RunSelfChallenge
+// Run a self challenge.
+//
+// Challenges a random block of the allocation and verifies the proof the way a validator does.
+// Nothing is submitted on chain.
+//
+// parameters:
+//
+//	+name: Authorization
+//	  in: header
+//	  type: string
+//	  required: true
+//	  description: Authorization header, "Bearer <admin token>" of a token with the debug scope. MUST be provided to fulfil the request
+//	+name: allocation
+//	  in: path
+//	  type: string
+//	  required: true
+//	  description: allocation id
+//
+// responses:
+//
+//	200: SelfChallengeResult
+func RunSelfChallenge(ctx context.Context, r *http.Request) (interface{}, error) {
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return challenge.RunSelfChallenge(ctx, allocationID)
+}
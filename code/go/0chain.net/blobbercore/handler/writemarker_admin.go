@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/writemarker"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/gorilla/mux"
+)
+
+func getAllocationVar(r *http.Request) (string, error) {
+	allocationID := mux.Vars(r)["allocation"]
+	if allocationID == "" {
+		return "", common.NewError("invalid_parameters", "allocation is required")
+	}
+	return allocationID, nil
+}
+
+func getSeqParam(r *http.Request, name string) (int64, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, common.NewErrorf("invalid_parameters", "%s parameter is not valid", name)
+	}
+	return seq, nil
+}
+
+// swagger:route GET /_writemarkers/{allocation} GetWriteMarkerRedeemState
+// Get write marker redemption state.
+//
+// Lists the pending and failed write markers of the allocation with their status messages
+// and close transaction IDs, along with the redemption state of the allocation.
+//
+// parameters:
+//
+//   +name: Authorization
+//     in: header
+//     type: string
+//     required: true
+//     description: Authorization header, "Bearer <admin token>" of a token with the read-stats scope. MUST be provided to fulfil the request
+//   +name: allocation
+//     in: path
+//     type: string
+//     required: true
+//     description: allocation id
+//   +name: filter
+//     in: query
+//     type: string
+//     required: false
+//     description: Either "pending" or "failed". Both are listed by default.
+//   +name: offset
+//     in: query
+//     type: integer
+//     required: false
+//     description: Pagination offset, start of the page to retrieve. Default is 0.
+//   +name: limit
+//     in: query
+//     type: integer
+//     required: false
+//     description: Pagination limit, number of entries in the page to retrieve. Default is 20.
+//   +name: sort
+//     in: query
+//     type: string
+//     required: false
+//     description: Direction of sorting based on the marker sequence, either "asc" or "desc". Default is "asc"
+//
+// responses:
+//   200: RedeemState
+func GetWriteMarkerRedeemState(ctx context.Context, r *http.Request) (interface{}, error) {
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := common.GetOffsetLimitOrderParam(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	return writemarker.GetRedeemState(ctx, allocationID, r.URL.Query().Get("filter"), limit)
+}
+
+// swagger:route POST /_writemarkers/{allocation}/redeem ForceRedeemWriteMarkers
+// Force redeem write markers.
+//
+// Redeems the write markers of the allocation in the given sequence range right away.
+//
+// parameters:
+//
+//   +name: Authorization
+//     in: header
+//     type: string
+//     required: true
+//     description: Authorization header, "Bearer <admin token>" of a token with the redemption-control scope. MUST be provided to fulfil the request
+//   +name: allocation
+//     in: path
+//     type: string
+//     required: true
+//     description: allocation id
+//   +name: start_seq
+//     in: query
+//     type: integer
+//     required: false
+//     description: First sequence to redeem. Defaults to the one after the last redeemed sequence.
+//   +name: end_seq
+//     in: query
+//     type: integer
+//     required: false
+//     description: Sequence of the write marker to redeem. Defaults to the latest write marker.
+//
+// responses:
+//   200: MarkerInfo
+func ForceRedeemWriteMarkers(ctx context.Context, r *http.Request) (interface{}, error) {
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	startSeq, err := getSeqParam(r, "start_seq")
+	if err != nil {
+		return nil, err
+	}
+	endSeq, err := getSeqParam(r, "end_seq")
+	if err != nil {
+		return nil, err
+	}
+
+	return writemarker.ForceRedeem(allocationID, startSeq, endSeq)
+}
+
+// swagger:route POST /_writemarkers/{allocation}/pause PauseWriteMarkerRedeem
+// Pause write marker redemption of the allocation.
+//
+// parameters:
+//
+//   +name: Authorization
+//     in: header
+//     type: string
+//     required: true
+//     description: Authorization header, "Bearer <admin token>" of a token with the redemption-control scope. MUST be provided to fulfil the request
+//   +name: allocation
+//     in: path
+//     type: string
+//     required: true
+//     description: allocation id
+//
+// responses:
+//   200:
+func PauseWriteMarkerRedeem(ctx context.Context, r *http.Request) (interface{}, error) {
+	return setWriteMarkerRedeemPaused(r, true)
+}
+
+// swagger:route POST /_writemarkers/{allocation}/resume ResumeWriteMarkerRedeem
+// Resume write marker redemption of the allocation.
+//
+// parameters:
+//
+//   +name: Authorization
+//     in: header
+//     type: string
+//     required: true
+//     description: Authorization header, "Bearer <admin token>" of a token with the redemption-control scope. MUST be provided to fulfil the request
+//   +name: allocation
+//     in: path
+//     type: string
+//     required: true
+//     description: allocation id
+//
+// responses:
+//   200:
+func ResumeWriteMarkerRedeem(ctx context.Context, r *http.Request) (interface{}, error) {
+	return setWriteMarkerRedeemPaused(r, false)
+}
+
+func setWriteMarkerRedeemPaused(r *http.Request, paused bool) (interface{}, error) {
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writemarker.SetRedeemPaused(allocationID, paused); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"allocation_id": allocationID,
+		"redeem_paused": paused,
+	}, nil
+}
+
+// swagger:route POST /_writemarkers/{allocation}/resync ResyncWriteMarkerRedeem
+// Re-sync the last redeemed write marker from chain.
+//
+// Sets the last redeemed sequence of the allocation to the write marker matching
+// the allocation root stored on chain for this blobber.
+//
+// parameters:
+//
+//   +name: Authorization
+//     in: header
+//     type: string
+//     required: true
+//     description: Authorization header, "Bearer <admin token>" of a token with the redemption-control scope. MUST be provided to fulfil the request
+//   +name: allocation
+//     in: path
+//     type: string
+//     required: true
+//     description: allocation id
+//
+// responses:
+//   200: ResyncResult
+func ResyncWriteMarkerRedeem(ctx context.Context, r *http.Request) (interface{}, error) {
+	allocationID, err := getAllocationVar(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return writemarker.ResyncRedeemedSeq(ctx, allocationID)
+}
@@ -0,0 +1,13 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// rejected counts the requests rejected, and the uploads and downloads slowed
+// down, by the limits of their allocation.
+var rejected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "blobber_allocation_rate_limited_total",
+	Help: "Requests rejected and transfers slowed down by the limits of their allocation, by kind.",
+}, []string{"kind"})
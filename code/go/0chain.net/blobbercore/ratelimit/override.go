@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"gorm.io/gorm/clause"
+)
+
+// Override replaces the limits of the config for an allocation. A limit not
+// set is the limit of the config, a limit set to zero is no limit.
+// swagger:model AllocationRateLimit
+type Override struct {
+	AllocationID string   `gorm:"column:allocation_id;size:64;primaryKey" json:"allocation_id"`
+	RPS          *float64 `gorm:"column:rps" json:"rps,omitempty"`
+	UploadBPS    *int64   `gorm:"column:upload_bps" json:"upload_bps,omitempty"`
+	DownloadBPS  *int64   `gorm:"column:download_bps" json:"download_bps,omitempty"`
+	Reason       string   `gorm:"column:reason" json:"reason"`
+
+	CreatedAt common.Timestamp `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+func (Override) TableName() string {
+	return "allocation_rate_limits"
+}
+
+// the overrides of the operator, kept in memory
+var (
+	overridesMu sync.RWMutex
+	overrides   = make(map[string]*Override)
+)
+
+// LoadOverrides reads the overrides of the operator from the database.
+func LoadOverrides(ctx context.Context) error {
+	var list []*Override
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		return datastore.GetStore().GetTransaction(ctx).Find(&list).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return common.NewError("allocation_rate_limits", err.Error())
+	}
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	clear(overrides)
+	for _, o := range list {
+		overrides[o.AllocationID] = o
+	}
+	return nil
+}
+
+// SetOverride saves the override of its allocation, replacing the previous one.
+func SetOverride(ctx context.Context, o *Override) error {
+	if o.AllocationID == "" {
+		return common.NewError("invalid_parameters", "missing allocation id")
+	}
+	if (o.RPS != nil && *o.RPS < 0) || (o.UploadBPS != nil && *o.UploadBPS < 0) ||
+		(o.DownloadBPS != nil && *o.DownloadBPS < 0) {
+		return common.NewError("invalid_parameters", "limits cannot be negative")
+	}
+	o.CreatedAt = common.Now()
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		return datastore.GetStore().GetTransaction(ctx).
+			Clauses(clause.OnConflict{UpdateAll: true}).Create(o).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return common.NewError("allocation_rate_limits", err.Error())
+	}
+	return LoadOverrides(ctx)
+}
+
+// DeleteOverride drops the override of the allocation, which gets back the
+// limits of the config.
+func DeleteOverride(ctx context.Context, allocationID string) error {
+	var deleted int64
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		res := datastore.GetStore().GetTransaction(ctx).
+			Delete(&Override{}, "allocation_id = ?", allocationID)
+		deleted = res.RowsAffected
+		return res.Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return common.NewError("allocation_rate_limits", err.Error())
+	}
+	if deleted == 0 {
+		return common.NewError("not_found", "no rate limit override for this allocation")
+	}
+	return LoadOverrides(ctx)
+}
+
+// GetOverrides returns the overrides of the operator.
+func GetOverrides() []*Override {
+	overridesMu.RLock()
+	list := make([]*Override, 0, len(overrides))
+	for _, o := range overrides {
+		list = append(list, o)
+	}
+	overridesMu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].AllocationID < list[j].AllocationID
+	})
+	return list
+}
+
+// GetLimits returns the limits of the allocation, the ones of the config
+// unless the operator overrode them.
+func GetLimits(allocationID string) Limits {
+	limits := defaultLimits()
+
+	overridesMu.RLock()
+	o := overrides[allocationID]
+	overridesMu.RUnlock()
+	if o == nil {
+		return limits
+	}
+	if o.RPS != nil {
+		limits.RPS = *o.RPS
+	}
+	if o.UploadBPS != nil {
+		limits.UploadBPS = *o.UploadBPS
+	}
+	if o.DownloadBPS != nil {
+		limits.DownloadBPS = *o.DownloadBPS
+	}
+	return limits
+}
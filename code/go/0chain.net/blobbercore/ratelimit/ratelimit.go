@@ -0,0 +1,217 @@
+// Package ratelimit limits the requests per second and shapes the upload and
+// download bandwidth of each allocation, so a noisy allocation cannot starve
+// the others of the blobber. The limits come from the config, unless the
+// operator overrode them for the allocation.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// idleTTL is the time the limiters of an allocation without requests are
+// kept.
+const idleTTL = 10 * time.Minute
+
+// Limits are the limits of an allocation. A zero limit is no limit.
+// swagger:model AllocationLimits
+type Limits struct {
+	// RPS are the requests per second of each client of the allocation, so a
+	// client cannot use up the requests of the others.
+	RPS         float64 `json:"rps"`
+	UploadBPS   int64   `json:"upload_bps"`
+	DownloadBPS int64   `json:"download_bps"`
+}
+
+// Counters count the requests of an allocation rejected and its transfers
+// slowed down. They are dropped along the limiters of the allocation once
+// idle.
+// swagger:model AllocationRateLimitCounters
+type Counters struct {
+	AllocationID       string `json:"allocation_id"`
+	RejectedRequests   int64  `json:"rejected_requests"`
+	ThrottledUploads   int64  `json:"throttled_uploads"`
+	ThrottledDownloads int64  `json:"throttled_downloads"`
+}
+
+type allocationLimiter struct {
+	limits Limits
+	// clients are the request limiters of the clients of the allocation.
+	clients  map[string]*clientLimiter
+	upload   *rate.Limiter
+	download *rate.Limiter
+	lastUsed time.Time
+}
+
+type clientLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+var (
+	mu       sync.Mutex
+	limiters = make(map[string]*allocationLimiter)
+	counters = make(map[string]*Counters)
+)
+
+// defaultLimits are the limits of the config.
+func defaultLimits() Limits {
+	return Limits{
+		RPS:         config.Configuration.AllocationRPS,
+		UploadBPS:   config.Configuration.AllocationUploadBPS,
+		DownloadBPS: config.Configuration.AllocationDownloadBPS,
+	}
+}
+
+func newRequestLimiter(rps float64) *rate.Limiter {
+	if rps <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(rps), int(math.Max(1, math.Ceil(rps))))
+}
+
+// newBandwidthLimiter allows bursts of a second of transfer.
+func newBandwidthLimiter(bps int64) *rate.Limiter {
+	if bps <= 0 {
+		return nil
+	}
+	burst := bps
+	if burst > math.MaxInt32 {
+		burst = math.MaxInt32
+	}
+	return rate.NewLimiter(rate.Limit(bps), int(burst))
+}
+
+// getLimiter returns the limiters of the allocation, rebuilt when its limits
+// changed. mu must be held.
+func getLimiter(allocationID string) *allocationLimiter {
+	limits := GetLimits(allocationID)
+	l := limiters[allocationID]
+	if l == nil || l.limits != limits {
+		l = &allocationLimiter{
+			limits:   limits,
+			clients:  make(map[string]*clientLimiter),
+			upload:   newBandwidthLimiter(limits.UploadBPS),
+			download: newBandwidthLimiter(limits.DownloadBPS),
+		}
+		limiters[allocationID] = l
+	}
+	l.lastUsed = time.Now()
+	return l
+}
+
+// getCounters returns the counters of the allocation. mu must be held.
+func getCounters(allocationID string) *Counters {
+	c := counters[allocationID]
+	if c == nil {
+		c = &Counters{AllocationID: allocationID}
+		counters[allocationID] = c
+	}
+	return c
+}
+
+// Limited reports whether an allocation may have limits, either from the config
+// or from an override, so the requests need not be resolved to their
+// allocation otherwise.
+func Limited() bool {
+	if defaultLimits() != (Limits{}) {
+		return true
+	}
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	return len(overrides) > 0
+}
+
+// Allow takes a request of the client out of its request limit on the
+// allocation, and reports whether the request is within the limit.
+func Allow(allocationID, clientID string) bool {
+	if allocationID == "" {
+		return true
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	l := getLimiter(allocationID)
+	if l.limits.RPS <= 0 {
+		return true
+	}
+	c := l.clients[clientID]
+	if c == nil {
+		c = &clientLimiter{Limiter: newRequestLimiter(l.limits.RPS)}
+		l.clients[clientID] = c
+	}
+	c.lastUsed = l.lastUsed
+	if c.Allow() {
+		return true
+	}
+	getCounters(allocationID).RejectedRequests++
+	rejected.WithLabelValues("requests").Inc()
+	return false
+}
+
+// GetCounters returns the counters of the allocations with requests rejected
+// or transfers slowed down.
+func GetCounters() []*Counters {
+	mu.Lock()
+	list := make([]*Counters, 0, len(counters))
+	for _, c := range counters {
+		cp := *c
+		list = append(list, &cp)
+	}
+	mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].AllocationID < list[j].AllocationID
+	})
+	return list
+}
+
+// removeIdle drops the limiters and the counters of the allocations without
+// requests for a while.
+func removeIdle(now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+	for id, l := range limiters {
+		if now.Sub(l.lastUsed) > idleTTL {
+			delete(limiters, id)
+			continue
+		}
+		for clientID, c := range l.clients {
+			if now.Sub(c.lastUsed) > idleTTL {
+				delete(l.clients, clientID)
+			}
+		}
+	}
+	// the counters of transfers outliving the limiters of their allocation
+	for id := range counters {
+		if _, ok := limiters[id]; !ok {
+			delete(counters, id)
+		}
+	}
+}
+
+// SetupWorkers loads the overrides of the operator and starts dropping the
+// idle limiters.
+func SetupWorkers(ctx context.Context) {
+	if err := LoadOverrides(ctx); err != nil {
+		logging.Logger.Error("[ratelimit]load overrides", zap.Error(err))
+	}
+	go func() {
+		ticker := time.NewTicker(idleTTL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				removeIdle(now)
+			}
+		}
+	}()
+}
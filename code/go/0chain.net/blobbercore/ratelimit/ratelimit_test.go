@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/stretchr/testify/require"
+)
+
+func setLimits(t *testing.T, defaults Limits, list ...*Override) {
+	config.Configuration.AllocationRPS = defaults.RPS
+	config.Configuration.AllocationUploadBPS = defaults.UploadBPS
+	config.Configuration.AllocationDownloadBPS = defaults.DownloadBPS
+	overridesMu.Lock()
+	for _, o := range list {
+		overrides[o.AllocationID] = o
+	}
+	overridesMu.Unlock()
+
+	t.Cleanup(func() {
+		config.Configuration.AllocationRPS = 0
+		config.Configuration.AllocationUploadBPS = 0
+		config.Configuration.AllocationDownloadBPS = 0
+		overridesMu.Lock()
+		clear(overrides)
+		overridesMu.Unlock()
+		mu.Lock()
+		clear(limiters)
+		clear(counters)
+		mu.Unlock()
+	})
+}
+
+func TestGetLimits(t *testing.T) {
+	rps, unlimited := 50.0, int64(0)
+	setLimits(t, Limits{RPS: 2, UploadBPS: 1000, DownloadBPS: 2000},
+		&Override{AllocationID: "partner", RPS: &rps, DownloadBPS: &unlimited})
+
+	require.Equal(t, Limits{RPS: 2, UploadBPS: 1000, DownloadBPS: 2000}, GetLimits("other"))
+	require.Equal(t, Limits{RPS: 50, UploadBPS: 1000}, GetLimits("partner"))
+}
+
+func TestAllow(t *testing.T) {
+	rps := 0.0
+	setLimits(t, Limits{RPS: 2}, &Override{AllocationID: "partner", RPS: &rps})
+
+	require.True(t, Allow("noisy", "client"))
+	require.True(t, Allow("noisy", "client"))
+	require.False(t, Allow("noisy", "client"))
+
+	// the other clients and allocations are not starved by the noisy one
+	require.True(t, Allow("noisy", "other"))
+	require.True(t, Allow("quiet", "client"))
+	for i := 0; i < 10; i++ {
+		require.True(t, Allow("partner", "client"))
+	}
+	require.True(t, Allow("", "client"))
+
+	list := GetCounters()
+	require.Len(t, list, 1)
+	require.Equal(t, "noisy", list[0].AllocationID)
+	require.EqualValues(t, 1, list[0].RejectedRequests)
+}
+
+func TestAllow_OverrideChanged(t *testing.T) {
+	setLimits(t, Limits{RPS: 1})
+	require.True(t, Allow("alloc", "client"))
+	require.False(t, Allow("alloc", "client"))
+
+	// the limiter of the allocation is rebuilt with its new limits
+	rps := 100.0
+	overridesMu.Lock()
+	overrides["alloc"] = &Override{AllocationID: "alloc", RPS: &rps}
+	overridesMu.Unlock()
+	require.True(t, Allow("alloc", "client"))
+}
+
+func TestLimited(t *testing.T) {
+	setLimits(t, Limits{})
+	require.False(t, Limited())
+
+	rps := 1.0
+	overridesMu.Lock()
+	overrides["alloc"] = &Override{AllocationID: "alloc", RPS: &rps}
+	overridesMu.Unlock()
+	require.True(t, Limited())
+
+	setLimits(t, Limits{DownloadBPS: 1000})
+	require.True(t, Limited())
+}
+
+func TestRemoveIdle(t *testing.T) {
+	setLimits(t, Limits{RPS: 1})
+	require.True(t, Allow("idle", "client"))
+	require.False(t, Allow("idle", "client"))
+	require.True(t, Allow("active", "client"))
+	require.False(t, Allow("active", "client"))
+	require.True(t, Allow("active", "gone"))
+
+	mu.Lock()
+	limiters["idle"].lastUsed = time.Now().Add(-2 * idleTTL)
+	limiters["active"].clients["gone"].lastUsed = time.Now().Add(-2 * idleTTL)
+	getCounters("orphan").ThrottledDownloads++
+	mu.Unlock()
+
+	// the counters go along the limiters of the idle allocations
+	removeIdle(time.Now())
+	list := GetCounters()
+	require.Len(t, list, 1)
+	require.Equal(t, "active", list[0].AllocationID)
+
+	// and the limiters of the idle clients of the active ones
+	mu.Lock()
+	require.NotContains(t, limiters["active"].clients, "gone")
+	require.Contains(t, limiters["active"].clients, "client")
+	mu.Unlock()
+}
+
+func TestUploadReader(t *testing.T) {
+	setLimits(t, Limits{UploadBPS: 1000})
+
+	data := bytes.Repeat([]byte("x"), 1500)
+	start := time.Now()
+	r := UploadReader(context.Background(), "alloc", io.NopCloser(bytes.NewReader(data)))
+	read, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, data, read)
+	// a burst of a second, then half a second for the rest
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	list := GetCounters()
+	require.Len(t, list, 1)
+	require.EqualValues(t, 1, list[0].ThrottledUploads)
+}
+
+func TestUploadReader_Cancelled(t *testing.T) {
+	setLimits(t, Limits{UploadBPS: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := UploadReader(ctx, "alloc", io.NopCloser(bytes.NewReader(make([]byte, 1000))))
+	_, err := io.ReadAll(r)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDownloadWriter(t *testing.T) {
+	setLimits(t, Limits{DownloadBPS: 1000})
+
+	rec := httptest.NewRecorder()
+	require.Same(t, rec, DownloadWriter(context.Background(), "", rec))
+
+	data := bytes.Repeat([]byte("x"), 1500)
+	start := time.Now()
+	n, err := DownloadWriter(context.Background(), "alloc", rec).Write(data)
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+	require.Equal(t, data, rec.Body.Bytes())
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+
+	list := GetCounters()
+	require.Len(t, list, 1)
+	require.EqualValues(t, 1, list[0].ThrottledDownloads)
+}
+
+func TestShaping_Unlimited(t *testing.T) {
+	setLimits(t, Limits{})
+
+	body := io.NopCloser(bytes.NewReader(nil))
+	require.Equal(t, body, UploadReader(context.Background(), "alloc", body))
+	rec := httptest.NewRecorder()
+	require.Same(t, rec, DownloadWriter(context.Background(), "alloc", rec))
+}
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// shaper holds back a transfer of an allocation to its bandwidth limit.
+type shaper struct {
+	ctx          context.Context
+	allocationID string
+	kind         string
+	limiter      *rate.Limiter
+	throttled    bool
+}
+
+// wait takes n bytes out of the bucket, in pieces of at most a burst, and
+// sleeps until they are available.
+func (s *shaper) wait(n int) error {
+	for n > 0 {
+		take := n
+		if burst := s.limiter.Burst(); take > burst {
+			take = burst
+		}
+		r := s.limiter.ReserveN(time.Now(), take)
+		if delay := r.Delay(); delay > 0 {
+			s.markThrottled()
+			t := time.NewTimer(delay)
+			select {
+			case <-s.ctx.Done():
+				t.Stop()
+				r.Cancel()
+				return s.ctx.Err()
+			case <-t.C:
+			}
+		}
+		n -= take
+	}
+	return nil
+}
+
+// markThrottled counts the transfer once, on its first wait.
+func (s *shaper) markThrottled() {
+	if s.throttled {
+		return
+	}
+	s.throttled = true
+	mu.Lock()
+	c := getCounters(s.allocationID)
+	if s.kind == "upload" {
+		c.ThrottledUploads++
+	} else {
+		c.ThrottledDownloads++
+	}
+	mu.Unlock()
+	rejected.WithLabelValues(s.kind).Inc()
+}
+
+func newShaper(ctx context.Context, allocationID, kind string) *shaper {
+	if allocationID == "" {
+		return nil
+	}
+	mu.Lock()
+	l := getLimiter(allocationID)
+	mu.Unlock()
+	limiter := l.upload
+	if kind == "download" {
+		limiter = l.download
+	}
+	if limiter == nil {
+		return nil
+	}
+	return &shaper{ctx: ctx, allocationID: allocationID, kind: kind, limiter: limiter}
+}
+
+type shapedReader struct {
+	io.ReadCloser
+	s *shaper
+}
+
+func (r *shapedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := r.s.wait(n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// UploadReader shapes the body of an upload to the upload limit of the
+// allocation. It returns body when the allocation has no upload limit.
+func UploadReader(ctx context.Context, allocationID string, body io.ReadCloser) io.ReadCloser {
+	s := newShaper(ctx, allocationID, "upload")
+	if s == nil {
+		return body
+	}
+	return &shapedReader{ReadCloser: body, s: s}
+}
+
+type shapedWriter struct {
+	http.ResponseWriter
+	s *shaper
+}
+
+// Write writes p a burst at a time, so a large download is spread over time
+// instead of sent at once after a long wait.
+func (w *shapedWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if burst := w.s.limiter.Burst(); len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+		if err := w.s.wait(len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.ResponseWriter.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (w *shapedWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// DownloadWriter shapes the response of a download to the download limit of
+// the allocation. It returns w when the allocation has no download limit.
+func DownloadWriter(ctx context.Context, allocationID string, w http.ResponseWriter) http.ResponseWriter {
+	s := newShaper(ctx, allocationID, "download")
+	if s == nil {
+		return w
+	}
+	return &shapedWriter{ResponseWriter: w, s: s}
+}
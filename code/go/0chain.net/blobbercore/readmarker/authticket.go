@@ -2,6 +2,7 @@ package readmarker
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
@@ -27,6 +28,10 @@ type AuthTicket struct {
 	ReEncryptionKey string           `json:"re_encryption_key"`
 	Signature       string           `json:"signature"`
 	Encrypted       bool             `json:"encrypted"`
+	// MaxDownloads and MaxBytes optionally limit how often and how much the
+	// ticket can be used to download from this blobber, 0 means no limit.
+	MaxDownloads int64 `json:"max_downloads,omitempty"`
+	MaxBytes     int64 `json:"max_bytes,omitempty"`
 }
 
 func (rm *AuthTicket) GetHashData() string {
@@ -43,9 +48,28 @@ func (rm *AuthTicket) GetHashData() string {
 		rm.ActualFileHash,
 		rm.Encrypted,
 	)
+	// limits are only part of the signed data when set, so tickets issued
+	// before they were introduced stay valid
+	if rm.MaxDownloads > 0 || rm.MaxBytes > 0 {
+		hashData += fmt.Sprintf(":%v:%v", rm.MaxDownloads, rm.MaxBytes)
+	}
 	return hashData
 }
 
+// Hash identifies the ticket in the blobber revocation list and usage records.
+func (rm *AuthTicket) Hash() string {
+	return encryption.Hash(rm.GetHashData())
+}
+
+// CoversPath reports whether a ref at path is within the ticket scope. A ticket
+// for a directory covers its whole subtree, a ticket for a file only the file.
+func CoversPath(ticketPath, path string) bool {
+	if ticketPath == path || ticketPath == "/" {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(ticketPath, "/")+"/")
+}
+
 func (authToken *AuthTicket) Verify(allocationObj *allocation.Allocation, clientID string) error {
 	if authToken.AllocationID != allocationObj.ID {
 		return common.NewError("invalid_parameters", "Invalid auth ticket. Allocation id mismatch")
@@ -71,12 +95,19 @@ func (authToken *AuthTicket) Verify(allocationObj *allocation.Allocation, client
 		return common.NewError("invalid_parameters", "Invalid auth ticket. Timestamp in future")
 	}
 
-	hashData := authToken.GetHashData()
-	signatureHash := encryption.Hash(hashData)
+	if authToken.MaxDownloads < 0 || authToken.MaxBytes < 0 {
+		return common.NewError("invalid_parameters", "Invalid auth ticket. Negative usage limit")
+	}
+
+	signatureHash := authToken.Hash()
 	sigOK, err := encryption.Verify(allocationObj.OwnerPublicKey, authToken.Signature, signatureHash)
 	if err != nil || !sigOK {
 		return common.NewError("invalid_parameters", "Invalid auth ticket. Signature verification failed")
 	}
 
+	if IsAuthTicketRevoked(allocationObj.ID, signatureHash) {
+		return common.NewError("invalid_parameters", "Invalid auth ticket. Ticket revoked")
+	}
+
 	return nil
 }
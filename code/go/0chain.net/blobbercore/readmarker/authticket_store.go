@@ -0,0 +1,221 @@
+package readmarker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthTicketRecord keeps the usage and the revocation state of an auth ticket
+// on this blobber. Records are created when a ticket is first used to download
+// or when the owner revokes it.
+// swagger:model AuthTicketRecord
+type AuthTicketRecord struct {
+	AllocationID    string           `gorm:"column:allocation_id;size:64;primaryKey" json:"allocation_id"`
+	TicketHash      string           `gorm:"column:ticket_hash;size:64;primaryKey" json:"ticket_hash"`
+	OwnerID         string           `gorm:"column:owner_id;size:64;not null" json:"owner_id"`
+	ClientID        string           `gorm:"column:client_id;size:64;not null;default:''" json:"client_id"`
+	FilePathHash    string           `gorm:"column:file_path_hash;size:64;not null;default:''" json:"file_path_hash"`
+	FileName        string           `gorm:"column:file_name;not null;default:''" json:"file_name"`
+	RefType         string           `gorm:"column:ref_type;size:1;not null;default:''" json:"reference_type"`
+	Expiration      common.Timestamp `gorm:"column:expiration;not null;default:0" json:"expiration"`
+	MaxDownloads    int64            `gorm:"column:max_downloads;not null;default:0" json:"max_downloads"`
+	MaxBytes        int64            `gorm:"column:max_bytes;not null;default:0" json:"max_bytes"`
+	Downloads       int64            `gorm:"column:downloads;not null;default:0" json:"downloads"`
+	BytesDownloaded int64            `gorm:"column:bytes_downloaded;not null;default:0" json:"bytes_downloaded"`
+	Revoked         bool             `gorm:"column:revoked;not null;default:false" json:"revoked"`
+	RevokedAt       *time.Time       `gorm:"column:revoked_at" json:"revoked_at,omitempty"`
+	datastore.ModelWithTS
+}
+
+func (AuthTicketRecord) TableName() string {
+	return "auth_tickets"
+}
+
+// revokedTickets is the in-memory revocation list, keyed by allocation id and ticket hash.
+var revokedTickets = struct {
+	sync.RWMutex
+	m map[string]struct{}
+}{m: make(map[string]struct{})}
+
+func revokedKey(allocationID, ticketHash string) string {
+	return allocationID + ":" + ticketHash
+}
+
+// IsAuthTicketRevoked checks the ticket against the revocation list.
+func IsAuthTicketRevoked(allocationID, ticketHash string) bool {
+	revokedTickets.RLock()
+	defer revokedTickets.RUnlock()
+	_, ok := revokedTickets.m[revokedKey(allocationID, ticketHash)]
+	return ok
+}
+
+func addRevokedTickets(allocationID string, ticketHashes []string) {
+	revokedTickets.Lock()
+	defer revokedTickets.Unlock()
+	for _, h := range ticketHashes {
+		revokedTickets.m[revokedKey(allocationID, h)] = struct{}{}
+	}
+}
+
+// LoadRevokedAuthTickets fills the revocation list from the database.
+func LoadRevokedAuthTickets(ctx context.Context) error {
+	db := datastore.GetStore().GetTransaction(ctx)
+	var records []*AuthTicketRecord
+	err := db.Select("allocation_id", "ticket_hash").Where("revoked = ?", true).Find(&records).Error
+	if err != nil {
+		return err
+	}
+
+	revokedTickets.Lock()
+	defer revokedTickets.Unlock()
+	for _, r := range records {
+		revokedTickets.m[revokedKey(r.AllocationID, r.TicketHash)] = struct{}{}
+	}
+	return nil
+}
+
+// CheckAuthTicket refuses a ticket revoked or used up on this blobber, before
+// the blocks of a download are read. UseAuthTicket still accounts the download
+// within the limits once the blocks are read.
+func CheckAuthTicket(ctx context.Context, at *AuthTicket, newDownload bool) error {
+	db := datastore.GetStore().GetTransaction(ctx)
+	var record AuthTicketRecord
+	err := db.Select("revoked", "max_downloads", "max_bytes", "downloads", "bytes_downloaded").
+		Where("allocation_id = ? AND ticket_hash = ?", at.AllocationID, at.Hash()).
+		Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.Revoked {
+		return common.NewError("auth_ticket_revoked", "auth ticket revoked")
+	}
+	if record.usedUp(newDownload) {
+		return common.NewError("auth_ticket_limit", "auth ticket usage limit reached")
+	}
+	return nil
+}
+
+// usedUp reports whether the ticket has no download or bytes left.
+func (r *AuthTicketRecord) usedUp(newDownload bool) bool {
+	if newDownload && r.MaxDownloads > 0 && r.Downloads >= r.MaxDownloads {
+		return true
+	}
+	return r.MaxBytes > 0 && r.BytesDownloaded >= r.MaxBytes
+}
+
+// UseAuthTicket accounts a download of numBytes made with the ticket. A new
+// download is counted against MaxDownloads only when newDownload is set, so a
+// file fetched in many block requests counts once. It fails without changing
+// anything if the ticket is revoked or the request would exceed its limits.
+func UseAuthTicket(ctx context.Context, at *AuthTicket, numBytes int64, newDownload bool) error {
+	var downloads int64
+	if newDownload {
+		downloads = 1
+	}
+
+	if (at.MaxDownloads > 0 && downloads > at.MaxDownloads) || (at.MaxBytes > 0 && numBytes > at.MaxBytes) {
+		return common.NewError("auth_ticket_limit", "auth ticket usage limit reached")
+	}
+
+	now := time.Now()
+	record := &AuthTicketRecord{
+		AllocationID:    at.AllocationID,
+		TicketHash:      at.Hash(),
+		OwnerID:         at.OwnerID,
+		ClientID:        at.ClientID,
+		FilePathHash:    at.FilePathHash,
+		FileName:        at.FileName,
+		RefType:         at.RefType,
+		Expiration:      at.Expiration,
+		MaxDownloads:    at.MaxDownloads,
+		MaxBytes:        at.MaxBytes,
+		Downloads:       downloads,
+		BytesDownloaded: numBytes,
+		ModelWithTS:     datastore.ModelWithTS{CreatedAt: now, UpdatedAt: now},
+	}
+
+	// the conditional upsert keeps concurrent downloads within the limits
+	db := datastore.GetStore().GetTransaction(ctx)
+	res := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "allocation_id"}, {Name: "ticket_hash"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "downloads"}, Value: clause.Expr{SQL: "auth_tickets.downloads + EXCLUDED.downloads"}},
+			{Column: clause.Column{Name: "bytes_downloaded"}, Value: clause.Expr{SQL: "auth_tickets.bytes_downloaded + EXCLUDED.bytes_downloaded"}},
+			{Column: clause.Column{Name: "updated_at"}, Value: clause.Expr{SQL: "EXCLUDED.updated_at"}},
+		},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "NOT auth_tickets.revoked"},
+			clause.Expr{SQL: "(auth_tickets.max_downloads = 0 OR auth_tickets.downloads + EXCLUDED.downloads <= auth_tickets.max_downloads)"},
+			clause.Expr{SQL: "(auth_tickets.max_bytes = 0 OR auth_tickets.bytes_downloaded + EXCLUDED.bytes_downloaded <= auth_tickets.max_bytes)"},
+		}},
+	}).Create(record)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return common.NewError("auth_ticket_limit", "auth ticket usage limit reached")
+	}
+	return nil
+}
+
+// ListAuthTickets returns the auth tickets of the allocation seen by this blobber.
+// Revoked and expired tickets are only listed if all is set.
+func ListAuthTickets(ctx context.Context, allocationID string, all bool, limit common.Pagination) ([]*AuthTicketRecord, error) {
+	db := datastore.GetStore().GetTransaction(ctx)
+	query := db.Where("allocation_id = ?", allocationID)
+	if !all {
+		query = query.Where("revoked = ? AND (expiration = 0 OR expiration > ?)", false, common.Now())
+	}
+
+	order := "created_at asc"
+	if limit.IsDescending {
+		order = "created_at desc"
+	}
+
+	records := make([]*AuthTicketRecord, 0)
+	err := query.Order(order).Limit(limit.Limit).Offset(limit.Offset).Find(&records).Error
+	return records, err
+}
+
+// RevokeAuthTickets adds the tickets to the revocation list of the allocation.
+// Tickets never used on this blobber are revoked as well.
+func RevokeAuthTickets(ctx context.Context, allocationID, ownerID string, ticketHashes []string) error {
+	if len(ticketHashes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]*AuthTicketRecord, 0, len(ticketHashes))
+	for _, h := range ticketHashes {
+		records = append(records, &AuthTicketRecord{
+			AllocationID: allocationID,
+			TicketHash:   h,
+			OwnerID:      ownerID,
+			Revoked:      true,
+			RevokedAt:    &now,
+			ModelWithTS:  datastore.ModelWithTS{CreatedAt: now, UpdatedAt: now},
+		})
+	}
+
+	db := datastore.GetStore().GetTransaction(ctx)
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "allocation_id"}, {Name: "ticket_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked", "revoked_at", "updated_at"}),
+	}).Create(&records).Error
+	if err != nil {
+		return err
+	}
+
+	addRevokedTickets(allocationID, ticketHashes)
+	return nil
+}
@@ -0,0 +1,85 @@
+package readmarker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	coreConfig "github.com/0chain/blobber/code/go/0chain.net/core/config"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoversPath(t *testing.T) {
+	require.True(t, CoversPath("/a/b.txt", "/a/b.txt"))
+	require.True(t, CoversPath("/", "/a/b.txt"))
+	require.True(t, CoversPath("/a", "/a/b.txt"))
+	require.True(t, CoversPath("/a/", "/a/c/b.txt"))
+	require.False(t, CoversPath("/a", "/ab/b.txt"))
+	require.False(t, CoversPath("/a/b.txt", "/a"))
+}
+
+func TestAuthTicket_HashData(t *testing.T) {
+	at := &AuthTicket{AllocationID: "alloc", FilePathHash: "hash", OwnerID: "owner"}
+
+	// tickets without limits keep the hash data of older clients
+	require.Equal(t, "alloc::owner:hash::::0:0::false", at.GetHashData())
+
+	at.MaxDownloads = 3
+	require.Equal(t, "alloc::owner:hash::::0:0::false:3:0", at.GetHashData())
+}
+
+func TestAuthTicket_Refused(t *testing.T) {
+	coreConfig.Configuration.SignatureScheme = "bls0chain"
+	wallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	sch := zcncrypto.NewSignatureScheme("bls0chain")
+	require.NoError(t, sch.SetPrivateKey(wallet.Keys[0].PrivateKey))
+
+	alloc := &allocation.Allocation{ID: "refused_alloc", OwnerID: "owner", OwnerPublicKey: wallet.Keys[0].PublicKey}
+	at := &AuthTicket{
+		AllocationID: alloc.ID,
+		OwnerID:      alloc.OwnerID,
+		FilePathHash: "hash",
+		Timestamp:    common.Now(),
+		MaxBytes:     100,
+	}
+	at.Signature, err = sch.Sign(at.Hash())
+	require.NoError(t, err)
+	require.NoError(t, at.Verify(alloc, "client"))
+
+	// a download over the limits of the ticket is refused before any record
+	err = UseAuthTicket(context.Background(), at, 101, true)
+	require.ErrorContains(t, err, "auth_ticket_limit")
+
+	// a revoked ticket no longer verifies, before any block is read
+	addRevokedTickets(alloc.ID, []string{at.Hash()})
+	t.Cleanup(func() {
+		revokedTickets.Lock()
+		delete(revokedTickets.m, revokedKey(alloc.ID, at.Hash()))
+		revokedTickets.Unlock()
+	})
+	require.ErrorContains(t, at.Verify(alloc, "client"), "Ticket revoked")
+}
+
+func TestAuthTicketRecord_UsedUp(t *testing.T) {
+	r := &AuthTicketRecord{MaxDownloads: 2, Downloads: 2}
+	// the blocks after the first one belong to a download already counted
+	require.True(t, r.usedUp(true))
+	require.False(t, r.usedUp(false))
+
+	r = &AuthTicketRecord{MaxBytes: 100, BytesDownloaded: 100}
+	require.True(t, r.usedUp(false))
+	r.BytesDownloaded = 99
+	require.False(t, r.usedUp(false))
+	require.False(t, (&AuthTicketRecord{Downloads: 10, BytesDownloaded: 10}).usedUp(true))
+}
+
+func TestRevokedAuthTickets(t *testing.T) {
+	require.False(t, IsAuthTicketRevoked("alloc", "h1"))
+	addRevokedTickets("alloc", []string{"h1", "h2"})
+	require.True(t, IsAuthTicketRevoked("alloc", "h1"))
+	require.True(t, IsAuthTicketRevoked("alloc", "h2"))
+	require.False(t, IsAuthTicketRevoked("other", "h1"))
+}
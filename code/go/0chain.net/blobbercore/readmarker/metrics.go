@@ -0,0 +1,22 @@
+package readmarker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of the redemptions of the read markers.
+const (
+	redeemSuccess = "success"
+	// redeemFailed is a redemption rejected by, or not confirmed on, the chain.
+	redeemFailed = "failed"
+	// redeemError is a redemption not sent to the chain for a local error.
+	redeemError = "error"
+	// redeemSynced is a read marker already redeemed on the chain.
+	redeemSynced = "synced"
+)
+
+var redemptions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "blobber_readmarker_redemptions_total",
+	Help: "Redemptions of the read markers, by outcome.",
+}, []string{"result"})
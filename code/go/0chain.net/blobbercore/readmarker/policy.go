@@ -0,0 +1,306 @@
+package readmarker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/gosdk/zcncore"
+	"go.uber.org/zap"
+)
+
+// Reasons for a redeem decision, mostly useful in logs.
+const (
+	RedeemReasonPolicyOff   = "policy_disabled"
+	RedeemReasonValue       = "value_covers_fee"
+	RedeemReasonExpiry      = "allocation_expiring"
+	RedeemReasonMaxDeferral = "max_deferral_reached"
+	RedeemReasonBelowFee    = "value_below_fee"
+)
+
+// RedeemPolicy decides whether redeeming a read marker is worth the
+// transaction fee. A deferred marker keeps its cumulative read counter, so
+// small reads are grouped into a single redemption once their value passes
+// the threshold.
+type RedeemPolicy struct {
+	Enabled bool
+	// MinValueFeeRatio is the minimal ratio of pending value to the fee.
+	MinValueFeeRatio float64
+	// ExpiryWindow forces redemption when the allocation expires within it.
+	ExpiryWindow time.Duration
+	// MaxDeferral forces redemption of markers deferred for longer, 0 means no limit.
+	MaxDeferral time.Duration
+}
+
+func currentRedeemPolicy() *RedeemPolicy {
+	return &RedeemPolicy{
+		Enabled:          config.Configuration.RMRedeemFeeAware,
+		MinValueFeeRatio: config.Configuration.RMRedeemMinValueFeeRatio,
+		ExpiryWindow:     config.Configuration.RMRedeemExpiryWindow,
+		MaxDeferral:      config.Configuration.RMRedeemMaxDeferral,
+	}
+}
+
+// ShouldRedeem reports whether a marker with pending value (in SAS) should be
+// redeemed now given the estimated fee (in SAS).
+func (p *RedeemPolicy) ShouldRedeem(value, fee float64, expiration common.Timestamp, deferredSince, now time.Time) (bool, string) {
+	if !p.Enabled {
+		return true, RedeemReasonPolicyOff
+	}
+
+	if value >= fee*p.MinValueFeeRatio {
+		return true, RedeemReasonValue
+	}
+
+	if expiration > 0 && time.Unix(int64(expiration), 0).Sub(now) <= p.ExpiryWindow {
+		return true, RedeemReasonExpiry
+	}
+
+	if p.MaxDeferral > 0 && !deferredSince.IsZero() && now.Sub(deferredSince) >= p.MaxDeferral {
+		return true, RedeemReasonMaxDeferral
+	}
+
+	return false, RedeemReasonBelowFee
+}
+
+// DeferredStat is the amount of read marker value the blobber has not
+// redeemed yet because it would not cover the redeem fee.
+type DeferredStat struct {
+	Markers int64   `json:"markers"`
+	Blocks  int64   `json:"blocks"`
+	Value   float64 `json:"value"`
+}
+
+type deferredMarker struct {
+	allocationID string
+	blocks       int64
+	value        float64
+	since        time.Time
+}
+
+var deferred = struct {
+	sync.Mutex
+	markers map[string]*deferredMarker
+}{markers: make(map[string]*deferredMarker)}
+
+func deferredKey(rme *ReadMarkerEntity) string {
+	return rme.LatestRM.ClientID + ":" + rme.LatestRM.AllocationID
+}
+
+func markDeferred(rme *ReadMarkerEntity, blocks int64, value float64, now time.Time) {
+	deferred.Lock()
+	defer deferred.Unlock()
+
+	key := deferredKey(rme)
+	dm, ok := deferred.markers[key]
+	if !ok {
+		dm = &deferredMarker{allocationID: rme.LatestRM.AllocationID, since: now}
+		if rme.DeferredSince > 0 {
+			dm.since = common.ToTime(rme.DeferredSince)
+		}
+		deferred.markers[key] = dm
+	}
+	dm.blocks = blocks
+	dm.value = value
+}
+
+func clearDeferred(rme *ReadMarkerEntity) {
+	deferred.Lock()
+	delete(deferred.markers, deferredKey(rme))
+	deferred.Unlock()
+}
+
+// pruneDeferred drops deferred markers that no longer need redeeming, e.g.
+// because they were synced from the chain.
+func pruneDeferred(rms []*ReadMarkerEntity) {
+	pending := make(map[string]struct{}, len(rms))
+	for _, rme := range rms {
+		pending[deferredKey(rme)] = struct{}{}
+	}
+
+	deferred.Lock()
+	defer deferred.Unlock()
+	for key := range deferred.markers {
+		if _, ok := pending[key]; !ok {
+			delete(deferred.markers, key)
+		}
+	}
+}
+
+// deferredSince returns when the redemption of the marker was first deferred,
+// as saved with the marker so the max deferral holds across restarts.
+func deferredSince(rme *ReadMarkerEntity) time.Time {
+	if rme.DeferredSince > 0 {
+		return common.ToTime(rme.DeferredSince)
+	}
+
+	deferred.Lock()
+	defer deferred.Unlock()
+
+	if dm, ok := deferred.markers[deferredKey(rme)]; ok {
+		return dm.since
+	}
+	return time.Time{}
+}
+
+// saveDeferral saves the start of the deferral of the marker, unless it is
+// deferred already.
+func saveDeferral(ctx context.Context, rme *ReadMarkerEntity, now time.Time) error {
+	if rme.DeferredSince > 0 {
+		return nil
+	}
+	since := common.Timestamp(now.Unix())
+	db := datastore.GetStore().GetTransaction(ctx)
+	err := db.Model(&ReadMarkerEntity{}).
+		Where("client_id = ? AND allocation_id = ? AND deferred_since = 0", rme.LatestRM.ClientID, rme.LatestRM.AllocationID).
+		Update("deferred_since", since).Error
+	if err != nil {
+		return err
+	}
+	rme.DeferredSince = since
+	return nil
+}
+
+// resetDeferral clears the saved deferral of the marker, once redeemed or
+// synced from the chain.
+func resetDeferral(ctx context.Context, rme *ReadMarkerEntity) error {
+	db := datastore.GetStore().GetTransaction(ctx)
+	err := db.Model(&ReadMarkerEntity{}).
+		Where("client_id = ? AND allocation_id = ?", rme.LatestRM.ClientID, rme.LatestRM.AllocationID).
+		Update("deferred_since", 0).Error
+	if err != nil {
+		return err
+	}
+	rme.DeferredSince = 0
+	return nil
+}
+
+// GetDeferredStat returns the deferred read marker value of the allocation,
+// or of all allocations if allocationID is empty.
+func GetDeferredStat(allocationID string) DeferredStat {
+	deferred.Lock()
+	defer deferred.Unlock()
+
+	var ds DeferredStat
+	for _, dm := range deferred.markers {
+		if allocationID != "" && dm.allocationID != allocationID {
+			continue
+		}
+		ds.Markers++
+		ds.Blocks += dm.blocks
+		ds.Value += dm.value
+	}
+	return ds
+}
+
+var feeEstimate = struct {
+	sync.Mutex
+	fee       float64
+	updatedAt time.Time
+}{}
+
+// estimateRedeemFee returns the configured redeem fee or the mean fee reported
+// by the miners. The estimate is cached for the configured refresh interval.
+func estimateRedeemFee(ctx context.Context) (float64, error) {
+	if config.Configuration.RMRedeemFee > 0 {
+		return float64(config.Configuration.RMRedeemFee), nil
+	}
+
+	feeEstimate.Lock()
+	defer feeEstimate.Unlock()
+
+	if !feeEstimate.updatedAt.IsZero() && time.Since(feeEstimate.updatedAt) < config.Configuration.RMRedeemFeeRefreshInterval {
+		return feeEstimate.fee, nil
+	}
+
+	stats, err := zcncore.GetFeeStats(ctx)
+	if err != nil {
+		return 0, common.NewErrorf("estimate_redeem_fee", "getting fee stats: %v", err)
+	}
+
+	feeEstimate.fee = float64(stats.MeanFees)
+	feeEstimate.updatedAt = time.Now()
+	return feeEstimate.fee, nil
+}
+
+// filterRedeemable returns the read markers that are worth redeeming now at
+// the estimated fee and records the others as deferred. The fee is estimated
+// by the caller, before opening the database transaction, as it may query the
+// miners.
+func filterRedeemable(ctx context.Context, rms []*ReadMarkerEntity, fee float64, feeErr error) []*ReadMarkerEntity {
+	policy := currentRedeemPolicy()
+	if !policy.Enabled {
+		pruneDeferred(nil)
+		return rms
+	}
+
+	pruneDeferred(rms)
+	if len(rms) == 0 {
+		return rms
+	}
+
+	if feeErr != nil {
+		// redeem as before rather than holding tokens back on a guess
+		logging.Logger.Error("redeem_readmarker: fee estimate failed, redeeming all", zap.Error(feeErr))
+		return rms
+	}
+
+	allocs := make(map[string]*allocation.Allocation)
+	now := time.Now()
+	redeemable := make([]*ReadMarkerEntity, 0, len(rms))
+	for _, rme := range rms {
+		blocks, err := rme.PendNumBlocks()
+		if err != nil {
+			logging.Logger.Error("redeem_readmarker: pending blocks", zap.Error(err))
+			continue
+		}
+
+		allocID := rme.LatestRM.AllocationID
+		alloc, ok := allocs[allocID]
+		if !ok {
+			alloc, err = allocation.Repo.GetAllocationFromDB(ctx, allocID)
+			if err == nil {
+				err = alloc.LoadTerms(ctx)
+			}
+			if err != nil {
+				logging.Logger.Error("redeem_readmarker: loading allocation",
+					zap.String("allocation_id", allocID), zap.Error(err))
+				redeemable = append(redeemable, rme)
+				continue
+			}
+			allocs[allocID] = alloc
+		}
+
+		value := alloc.GetRequiredReadBalance(node.Self.ID, blocks)
+		ok, reason := policy.ShouldRedeem(value, fee, alloc.Expiration, deferredSince(rme), now)
+		if !ok {
+			if err := saveDeferral(ctx, rme, now); err != nil {
+				logging.Logger.Error("redeem_readmarker: saving deferral",
+					zap.String("allocation_id", allocID), zap.Error(err))
+			}
+			markDeferred(rme, blocks, value, now)
+			logging.Logger.Debug("redeem_readmarker: deferred",
+				zap.String("allocation_id", allocID),
+				zap.String("client_id", rme.LatestRM.ClientID),
+				zap.Int64("blocks", blocks),
+				zap.Float64("value", value),
+				zap.Float64("fee", fee))
+			continue
+		}
+
+		clearDeferred(rme)
+		logging.Logger.Info("redeem_readmarker: redeeming",
+			zap.String("allocation_id", allocID),
+			zap.String("reason", reason),
+			zap.Float64("value", value),
+			zap.Float64("fee", fee))
+		redeemable = append(redeemable, rme)
+	}
+	return redeemable
+}
@@ -0,0 +1,113 @@
+package readmarker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedeemPolicy_ShouldRedeem(t *testing.T) {
+	now := time.Now()
+	farExpiry := common.Timestamp(now.Add(30 * 24 * time.Hour).Unix())
+	policy := &RedeemPolicy{
+		Enabled:          true,
+		MinValueFeeRatio: 2,
+		ExpiryWindow:     time.Hour,
+		MaxDeferral:      24 * time.Hour,
+	}
+
+	tests := []struct {
+		name          string
+		policy        *RedeemPolicy
+		value         float64
+		expiration    common.Timestamp
+		deferredSince time.Time
+		redeem        bool
+		reason        string
+	}{
+		{
+			name:       "disabled policy always redeems",
+			policy:     &RedeemPolicy{},
+			value:      1,
+			expiration: farExpiry,
+			redeem:     true,
+			reason:     RedeemReasonPolicyOff,
+		},
+		{
+			name:       "value covers the fee",
+			value:      200,
+			expiration: farExpiry,
+			redeem:     true,
+			reason:     RedeemReasonValue,
+		},
+		{
+			name:       "small value is deferred",
+			value:      150,
+			expiration: farExpiry,
+			reason:     RedeemReasonBelowFee,
+		},
+		{
+			name:       "allocation close to expiry",
+			value:      1,
+			expiration: common.Timestamp(now.Add(30 * time.Minute).Unix()),
+			redeem:     true,
+			reason:     RedeemReasonExpiry,
+		},
+		{
+			name:          "deferred for too long",
+			value:         1,
+			expiration:    farExpiry,
+			deferredSince: now.Add(-25 * time.Hour),
+			redeem:        true,
+			reason:        RedeemReasonMaxDeferral,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			if p == nil {
+				p = policy
+			}
+			redeem, reason := p.ShouldRedeem(tt.value, 100, tt.expiration, tt.deferredSince, now)
+			require.Equal(t, tt.redeem, redeem)
+			require.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestDeferredStat(t *testing.T) {
+	rme := func(client, alloc string) *ReadMarkerEntity {
+		return &ReadMarkerEntity{LatestRM: &ReadMarker{ClientID: client, AllocationID: alloc}}
+	}
+	a, b, c := rme("c1", "a1"), rme("c2", "a1"), rme("c1", "a2")
+	now := time.Now()
+
+	markDeferred(a, 10, 1.5, now)
+	markDeferred(b, 5, 0.5, now)
+	markDeferred(c, 1, 0.25, now)
+	require.Equal(t, DeferredStat{Markers: 2, Blocks: 15, Value: 2}, GetDeferredStat("a1"))
+	require.Equal(t, DeferredStat{Markers: 3, Blocks: 16, Value: 2.25}, GetDeferredStat(""))
+
+	// deferral start is kept while the marker stays deferred
+	markDeferred(a, 12, 1.8, now.Add(time.Minute))
+	require.Equal(t, now, deferredSince(a))
+
+	pruneDeferred([]*ReadMarkerEntity{a})
+	require.Equal(t, DeferredStat{Markers: 1, Blocks: 12, Value: 1.8}, GetDeferredStat(""))
+
+	clearDeferred(a)
+	require.True(t, deferredSince(a).IsZero())
+
+	// the deferral saved with the marker survives a restart
+	saved := rme("c3", "a3")
+	saved.DeferredSince = common.Timestamp(now.Add(-time.Hour).Unix())
+	require.Equal(t, common.ToTime(saved.DeferredSince), deferredSince(saved))
+	markDeferred(saved, 1, 0.1, now)
+	deferred.Lock()
+	require.Equal(t, common.ToTime(saved.DeferredSince), deferred.markers[deferredKey(saved)].since)
+	deferred.Unlock()
+	clearDeferred(saved)
+}
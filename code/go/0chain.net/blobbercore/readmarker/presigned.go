@@ -0,0 +1,237 @@
+package readmarker
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// PresignSchemeBLS urls are signed by the allocation owner with the wallet keys.
+	PresignSchemeBLS = "bls"
+	// PresignSchemeHMAC urls are issued and signed by this blobber on request of the owner.
+	PresignSchemeHMAC = "hmac"
+)
+
+// PresignedURL grants anyone holding the url the download of a block range
+// of a file until the url expires. Reads are paid in advance by the allocation owner.
+type PresignedURL struct {
+	AllocationID string           `json:"allocation_id"`
+	PathHash     string           `json:"path_hash"`
+	StartBlock   int64            `json:"start_block"`
+	EndBlock     int64            `json:"end_block"`
+	Expiration   common.Timestamp `json:"expiration"`
+	Scheme       string           `json:"scheme"`
+	Signature    string           `json:"signature"`
+}
+
+// PresignedURLFromQuery parses the query parameters of a pre-signed url.
+func PresignedURLFromQuery(allocationID string, query url.Values) (*PresignedURL, error) {
+	pu := &PresignedURL{
+		AllocationID: allocationID,
+		PathHash:     query.Get("path_hash"),
+		Scheme:       query.Get("scheme"),
+		Signature:    query.Get("sig"),
+	}
+	if pu.Scheme == "" {
+		pu.Scheme = PresignSchemeBLS
+	}
+
+	var err error
+	if pu.StartBlock, err = strconv.ParseInt(query.Get("start_block"), 10, 64); err != nil {
+		return nil, common.NewError("invalid_parameters", "invalid start_block")
+	}
+	if pu.EndBlock, err = strconv.ParseInt(query.Get("end_block"), 10, 64); err != nil {
+		return nil, common.NewError("invalid_parameters", "invalid end_block")
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return nil, common.NewError("invalid_parameters", "invalid expires")
+	}
+	pu.Expiration = common.Timestamp(expires)
+	return pu, nil
+}
+
+// Query returns the query parameters of the url.
+func (pu *PresignedURL) Query() url.Values {
+	query := url.Values{}
+	query.Set("path_hash", pu.PathHash)
+	query.Set("start_block", strconv.FormatInt(pu.StartBlock, 10))
+	query.Set("end_block", strconv.FormatInt(pu.EndBlock, 10))
+	query.Set("expires", strconv.FormatInt(int64(pu.Expiration), 10))
+	query.Set("scheme", pu.Scheme)
+	query.Set("sig", pu.Signature)
+	return query
+}
+
+func (pu *PresignedURL) GetHashData() string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v", pu.AllocationID, pu.PathHash, pu.StartBlock, pu.EndBlock, pu.Expiration)
+}
+
+// ID identifies the url in the revocation list. It doesn't depend on the
+// signature scheme so both urls with the same grant are revoked together.
+func (pu *PresignedURL) ID() string {
+	return encryption.Hash(pu.GetHashData())
+}
+
+// NumBlocks is the number of blocks served for the url.
+func (pu *PresignedURL) NumBlocks() int64 {
+	return pu.EndBlock - pu.StartBlock + 1
+}
+
+// SignHMAC signs the url with the secret of this blobber.
+func (pu *PresignedURL) SignHMAC() {
+	pu.Scheme = PresignSchemeHMAC
+	pu.Signature = presignHMAC(pu.GetHashData())
+}
+
+func presignHMAC(data string) string {
+	secret := config.Configuration.PresignedURLHMACSecret
+	if secret == "" {
+		secret = encryption.Hash("presigned_url:" + node.Self.GetWallet().Keys[0].PrivateKey)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(data)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Validate checks the block range and the expiration of the url.
+func (pu *PresignedURL) Validate(now common.Timestamp) error {
+	if pu.PathHash == "" {
+		return common.NewError("invalid_parameters", "path_hash is required")
+	}
+	if pu.StartBlock < 0 || pu.EndBlock < pu.StartBlock {
+		return common.NewErrorf("invalid_parameters", "invalid block range %v-%v", pu.StartBlock, pu.EndBlock)
+	}
+	if pu.NumBlocks() > config.Configuration.BlockLimitRequest {
+		return common.NewErrorf("invalid_parameters", "too many blocks: %v, max limit is %v", pu.NumBlocks(), config.Configuration.BlockLimitRequest)
+	}
+	if pu.Expiration < now {
+		return common.NewError("presigned_url_expired", "pre-signed url has expired")
+	}
+	maxExpiration := now + common.Timestamp(config.Configuration.PresignedURLMaxLifetime/time.Second)
+	if pu.Expiration > maxExpiration {
+		return common.NewErrorf("invalid_parameters", "expiration is too far in the future, max lifetime is %v", config.Configuration.PresignedURLMaxLifetime)
+	}
+	return nil
+}
+
+// Verify checks the url against the allocation, the revocation list and its signature.
+func (pu *PresignedURL) Verify(alloc *allocation.Allocation) error {
+	if pu.AllocationID != alloc.ID {
+		return common.NewError("invalid_parameters", "allocation id mismatch")
+	}
+	if err := pu.Validate(common.Now()); err != nil {
+		return err
+	}
+	if IsPresignedURLRevoked(alloc.ID, pu.ID()) {
+		return common.NewError("presigned_url_revoked", "pre-signed url has been revoked")
+	}
+
+	switch pu.Scheme {
+	case PresignSchemeBLS:
+		ok, err := encryption.Verify(alloc.OwnerPublicKey, pu.Signature, encryption.Hash(pu.GetHashData()))
+		if err != nil || !ok {
+			return common.NewError("invalid_signature", "invalid pre-signed url signature")
+		}
+	case PresignSchemeHMAC:
+		if !hmac.Equal([]byte(pu.Signature), []byte(presignHMAC(pu.GetHashData()))) {
+			return common.NewError("invalid_signature", "invalid pre-signed url signature")
+		}
+	default:
+		return common.NewErrorf("invalid_parameters", "unknown signature scheme %v", pu.Scheme)
+	}
+	return nil
+}
+
+// PresignedURLRevocation is an entry in the revocation list of pre-signed urls.
+type PresignedURLRevocation struct {
+	AllocationID string    `gorm:"column:allocation_id;size:64;primaryKey"`
+	URLID        string    `gorm:"column:url_id;size:64;primaryKey"`
+	RevokedAt    time.Time `gorm:"column:revoked_at;not null"`
+}
+
+func (PresignedURLRevocation) TableName() string {
+	return "presigned_url_revocations"
+}
+
+// revokedURLs is the in-memory revocation list, keyed by allocation id and url id.
+var revokedURLs = struct {
+	sync.RWMutex
+	m map[string]struct{}
+}{m: make(map[string]struct{})}
+
+// IsPresignedURLRevoked checks the url against the revocation list.
+func IsPresignedURLRevoked(allocationID, urlID string) bool {
+	revokedURLs.RLock()
+	defer revokedURLs.RUnlock()
+	_, ok := revokedURLs.m[revokedKey(allocationID, urlID)]
+	return ok
+}
+
+func addRevokedURLs(allocationID string, urlIDs []string) {
+	revokedURLs.Lock()
+	defer revokedURLs.Unlock()
+	for _, id := range urlIDs {
+		revokedURLs.m[revokedKey(allocationID, id)] = struct{}{}
+	}
+}
+
+// LoadRevokedPresignedURLs fills the revocation list from the database.
+func LoadRevokedPresignedURLs(ctx context.Context) error {
+	db := datastore.GetStore().GetTransaction(ctx)
+	var records []*PresignedURLRevocation
+	if err := db.Find(&records).Error; err != nil {
+		return err
+	}
+
+	revokedURLs.Lock()
+	defer revokedURLs.Unlock()
+	for _, r := range records {
+		revokedURLs.m[revokedKey(r.AllocationID, r.URLID)] = struct{}{}
+	}
+	return nil
+}
+
+// RevokePresignedURLs adds the urls to the revocation list of the allocation.
+func RevokePresignedURLs(ctx context.Context, allocationID string, urlIDs []string) error {
+	if len(urlIDs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]*PresignedURLRevocation, 0, len(urlIDs))
+	for _, id := range urlIDs {
+		records = append(records, &PresignedURLRevocation{
+			AllocationID: allocationID,
+			URLID:        id,
+			RevokedAt:    now,
+		})
+	}
+
+	// the revocation is committed on its own, the urls are only refused once it is saved
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		return datastore.GetStore().GetTransaction(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error
+	}, datastore.WithParent(ctx))
+	if err != nil {
+		return err
+	}
+
+	addRevokedURLs(allocationID, urlIDs)
+	return nil
+}
@@ -0,0 +1,119 @@
+package readmarker
+
+import (
+	"context"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+)
+
+// PresignedBalance are the blocks the allocation owner paid in advance for the
+// reads of its pre-signed urls. The owner pays them with read markers sent to
+// the redeem endpoint with the X-Presigned-Reads header, which the blobber
+// redeems against the read pool of the owner like any other read marker.
+type PresignedBalance struct {
+	AllocationID string `gorm:"column:allocation_id;size:64;primaryKey"`
+	Blocks       int64  `gorm:"column:blocks;not null;default:0"`
+}
+
+func (PresignedBalance) TableName() string {
+	return "presigned_balances"
+}
+
+// PresignedDailyBlocks are the blocks served with the pre-signed urls of the
+// owner in a day, over all its allocations.
+type PresignedDailyBlocks struct {
+	OwnerID string    `gorm:"column:owner_id;size:64;primaryKey"`
+	Day     time.Time `gorm:"column:day;type:date;primaryKey"`
+	Blocks  int64     `gorm:"column:blocks;not null;default:0"`
+}
+
+func (PresignedDailyBlocks) TableName() string {
+	return "presigned_daily_blocks"
+}
+
+// AddPresignedBlocks adds the blocks paid by the owner to the balance of the
+// allocation and returns the new balance.
+func AddPresignedBlocks(ctx context.Context, allocationID string, blocks int64) (int64, error) {
+	db := datastore.GetStore().GetTransaction(ctx)
+	var balance int64
+	err := db.Raw(`INSERT INTO presigned_balances (allocation_id, blocks) VALUES (?, ?)
+		ON CONFLICT (allocation_id) DO UPDATE SET blocks = presigned_balances.blocks + EXCLUDED.blocks
+		RETURNING blocks`, allocationID, blocks).Scan(&balance).Error
+	return balance, err
+}
+
+// ReservePresignedRead takes the blocks of a pre-signed read from the daily
+// budget of the owner and, unless reads are free, from the balance of the
+// allocation. Both are taken in a transaction of their own, committed before
+// the blocks are read, so concurrent reads cannot go over either of them.
+func ReservePresignedRead(ctx context.Context, allocationID, ownerID string, blocks int64, paid bool) error {
+	limit := config.Configuration.PresignedURLDailyBlockLimit
+	if blocks > limit {
+		return errPresignedDailyLimit
+	}
+
+	var reserveErr error
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		db := datastore.GetStore().GetTransaction(ctx)
+		res := db.Exec(`INSERT INTO presigned_daily_blocks (owner_id, day, blocks) VALUES (?, ?, ?)
+			ON CONFLICT (owner_id, day) DO UPDATE SET blocks = presigned_daily_blocks.blocks + EXCLUDED.blocks
+			WHERE presigned_daily_blocks.blocks + EXCLUDED.blocks <= ?`,
+			ownerID, presignedDay(time.Now()), blocks, limit)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			reserveErr = errPresignedDailyLimit
+			return reserveErr
+		}
+		if !paid {
+			return nil
+		}
+
+		res = db.Exec("UPDATE presigned_balances SET blocks = blocks - ? WHERE allocation_id = ? AND blocks >= ?",
+			blocks, allocationID, blocks)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			reserveErr = errPresignedBalance
+			return reserveErr
+		}
+		return nil
+	}, datastore.WithParent(ctx))
+	if reserveErr != nil {
+		return reserveErr
+	}
+	if err != nil {
+		return common.NewErrorf("download_file", "database error while reserving the pre-signed url blocks: %v", err)
+	}
+	return nil
+}
+
+// ReleasePresignedRead gives back the blocks of a reserved pre-signed read that
+// could not be served.
+func ReleasePresignedRead(ctx context.Context, allocationID, ownerID string, blocks int64, paid bool) error {
+	return datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		db := datastore.GetStore().GetTransaction(ctx)
+		err := db.Exec("UPDATE presigned_daily_blocks SET blocks = GREATEST(blocks - ?, 0) WHERE owner_id = ? AND day = ?",
+			blocks, ownerID, presignedDay(time.Now())).Error
+		if err != nil || !paid {
+			return err
+		}
+		return db.Exec("UPDATE presigned_balances SET blocks = blocks + ? WHERE allocation_id = ?",
+			blocks, allocationID).Error
+	}, datastore.WithParent(ctx))
+}
+
+var (
+	errPresignedDailyLimit = common.NewError("download_file", "daily pre-signed url block limit of the allocation owner reached")
+	errPresignedBalance    = common.NewError("not_enough_tokens", "no pre-signed url blocks paid by the allocation owner left")
+)
+
+// presignedDay is the day the daily budget of the owners is counted in.
+func presignedDay(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour)
+}
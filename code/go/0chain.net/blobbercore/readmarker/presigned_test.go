@@ -0,0 +1,105 @@
+package readmarker
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresignedURL_HMAC(t *testing.T) {
+	config.Configuration.BlockLimitRequest = 500
+	config.Configuration.PresignedURLMaxLifetime = 24 * time.Hour
+	config.Configuration.PresignedURLHMACSecret = "secret"
+
+	alloc := &allocation.Allocation{ID: "alloc"}
+	pu := &PresignedURL{
+		AllocationID: alloc.ID,
+		PathHash:     "path_hash",
+		StartBlock:   0,
+		EndBlock:     9,
+		Expiration:   common.Now() + 60,
+	}
+	id := pu.ID()
+	pu.SignHMAC()
+	require.Equal(t, id, pu.ID())
+	require.EqualValues(t, 10, pu.NumBlocks())
+
+	parsed, err := PresignedURLFromQuery(alloc.ID, pu.Query())
+	require.NoError(t, err)
+	require.Equal(t, pu, parsed)
+	require.NoError(t, parsed.Verify(alloc))
+
+	tampered := *parsed
+	tampered.EndBlock = 99
+	require.Error(t, tampered.Verify(alloc))
+
+	addRevokedURLs(alloc.ID, []string{id})
+	require.Error(t, parsed.Verify(alloc))
+}
+
+func TestPresignedURL_Validate(t *testing.T) {
+	config.Configuration.BlockLimitRequest = 500
+	config.Configuration.PresignedURLMaxLifetime = time.Hour
+	now := common.Now()
+
+	valid := PresignedURL{PathHash: "h", StartBlock: 1, EndBlock: 1, Expiration: now + 60}
+	require.NoError(t, valid.Validate(now))
+
+	for name, change := range map[string]func(pu *PresignedURL){
+		"no path hash":      func(pu *PresignedURL) { pu.PathHash = "" },
+		"reversed range":    func(pu *PresignedURL) { pu.StartBlock = 2 },
+		"too many blocks":   func(pu *PresignedURL) { pu.EndBlock = 501 },
+		"expired":           func(pu *PresignedURL) { pu.Expiration = now - 1 },
+		"too long lifetime": func(pu *PresignedURL) { pu.Expiration = now + 7200 },
+	} {
+		pu := valid
+		change(&pu)
+		require.Error(t, pu.Validate(now), name)
+	}
+
+	_, err := PresignedURLFromQuery("alloc", url.Values{"start_block": {"x"}})
+	require.Error(t, err)
+}
+
+func TestReservePresignedRead(t *testing.T) {
+	config.Configuration.PresignedURLDailyBlockLimit = 100
+	const daily = `INSERT INTO presigned_daily_blocks`
+	const balance = `UPDATE presigned_balances SET blocks = blocks - $1`
+
+	mock := datastore.MockTheStore(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(daily)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(balance)).WithArgs(int64(10), "alloc", int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, ReservePresignedRead(context.Background(), "alloc", "owner", 10, true))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(daily)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	require.Equal(t, errPresignedDailyLimit, ReservePresignedRead(context.Background(), "alloc", "owner", 10, true))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(daily)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(balance)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	require.Equal(t, errPresignedBalance, ReservePresignedRead(context.Background(), "alloc", "owner", 10, true),
+		"the daily blocks are given back with the balance missing")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(daily)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, ReservePresignedRead(context.Background(), "alloc", "owner", 10, false), "free reads need no balance")
+
+	require.Equal(t, errPresignedDailyLimit, ReservePresignedRead(context.Background(), "alloc", "owner", 101, false))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
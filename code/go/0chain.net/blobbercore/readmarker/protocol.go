@@ -73,14 +73,14 @@ func (rme *ReadMarkerEntity) RedeemReadMarker(ctx context.Context) (err error) {
 	return
 }
 
-func GetLatestReadMarkerEntityFromChain(clientID, allocID string) (*ReadMarker, error) {
+func GetLatestReadMarkerEntityFromChain(ctx context.Context, clientID, allocID string) (*ReadMarker, error) {
 	params := map[string]string{
 		"blobber":    node.Self.ID,
 		"client":     clientID,
 		"allocation": allocID,
 	}
 
-	latestRMBytes, err := transaction.MakeSCRestAPICall(
+	latestRMBytes, err := transaction.MakeSCRestAPICall(ctx,
 		transaction.STORAGE_CONTRACT_ADDRESS, "/latestreadmarker", params)
 
 	if err != nil {
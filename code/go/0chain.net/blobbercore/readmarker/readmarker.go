@@ -50,6 +50,9 @@ func (rm *ReadMarker) GetHashData() string {
 type ReadMarkerEntity struct {
 	LatestRM         *ReadMarker `gorm:"embedded" json:"latest_read_marker,omitempty"`
 	LatestRedeemedRC int64       `gorm:"latest_redeemed_rc" json:"latest_redeemed_rc"`
+	// DeferredSince is when the redemption of the marker was first deferred
+	// by the redeem policy, 0 when it is not deferred.
+	DeferredSince common.Timestamp `gorm:"column:deferred_since;not null;default:0" json:"deferred_since,omitempty"`
 	datastore.ModelWithTS
 }
 
@@ -129,7 +132,7 @@ func GetLatestReadMarkerEntity(ctx context.Context, clientID, allocID string) (*
 	rm := &ReadMarkerEntity{}
 	err := db.Take(rm, "client_id = ? AND allocation_id = ?", clientID, allocID).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		latestRM, err := GetLatestReadMarkerEntityFromChain(clientID, allocID)
+		latestRM, err := GetLatestReadMarkerEntityFromChain(ctx, clientID, allocID)
 		if err != nil {
 			return nil, err
 		}
@@ -189,7 +192,7 @@ func SaveLatestReadMarker(ctx context.Context, rm *ReadMarker, latestRedeemedRC
 // Sync read marker with 0chain to be sure its correct.
 func (rm *ReadMarkerEntity) Sync(ctx context.Context) error {
 	// update local read pools cache from sharders
-	rp, err := allocation.RequestReadPoolStat(rm.LatestRM.ClientID)
+	rp, err := allocation.RequestReadPoolStat(ctx, rm.LatestRM.ClientID)
 	if err != nil {
 		return common.NewErrorf("rme_sync", "can't get read pools from sharders: %v", err)
 	}
@@ -215,12 +218,15 @@ func (rme *ReadMarkerEntity) UpdateStatus(ctx context.Context, txOutput, redeemT
 
 	err = db.Model(rme).
 		Where("client_id=?", rme.LatestRM.ClientID).
-		Update("latest_redeemed_rc", rme.LatestRM.ReadCounter).Error
+		Updates(map[string]interface{}{
+			"latest_redeemed_rc": rme.LatestRM.ReadCounter,
+			"deferred_since":     0,
+		}).Error
 	if err != nil {
 		return common.NewError("rme_update_status", err.Error())
 	}
 
-	rp, err := allocation.RequestReadPoolStat(rme.LatestRM.ClientID)
+	rp, err := allocation.RequestReadPoolStat(ctx, rme.LatestRM.ClientID)
 	if err != nil {
 		return common.NewErrorf("rme_update_status", "can't get read pools from sharders: %v", err)
 	}
@@ -0,0 +1,114 @@
+package readmarker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+)
+
+// DownloadReceipt is the record, signed by the blobber, of blocks of a file
+// served to a client. ReadCounter is the counter of the latest read marker of
+// the client when the blocks were served.
+// swagger:model DownloadReceipt
+type DownloadReceipt struct {
+	ID           int64            `gorm:"column:id;primaryKey" json:"id"`
+	AllocationID string           `gorm:"column:allocation_id;size:64;not null" json:"allocation_id"`
+	BlobberID    string           `gorm:"column:blobber_id;size:64;not null" json:"blobber_id"`
+	ClientID     string           `gorm:"column:client_id;size:64;not null" json:"client_id"`
+	PathHash     string           `gorm:"column:path_hash;size:64;not null" json:"path_hash"`
+	StartBlock   int64            `gorm:"column:start_block;not null" json:"start_block"`
+	NumBlocks    int64            `gorm:"column:num_blocks;not null" json:"num_blocks"`
+	ReadCounter  int64            `gorm:"column:read_counter;not null" json:"read_counter"`
+	Timestamp    common.Timestamp `gorm:"column:timestamp;not null" json:"timestamp"`
+	Signature    string           `gorm:"column:signature;not null" json:"signature"`
+	CreatedAt    time.Time        `gorm:"column:created_at" json:"-"`
+}
+
+func (DownloadReceipt) TableName() string {
+	return "download_receipts"
+}
+
+func (dr *DownloadReceipt) GetHashData() string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v:%v:%v:%v", dr.AllocationID, dr.BlobberID,
+		dr.ClientID, dr.PathHash, dr.StartBlock, dr.NumBlocks, dr.ReadCounter, dr.Timestamp)
+}
+
+// Sign signs the receipt with the key of this blobber.
+func (dr *DownloadReceipt) Sign() (err error) {
+	dr.Signature, err = node.Self.Sign(encryption.Hash(dr.GetHashData()))
+	return
+}
+
+// Verify checks the signature of the receipt against the public key of the blobber.
+func (dr *DownloadReceipt) Verify(blobberPublicKey string) (bool, error) {
+	return encryption.Verify(blobberPublicKey, dr.Signature, encryption.Hash(dr.GetHashData()))
+}
+
+// IssueDownloadReceipt signs and stores the receipt of the blocks served to the client.
+func IssueDownloadReceipt(ctx context.Context, allocationID, clientID, pathHash string, startBlock, numBlocks int64) (*DownloadReceipt, error) {
+	db := datastore.GetStore().GetTransaction(ctx)
+
+	var readCounter int64
+	err := db.Model(&ReadMarkerEntity{}).Select("counter").
+		Where("client_id = ? AND allocation_id = ?", clientID, allocationID).
+		Scan(&readCounter).Error
+	if err != nil {
+		return nil, err
+	}
+
+	dr := &DownloadReceipt{
+		AllocationID: allocationID,
+		BlobberID:    node.Self.ID,
+		ClientID:     clientID,
+		PathHash:     pathHash,
+		StartBlock:   startBlock,
+		NumBlocks:    numBlocks,
+		ReadCounter:  readCounter,
+		Timestamp:    common.Now(),
+	}
+	if err := dr.Sign(); err != nil {
+		return nil, err
+	}
+
+	if err := db.Create(dr).Error; err != nil {
+		return nil, err
+	}
+	return dr, nil
+}
+
+// DownloadReceipts is a page of the receipts of a client along with the
+// total number of blocks in all the receipts of the client.
+// swagger:model DownloadReceipts
+type DownloadReceipts struct {
+	Receipts    []*DownloadReceipt `json:"receipts"`
+	TotalBlocks int64              `json:"total_blocks"`
+}
+
+// ListDownloadReceipts returns the receipts issued to the client for the allocation.
+func ListDownloadReceipts(ctx context.Context, allocationID, clientID string, limit common.Pagination) (*DownloadReceipts, error) {
+	db := datastore.GetStore().GetTransaction(ctx)
+	query := db.Model(&DownloadReceipt{}).Where("allocation_id = ? AND client_id = ?", allocationID, clientID)
+
+	result := &DownloadReceipts{Receipts: make([]*DownloadReceipt, 0)}
+	err := query.Select("COALESCE(SUM(num_blocks), 0)").Scan(&result.TotalBlocks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	order := "id asc"
+	if limit.IsDescending {
+		order = "id desc"
+	}
+	err = db.Where("allocation_id = ? AND client_id = ?", allocationID, clientID).
+		Order(order).Limit(limit.Limit).Offset(limit.Offset).
+		Find(&result.Receipts).Error
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
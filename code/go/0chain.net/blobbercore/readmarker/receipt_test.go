@@ -0,0 +1,39 @@
+package readmarker
+
+import (
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	coreConfig "github.com/0chain/blobber/code/go/0chain.net/core/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadReceipt_Sign(t *testing.T) {
+	coreConfig.Configuration.SignatureScheme = "bls0chain"
+	wallet, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	node.Self.SetKeys(wallet.Keys[0].PublicKey, wallet.Keys[0].PrivateKey)
+
+	dr := &DownloadReceipt{
+		AllocationID: "alloc",
+		BlobberID:    node.Self.ID,
+		ClientID:     "client",
+		PathHash:     "path_hash",
+		StartBlock:   0,
+		NumBlocks:    10,
+		ReadCounter:  42,
+		Timestamp:    common.Now(),
+	}
+	require.NoError(t, dr.Sign())
+
+	ok, err := dr.Verify(node.Self.PublicKey)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// a receipt for more blocks than served doesn't verify
+	dr.NumBlocks = 20
+	ok, _ = dr.Verify(node.Self.PublicKey)
+	require.False(t, ok)
+}
@@ -17,11 +17,25 @@ import (
 )
 
 func SetupWorkers(ctx context.Context) {
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		if err := LoadRevokedAuthTickets(ctx); err != nil {
+			return err
+		}
+		return LoadRevokedPresignedURLs(ctx)
+	})
+	if err != nil {
+		logging.Logger.Error("loading revoked auth tickets and pre-signed urls", zap.Error(err))
+	}
+
 	go startRedeemMarkers(ctx)
 }
 
 func redeemReadMarker(ctx context.Context, rmEntity *ReadMarkerEntity) (err error) {
 	logging.Logger.Info("Redeeming the read marker", zap.Any("rm", rmEntity.LatestRM))
+	result := redeemError
+	defer func() {
+		redemptions.WithLabelValues(result).Inc()
+	}()
 
 	params := map[string]string{
 		"blobber":    rmEntity.LatestRM.BlobberID,
@@ -30,7 +44,7 @@ func redeemReadMarker(ctx context.Context, rmEntity *ReadMarkerEntity) (err erro
 	}
 
 	latestRM := ReadMarker{BlobberID: rmEntity.LatestRM.BlobberID, ClientID: rmEntity.LatestRM.ClientID}
-	latestRMBytes, err := transaction.MakeSCRestAPICall(
+	latestRMBytes, err := transaction.MakeSCRestAPICall(ctx,
 		transaction.STORAGE_CONTRACT_ADDRESS, "/latestreadmarker", params)
 
 	if err != nil {
@@ -53,12 +67,16 @@ func redeemReadMarker(ctx context.Context, rmEntity *ReadMarkerEntity) (err erro
 		if err = SaveLatestReadMarker(ctx, &latestRM, latestRM.ReadCounter, false); err != nil {
 			return
 		}
+		if err = resetDeferral(ctx, rmEntity); err != nil {
+			return
+		}
 
 		rmEntity.LatestRM = &latestRM
 		if err = rmEntity.Sync(ctx); err != nil {
 			logging.Logger.Error("redeem RM loop -- error syncing RM state", zap.Error(err))
 			return
 		}
+		result = redeemSynced
 		return // synced from blockchain, no redeeming needed
 	}
 
@@ -66,8 +84,10 @@ func redeemReadMarker(ctx context.Context, rmEntity *ReadMarkerEntity) (err erro
 
 	if err = rmEntity.RedeemReadMarker(ctx); err != nil {
 		logging.Logger.Error("error redeeming the read marker.", zap.Any("rm", rmEntity), zap.Error(err))
+		result = redeemFailed
 		return
 	}
+	result = redeemSuccess
 
 	logging.Logger.Info("successfully redeemed read marker", zap.Any("rm", rmEntity.LatestRM))
 	return
@@ -80,11 +100,21 @@ func redeemReadMarkers(ctx context.Context) {
 		}
 	}()
 
+	var fee float64
+	var feeErr error
+	if currentRedeemPolicy().Enabled {
+		fee, feeErr = estimateRedeemFee(ctx)
+	}
+
 	var readMarkers []*ReadMarkerEntity
 	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
 		var err error
 		readMarkers, err = GetRedeemRequiringRMEntities(ctx)
-		return err
+		if err != nil {
+			return err
+		}
+		readMarkers = filterRedeemable(ctx, readMarkers, fee, feeErr)
+		return nil
 	})
 
 	if err != nil {
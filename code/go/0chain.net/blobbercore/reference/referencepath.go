@@ -397,3 +397,15 @@ func CountRefs(ctx context.Context, allocationID string) (int64, error) {
 
 	return totalRows, err
 }
+
+// CountChildren returns the number of objects in the directory at parentPath.
+func CountChildren(ctx context.Context, allocationID, parentPath string) (int64, error) {
+	var totalRows int64
+	tx := datastore.GetStore().GetTransaction(ctx)
+
+	err := tx.Model(&Ref{}).
+		Where("allocation_id = ? AND parent_path = ?", allocationID, parentPath).
+		Count(&totalRows).Error
+
+	return totalRows, err
+}
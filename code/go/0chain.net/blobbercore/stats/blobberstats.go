@@ -10,6 +10,8 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/filestore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/ratelimit"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/readmarker"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
@@ -23,6 +25,10 @@ const DateTimeFormat = "2006-01-02T15:04:05"
 type ReadMarkersStat struct {
 	Redeemed int64 `gorm:"redeemed" json:"redeemed"`
 	Pending  int64 `gorm:"pending" json:"pending"`
+	// Deferred is the part of pending blocks that is held back by the
+	// fee-aware redeem policy, DeferredValue is its value in SAS.
+	Deferred      int64   `gorm:"-" json:"deferred"`
+	DeferredValue float64 `gorm:"-" json:"deferred_value"`
 }
 
 type WriteMarkers struct {
@@ -81,6 +87,11 @@ type BlobberStats struct {
 	// total for all allocations
 	ReadMarkers  ReadMarkersStat  `json:"read_markers"`
 	WriteMarkers WriteMarkersStat `json:"write_markers"`
+
+	ChallengeFailures *ChallengeFailureStats `json:"challenge_failures,omitempty"`
+	// RateLimited are the requests rejected and the transfers slowed down by
+	// the limits of their allocation.
+	RateLimited []*ratelimit.Counters `json:"rate_limited,omitempty"`
 }
 
 var fs *BlobberStats
@@ -143,6 +154,8 @@ func (bs *BlobberStats) loadDetailedStats(ctx context.Context) {
 	bs.loadAllocationStats(ctx)
 	bs.loadChallengeStats(ctx)
 	bs.loadAllocationChallengeStats(ctx)
+	bs.loadChallengeFailureStats(ctx)
+	bs.RateLimited = ratelimit.GetCounters()
 
 	// load read/write markers stat
 	var (
@@ -165,6 +178,8 @@ func (bs *BlobberStats) loadDetailedStats(ctx context.Context) {
 		} else {
 			bs.ReadMarkers.Pending += as.ReadMarkers.Pending
 			bs.ReadMarkers.Redeemed += as.ReadMarkers.Redeemed
+			bs.ReadMarkers.Deferred += as.ReadMarkers.Deferred
+			bs.ReadMarkers.DeferredValue += as.ReadMarkers.DeferredValue
 		}
 
 		as.WriteMarkers, err = loadAllocWriteMarkerStat(ctx, as.AllocationID)
@@ -183,6 +198,15 @@ func (bs *BlobberStats) loadDetailedStats(ctx context.Context) {
 	}
 }
 
+func (bs *BlobberStats) loadChallengeFailureStats(ctx context.Context) {
+	failures, err := loadChallengeFailureStats(ctx)
+	if err != nil {
+		Logger.Error("Error in getting the challenge failure stats", zap.Error(err))
+		return
+	}
+	bs.ChallengeFailures = failures
+}
+
 func (bs *BlobberStats) loadInfraStats(ctx context.Context) {
 	healthIn := ctx.Value(HealthDataKey)
 	if healthIn == nil {
@@ -506,6 +530,10 @@ func loadAllocReadMarkersStat(ctx context.Context, allocationID string) (*ReadMa
 	}
 
 	rms.Redeemed = rme.LatestRedeemedRC // already redeemed
+
+	deferred := readmarker.GetDeferredStat(allocationID)
+	rms.Deferred = deferred.Blocks
+	rms.DeferredValue = deferred.Value
 	return rms, nil
 }
 
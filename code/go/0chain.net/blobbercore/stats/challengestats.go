@@ -2,7 +2,9 @@ package stats
 
 import (
 	"context"
+	"sort"
 
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/challenge"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
 	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
@@ -51,3 +53,68 @@ func getAllFailedChallenges(ctx context.Context, offset, limit int) ([]Challenge
 
 	return crs, int(count), nil
 }
+
+// CategoryCount is the number of challenges failed for a root cause.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int64  `json:"count"`
+}
+
+// AllocationFailureStats are the challenge failures of an allocation by root cause.
+type AllocationFailureStats struct {
+	AllocationID string           `json:"allocation_id"`
+	Total        int64            `json:"total"`
+	Categories   map[string]int64 `json:"categories"`
+}
+
+// ChallengeFailureStats are the failed and cancelled challenges by root cause,
+// over the retention of the challenge diagnostics.
+type ChallengeFailureStats struct {
+	Total       int64                     `json:"total"`
+	Categories  []CategoryCount           `json:"categories"`
+	Allocations []*AllocationFailureStats `json:"allocations"`
+}
+
+func loadChallengeFailureStats(ctx context.Context) (*ChallengeFailureStats, error) {
+	var rows []struct {
+		AllocationID string
+		Category     string
+		Count        int64
+	}
+	db := datastore.GetStore().GetTransaction(ctx)
+	err := db.Table(challenge.FailureDiagnosis{}.TableName()).
+		Select("allocation_id, category, COUNT(*) AS count").
+		Group("allocation_id, category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ChallengeFailureStats{}
+	byCategory := make(map[string]int64)
+	byAllocation := make(map[string]*AllocationFailureStats)
+	for _, r := range rows {
+		s.Total += r.Count
+		byCategory[r.Category] += r.Count
+		as, ok := byAllocation[r.AllocationID]
+		if !ok {
+			as = &AllocationFailureStats{AllocationID: r.AllocationID, Categories: make(map[string]int64)}
+			byAllocation[r.AllocationID] = as
+			s.Allocations = append(s.Allocations, as)
+		}
+		as.Total += r.Count
+		as.Categories[r.Category] += r.Count
+	}
+
+	for _, category := range challenge.FailureCategories {
+		s.Categories = append(s.Categories, CategoryCount{Category: category, Count: byCategory[category]})
+	}
+	// the allocations failing the most first
+	sort.Slice(s.Allocations, func(i, j int) bool {
+		if s.Allocations[i].Total != s.Allocations[j].Total {
+			return s.Allocations[i].Total > s.Allocations[j].Total
+		}
+		return s.Allocations[i].AllocationID < s.Allocations[j].AllocationID
+	})
+	return s, nil
+}
@@ -149,6 +149,11 @@ const tpl = `
                     <td>{{ .ReadMarkers.Redeemed }} <i>(64 KB blocks)</i></td>
                     <td>{{ read_size .ReadMarkers.Redeemed }}</td>
                 </tr>
+                <tr>
+                    <td>Deferred</td>
+                    <td>{{ .ReadMarkers.Deferred }} <i>(64 KB blocks)</i></td>
+                    <td>{{ .ReadMarkers.DeferredValue }} <i>(SAS)</i></td>
+                </tr>
 
                 <tr><td colspan='3'></td></tr>
                 <tr><td colspan='3'>Write Markers</td></tr>
@@ -297,6 +302,11 @@ const tpl = `
                     <td>{{ .ReadMarkers.Redeemed }} <i>(64 KB blocks)</i></td>
                     <td>{{ read_size .ReadMarkers.Redeemed }}</td>
                 </tr>
+                <tr>
+                    <td>Deferred</td>
+                    <td>{{ .ReadMarkers.Deferred }} <i>(64 KB blocks)</i></td>
+                    <td>{{ .ReadMarkers.DeferredValue }} <i>(SAS)</i></td>
+                </tr>
                 {{ else }}
                 <tr><th>No read markers yet.</th></tr>
                 {{ end }}
@@ -354,6 +364,67 @@ const tpl = `
 
 <br>
 
+{{if .ChallengeFailures}}
+<h1>
+    Challenge Failure Causes
+</h1>
+
+<table style='border-collapse: collapse;'>
+	<tr class='header'>
+		<td>Allocation ID</td>
+		{{range .ChallengeFailures.Categories}}
+		<td>{{ .Category }}</td>
+		{{end}}
+		<td>Total</td>
+	</tr>
+	<tr>
+		<td>All</td>
+		{{range .ChallengeFailures.Categories}}
+		<td>{{ .Count }}</td>
+		{{end}}
+		<td>{{ .ChallengeFailures.Total }}</td>
+	</tr>
+	{{$categories := .ChallengeFailures.Categories}}
+	{{range .ChallengeFailures.Allocations}}
+	{{$counts := .Categories}}
+	<tr>
+		<td>{{ .AllocationID }}</td>
+		{{range $categories}}
+		<td>{{ index $counts .Category }}</td>
+		{{end}}
+		<td>{{ .Total }}</td>
+	</tr>
+	{{end}}
+</table>
+
+<br>
+{{end}}
+
+{{if .RateLimited}}
+<h1>
+    Allocation Rate Limits
+</h1>
+
+<table style='border-collapse: collapse;'>
+	<tr class='header'>
+		<td>Allocation ID</td>
+		<td>Rejected Requests</td>
+		<td>Throttled Uploads</td>
+		<td>Throttled Downloads</td>
+	</tr>
+	{{range .RateLimited}}
+	<tr>
+		<td>{{ .AllocationID }}</td>
+		<td>{{ .RejectedRequests }}</td>
+		<td>{{ .ThrottledUploads }}</td>
+		<td>{{ .ThrottledDownloads }}</td>
+	</tr>
+	{{end}}
+</table>
+
+<br>
+{{end}}
+
 <h1>
     Failed Challenges
 </h1>
@@ -0,0 +1,460 @@
+package writemarker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/lock"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Filters for listing write markers waiting for redemption.
+const (
+	MarkerFilterAll     = ""
+	MarkerFilterPending = "pending"
+	MarkerFilterFailed  = "failed"
+)
+
+func (s WriteMarkerStatus) String() string {
+	switch s {
+	case Accepted:
+		return "accepted"
+	case Committed:
+		return "committed"
+	case Failed:
+		return "failed"
+	case Rollbacked:
+		return "rollbacked"
+	}
+	return "unknown"
+}
+
+// MarkerInfo is the operator view of a write marker waiting for redemption.
+type MarkerInfo struct {
+	Sequence               int64            `json:"sequence"`
+	AllocationRoot         string           `json:"allocation_root"`
+	PreviousAllocationRoot string           `json:"prev_allocation_root"`
+	Size                   int64            `json:"size"`
+	ChainLength            int              `json:"chain_length"`
+	Timestamp              common.Timestamp `json:"timestamp"`
+	Status                 string           `json:"status"`
+	StatusMessage          string           `json:"status_message"`
+	RedeemRetries          int64            `json:"redeem_retries"`
+	CloseTxnID             string           `json:"close_txn_id"`
+}
+
+// RedeemState is the write marker redemption state of an allocation.
+type RedeemState struct {
+	AllocationID     string        `json:"allocation_id"`
+	AllocationRoot   string        `json:"allocation_root"`
+	LatestRedeemedWM string        `json:"latest_redeemed_write_marker"`
+	LastRedeemedSeq  int64         `json:"last_redeemed_sequence"`
+	IsRedeemRequired bool          `json:"is_redeem_required"`
+	RedeemPaused     bool          `json:"redeem_paused"`
+	Processing       bool          `json:"processing"`
+	Retries          int           `json:"retries"`
+	Markers          []*MarkerInfo `json:"markers"`
+	// Lock is the write marker lock of the allocation and its queue.
+	Lock *LockState `json:"lock"`
+}
+
+// ResyncResult reports the change made by re-syncing the last redeemed sequence from chain.
+type ResyncResult struct {
+	AllocationID        string `json:"allocation_id"`
+	ChainAllocationRoot string `json:"chain_allocation_root"`
+	PreviousSeq         int64  `json:"previous_sequence"`
+	LastRedeemedSeq     int64  `json:"last_redeemed_sequence"`
+	IsRedeemRequired    bool   `json:"is_redeem_required"`
+}
+
+func toMarkerInfo(wme *WriteMarkerEntity) *MarkerInfo {
+	return &MarkerInfo{
+		Sequence:               wme.Sequence,
+		AllocationRoot:         wme.WM.AllocationRoot,
+		PreviousAllocationRoot: wme.WM.PreviousAllocationRoot,
+		Size:                   wme.WM.Size,
+		ChainLength:            wme.WM.ChainLength,
+		Timestamp:              wme.WM.Timestamp,
+		Status:                 wme.Status.String(),
+		StatusMessage:          wme.StatusMessage,
+		RedeemRetries:          wme.ReedeemRetries,
+		CloseTxnID:             wme.CloseTxnID,
+	}
+}
+
+// GetRedeemState returns the redemption state of the allocation with the write
+// markers matching the filter. Pending markers are the accepted markers after
+// the last redeemed sequence.
+func GetRedeemState(ctx context.Context, allocationID, filter string, limit common.Pagination) (*RedeemState, error) {
+	alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+	if err != nil {
+		return nil, common.NewErrorf("redeem_state", "could not get allocation %s: %v", allocationID, err)
+	}
+
+	db := datastore.GetStore().GetTransaction(ctx)
+	query := db.Table((WriteMarkerEntity{}).TableName()).Where("allocation_id=?", allocationID)
+	switch filter {
+	case MarkerFilterPending:
+		query = query.Where("status=? AND sequence > ?", Accepted, alloc.LastRedeemedSeq)
+	case MarkerFilterFailed:
+		query = query.Where("status=?", Failed)
+	case MarkerFilterAll:
+		query = query.Where("(status=? AND sequence > ?) OR status=?", Accepted, alloc.LastRedeemedSeq, Failed)
+	default:
+		return nil, common.NewErrorf("invalid_parameters", "unknown marker filter %q", filter)
+	}
+
+	order := "sequence asc"
+	if limit.IsDescending {
+		order = "sequence desc"
+	}
+	markers := make([]*WriteMarkerEntity, 0)
+	err = query.Order(order).Offset(limit.Offset).Limit(limit.Limit).Find(&markers).Error
+	if err != nil {
+		return nil, common.NewErrorf("redeem_state", "could not get write markers: %v", err)
+	}
+
+	state := &RedeemState{
+		AllocationID:     alloc.ID,
+		AllocationRoot:   alloc.AllocationRoot,
+		LatestRedeemedWM: alloc.LatestRedeemedWM,
+		LastRedeemedSeq:  alloc.LastRedeemedSeq,
+		IsRedeemRequired: alloc.IsRedeemRequired,
+		RedeemPaused:     alloc.RedeemPaused,
+		Markers:          make([]*MarkerInfo, 0, len(markers)),
+		Lock:             GetLockState(allocationID),
+	}
+	markerDataMut.Lock()
+	if md, ok := markerDataMap[allocationID]; ok {
+		state.Processing = md.processing
+		state.Retries = md.retries
+	}
+	markerDataMut.Unlock()
+
+	for _, wme := range markers {
+		state.Markers = append(state.Markers, toMarkerInfo(wme))
+	}
+	return state, nil
+}
+
+// claimMarkerData marks the allocation as processing so the redeem worker
+// leaves it alone. It fails if the worker is redeeming it right now. The
+// returned value tells whether the marker data was created by the claim.
+func claimMarkerData(allocationID string) (bool, error) {
+	markerDataMut.Lock()
+	defer markerDataMut.Unlock()
+
+	md, ok := markerDataMap[allocationID]
+	if !ok {
+		md = &markerData{allocationID: allocationID}
+		markerDataMap[allocationID] = md
+	} else if md.processing {
+		return false, common.NewError("redeem_in_progress", "write markers of the allocation are being redeemed, try again later")
+	}
+	md.processing = true
+	return !ok, nil
+}
+
+func releaseMarkerData(allocationID string, created bool) {
+	markerDataMut.Lock()
+	defer markerDataMut.Unlock()
+
+	if created {
+		delete(markerDataMap, allocationID)
+	} else if md, ok := markerDataMap[allocationID]; ok {
+		md.processing = false
+	}
+}
+
+// scheduleRedeem queues the allocation for the redeem worker if its latest
+// write marker still has to be redeemed.
+func scheduleRedeem(allocationID string) error {
+	if writeMarkerChan == nil {
+		return nil // workers are not running
+	}
+
+	var wm *WriteMarkerEntity
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+		if err != nil {
+			return err
+		}
+		if alloc.RedeemPaused || alloc.Finalized {
+			return nil
+		}
+		latest := &WriteMarkerEntity{}
+		err = datastore.GetStore().GetTransaction(ctx).
+			Where("allocation_id = ? AND sequence > ?", allocationID, alloc.LastRedeemedSeq).
+			Order("sequence desc").
+			Take(latest).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if latest.Status != Committed {
+			wm = latest
+		}
+		return nil
+	})
+	if err != nil || wm == nil {
+		return err
+	}
+
+	markerDataMut.Lock()
+	defer markerDataMut.Unlock()
+	md, ok := markerDataMap[allocationID]
+	if ok && md.processing {
+		return nil
+	}
+	md = &markerData{
+		firstMarkerTimestamp: wm.WM.Timestamp,
+		lastMarkerTimestamp:  wm.WM.Timestamp,
+		allocationID:         allocationID,
+		chainLength:          wm.WM.ChainLength,
+		processing:           true,
+	}
+	markerDataMap[allocationID] = md
+	writeMarkerChan <- md
+	return nil
+}
+
+// SetRedeemPaused pauses or resumes write marker redemption of the allocation.
+// A resumed allocation is queued for redemption right away.
+func SetRedeemPaused(allocationID string, paused bool) error {
+	err := datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+		if err != nil {
+			return common.NewErrorf("redeem_pause", "could not get allocation %s: %v", allocationID, err)
+		}
+		return allocation.Repo.UpdateAllocation(ctx, alloc, map[string]interface{}{
+			"redeem_paused": paused,
+		}, func(a *allocation.Allocation) {
+			a.RedeemPaused = paused
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Logger.Info("write marker redemption paused by admin",
+		zap.String("allocation_id", allocationID), zap.Bool("paused", paused))
+	if paused {
+		return nil
+	}
+	return scheduleRedeem(allocationID)
+}
+
+// ForceRedeem redeems the write markers of the allocation from startSeq to
+// endSeq in a single close connection transaction, bypassing the redeem
+// schedule. A zero startSeq starts after the last redeemed marker and a zero
+// endSeq redeems up to the latest marker.
+func ForceRedeem(allocationID string, startSeq, endSeq int64) (*MarkerInfo, error) {
+	created, err := claimMarkerData(allocationID)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseMarkerData(allocationID, created)
+
+	// the lock of the allocation only covers the choice of the markers, its
+	// commits and rollbacks go on while the redemption waits on the chain
+	allocMu := lock.GetMutex(allocation.Allocation{}.TableName(), allocationID)
+	allocMu.RLock()
+	var wm *WriteMarkerEntity
+	err = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		wm, startSeq, err = getForceRedeemMarker(ctx, allocationID, startSeq, endSeq)
+		return err
+	})
+	allocMu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	err = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		return wm.RedeemMarker(ctx, startSeq)
+	})
+	if err != nil {
+		redemptions.WithLabelValues(redeemFailed).Inc()
+		return nil, common.NewErrorf("force_redeem", "redeeming write markers: %v", err)
+	}
+	redemptions.WithLabelValues(redeemSuccess).Inc()
+
+	err = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+		if err != nil {
+			return err
+		}
+		if wm.Sequence <= alloc.LastRedeemedSeq {
+			return nil
+		}
+		return allocation.Repo.UpdateAllocationRedeem(ctx, allocationID, wm.WM.AllocationRoot, alloc, wm.Sequence)
+	})
+	if err != nil {
+		return nil, common.NewErrorf("force_redeem", "updating allocation: %v", err)
+	}
+
+	logging.Logger.Info("write markers redeemed by admin",
+		zap.String("allocation_id", allocationID),
+		zap.Int64("start_seq", startSeq),
+		zap.Int64("end_seq", wm.Sequence),
+		zap.String("txn", wm.CloseTxnID))
+	return toMarkerInfo(wm), nil
+}
+
+// getForceRedeemMarker returns the end write marker of a forced redemption
+// and its start sequence.
+func getForceRedeemMarker(ctx context.Context, allocationID string, startSeq, endSeq int64) (*WriteMarkerEntity, int64, error) {
+	alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+	if err != nil {
+		return nil, 0, common.NewErrorf("force_redeem", "could not get allocation %s: %v", allocationID, err)
+	}
+	if alloc.Finalized {
+		return nil, 0, common.NewError("force_redeem", "allocation is finalized")
+	}
+
+	if startSeq <= 0 {
+		startSeq = alloc.LastRedeemedSeq + 1
+	}
+
+	db := datastore.GetStore().GetTransaction(ctx)
+	wm := &WriteMarkerEntity{}
+	query := db.Table((WriteMarkerEntity{}).TableName()).Where("allocation_id=?", allocationID)
+	if endSeq > 0 {
+		query = query.Where("sequence=?", endSeq)
+	}
+	err = query.Order("sequence desc").Take(wm).Error
+	if err != nil {
+		return nil, 0, common.NewErrorf("force_redeem", "could not get end write marker: %v", err)
+	}
+
+	if startSeq > wm.Sequence {
+		return nil, 0, common.NewErrorf("invalid_parameters", "start sequence %d is after end sequence %d", startSeq, wm.Sequence)
+	}
+	if wm.Status == Rollbacked {
+		return nil, 0, common.NewError("force_redeem", "end write marker was rolled back")
+	}
+	if wm.WM.ChainHash != "" {
+		wm.WM.Version = MARKER_VERSION
+	}
+	return wm, startSeq, nil
+}
+
+// ResyncRedeemedSeq sets the last redeemed write marker of the allocation to
+// the allocation root the blockchain has for this blobber. Markers up to it are
+// marked committed and later ones are queued for redemption again.
+func ResyncRedeemedSeq(ctx context.Context, allocationID string) (*ResyncResult, error) {
+	chainRoot, err := getChainAllocationRoot(ctx, allocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := claimMarkerData(allocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResyncResult{
+		AllocationID:        allocationID,
+		ChainAllocationRoot: chainRoot,
+	}
+	err = datastore.GetStore().WithNewTransaction(func(ctx context.Context) error {
+		return resyncRedeemedSeq(ctx, result)
+	})
+	releaseMarkerData(allocationID, created)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Logger.Info("last redeemed sequence re-synced from chain",
+		zap.String("allocation_id", allocationID),
+		zap.Int64("previous_seq", result.PreviousSeq),
+		zap.Int64("last_redeemed_seq", result.LastRedeemedSeq))
+	if result.IsRedeemRequired {
+		if err := scheduleRedeem(allocationID); err != nil {
+			logging.Logger.Error("scheduling redeem after re-sync", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+func resyncRedeemedSeq(ctx context.Context, result *ResyncResult) error {
+	allocationID := result.AllocationID
+	alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+	if err != nil {
+		return common.NewErrorf("resync_redeemed_seq", "could not get allocation %s: %v", allocationID, err)
+	}
+	result.PreviousSeq = alloc.LastRedeemedSeq
+
+	var redeemedSeq int64
+	if result.ChainAllocationRoot != "" {
+		wm, err := GetWriteMarkerEntity(ctx, result.ChainAllocationRoot)
+		if err != nil {
+			return common.NewErrorf("resync_redeemed_seq", "no write marker for chain allocation root %s: %v",
+				result.ChainAllocationRoot, err)
+		}
+		redeemedSeq = wm.Sequence
+	}
+	result.LastRedeemedSeq = redeemedSeq
+
+	db := datastore.GetStore().GetTransaction(ctx)
+	err = db.Exec("UPDATE write_markers SET status=? WHERE allocation_id=? AND sequence <= ? AND status<>?",
+		Committed, allocationID, redeemedSeq, Rollbacked).Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec("UPDATE write_markers SET status=? WHERE allocation_id=? AND sequence > ? AND status=?",
+		Accepted, allocationID, redeemedSeq, Committed).Error
+	if err != nil {
+		return err
+	}
+
+	err = allocation.Repo.UpdateAllocationRedeem(ctx, allocationID, result.ChainAllocationRoot, alloc, redeemedSeq)
+	if err != nil {
+		return err
+	}
+
+	var pending int64
+	err = db.Table((WriteMarkerEntity{}).TableName()).
+		Where("allocation_id=? AND sequence > ?", allocationID, redeemedSeq).
+		Count(&pending).Error
+	if err != nil {
+		return err
+	}
+	result.IsRedeemRequired = pending > 0
+	if !result.IsRedeemRequired {
+		return nil
+	}
+	return allocation.Repo.UpdateAllocation(ctx, alloc, map[string]interface{}{
+		"is_redeem_required": true,
+	}, func(a *allocation.Allocation) {
+		a.IsRedeemRequired = true
+	})
+}
+
+func getChainAllocationRoot(ctx context.Context, allocationID string) (string, error) {
+	resp, err := transaction.MakeSCRestAPICall(ctx, transaction.STORAGE_CONTRACT_ADDRESS, "/allocation",
+		map[string]string{"allocation": allocationID})
+	if err != nil {
+		return "", common.NewErrorf("resync_redeemed_seq", "requesting allocation from chain: %v", err)
+	}
+
+	sa := &transaction.StorageAllocation{}
+	if err = json.Unmarshal(resp, sa); err != nil {
+		return "", common.NewErrorf("resync_redeemed_seq", "decoding allocation: %v", err)
+	}
+
+	for _, d := range sa.BlobberDetails {
+		if d.BlobberID == node.Self.ID {
+			return d.AllocationRoot, nil
+		}
+	}
+	return "", common.NewError("resync_redeemed_seq", "blobber is not part of the allocation")
+}
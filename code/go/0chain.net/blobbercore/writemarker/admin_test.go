@@ -0,0 +1,45 @@
+package writemarker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimMarkerData(t *testing.T) {
+	const allocationID = "claim_allocation"
+	defer deleteMarkerData(allocationID)
+
+	created, err := claimMarkerData(allocationID)
+	require.NoError(t, err)
+	require.True(t, created)
+	require.True(t, CheckProcessingMarker(allocationID))
+
+	// the worker or another admin call can't take it while it's claimed
+	_, err = claimMarkerData(allocationID)
+	require.Error(t, err)
+
+	releaseMarkerData(allocationID, created)
+	require.False(t, CheckProcessingMarker(allocationID))
+	markerDataMut.Lock()
+	_, ok := markerDataMap[allocationID]
+	markerDataMut.Unlock()
+	require.False(t, ok, "marker data created by the claim must be removed")
+
+	// existing marker data is kept and only released
+	markerDataMut.Lock()
+	markerDataMap[allocationID] = &markerData{allocationID: allocationID, chainLength: 3}
+	markerDataMut.Unlock()
+
+	created, err = claimMarkerData(allocationID)
+	require.NoError(t, err)
+	require.False(t, created)
+	releaseMarkerData(allocationID, created)
+
+	markerDataMut.Lock()
+	md, ok := markerDataMap[allocationID]
+	markerDataMut.Unlock()
+	require.True(t, ok)
+	require.False(t, md.processing)
+	require.Equal(t, 3, md.chainLength)
+}
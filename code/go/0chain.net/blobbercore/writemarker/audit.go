@@ -0,0 +1,236 @@
+package writemarker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/allocation"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/datastore"
+	"github.com/0chain/blobber/code/go/0chain.net/blobbercore/reference"
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"gorm.io/gorm"
+)
+
+// Kinds of breaks reported by the write marker chain audit.
+const (
+	AuditBreakChainHash      = "chain_hash_mismatch"
+	AuditBreakChainSize      = "chain_size_mismatch"
+	AuditBreakChainLength    = "chain_length_mismatch"
+	AuditBreakPrevRoot       = "prev_allocation_root_mismatch"
+	AuditBreakRollbackRoot   = "rollback_root_mismatch"
+	AuditBreakTimestamp      = "timestamp_out_of_order"
+	AuditBreakSignature      = "invalid_signature"
+	AuditBreakAllocationRoot = "allocation_root_mismatch"
+	AuditBreakRefRoot        = "ref_root_mismatch"
+	AuditBreakFileMetaRoot   = "file_meta_root_mismatch"
+)
+
+// AuditBreak is a single inconsistency found while walking the write marker chain.
+type AuditBreak struct {
+	Kind           string `json:"kind"`
+	Sequence       int64  `json:"sequence,omitempty"`
+	AllocationRoot string `json:"allocation_root,omitempty"`
+	Expected       string `json:"expected,omitempty"`
+	Actual         string `json:"actual,omitempty"`
+	Message        string `json:"message"`
+}
+
+// AuditReport is the machine readable result of a write marker chain audit.
+type AuditReport struct {
+	AllocationID       string        `json:"allocation_id"`
+	MarkersChecked     int           `json:"markers_checked"`
+	FirstSequence      int64         `json:"first_sequence"`
+	LastSequence       int64         `json:"last_sequence"`
+	LatestMarkerRoot   string        `json:"latest_marker_root"`
+	AllocationRoot     string        `json:"allocation_root"`
+	RefTreeRoot        string        `json:"ref_tree_root"`
+	LastRedeemedSeq    int64         `json:"last_redeemed_sequence"`
+	LatestRedeemedRoot string        `json:"latest_redeemed_root"`
+	Breaks             []*AuditBreak `json:"breaks"`
+	OK                 bool          `json:"ok"`
+}
+
+func (r *AuditReport) addBreak(b *AuditBreak) {
+	r.Breaks = append(r.Breaks, b)
+}
+
+// AuditChain walks the stored write marker chain of the allocation by sequence and
+// re-verifies chain hashes, chain sizes, signatures and the links between roots.
+// The latest marker is then compared with the allocation record and the ref tree.
+// It only reads from the database and never changes anything.
+func AuditChain(ctx context.Context, allocationID string) (*AuditReport, error) {
+	db := datastore.GetStore().GetTransaction(ctx)
+
+	alloc, err := allocation.Repo.GetAllocationFromDB(ctx, allocationID)
+	if err != nil {
+		return nil, common.NewErrorf("audit_wm", "could not get allocation %s: %v", allocationID, err)
+	}
+
+	markers := make([]*WriteMarkerEntity, 0)
+	err = db.Table((WriteMarkerEntity{}).TableName()).
+		Where("allocation_id=?", allocationID).
+		Order("sequence asc").
+		Find(&markers).Error
+	if err != nil {
+		return nil, common.NewErrorf("audit_wm", "could not get write markers: %v", err)
+	}
+
+	report := AuditMarkers(allocationID, markers)
+	report.AllocationRoot = alloc.AllocationRoot
+	report.LastRedeemedSeq = alloc.LastRedeemedSeq
+	report.LatestRedeemedRoot = alloc.LatestRedeemedWM
+
+	rootRef, err := reference.GetLimitedRefFieldsByPath(ctx, allocationID, "/", []string{"hash", "file_meta_hash"})
+	if err == gorm.ErrRecordNotFound {
+		rootRef = &reference.Ref{}
+	} else if err != nil {
+		return nil, common.NewErrorf("audit_wm", "could not get root ref: %v", err)
+	}
+	report.RefTreeRoot = rootRef.Hash
+
+	var latest *WriteMarkerEntity
+	if len(markers) > 0 {
+		latest = markers[len(markers)-1]
+	}
+	checkLatestRoots(report, latest, alloc, rootRef)
+
+	report.OK = len(report.Breaks) == 0
+	return report, nil
+}
+
+// AuditMarkers verifies a chain of write markers that is already ordered by sequence.
+func AuditMarkers(allocationID string, markers []*WriteMarkerEntity) *AuditReport {
+	report := &AuditReport{
+		AllocationID: allocationID,
+		Breaks:       make([]*AuditBreak, 0),
+	}
+
+	var prev *WriteMarkerEntity
+	for _, wme := range markers {
+		checkMarker(report, prev, wme)
+		prev = wme
+	}
+
+	report.MarkersChecked = len(markers)
+	if len(markers) > 0 {
+		report.FirstSequence = markers[0].Sequence
+		report.LastSequence = prev.Sequence
+		report.LatestMarkerRoot = prev.WM.AllocationRoot
+	}
+	report.OK = len(report.Breaks) == 0
+	return report
+}
+
+func checkMarker(report *AuditReport, prev, wme *WriteMarkerEntity) {
+	wm := &wme.WM
+	newBreak := func(kind, expected, actual, msg string) {
+		report.addBreak(&AuditBreak{
+			Kind:           kind,
+			Sequence:       wme.Sequence,
+			AllocationRoot: wm.AllocationRoot,
+			Expected:       expected,
+			Actual:         actual,
+			Message:        msg,
+		})
+	}
+
+	var (
+		prevRoot      string
+		prevPrevRoot  string
+		prevChainHash string
+		prevChainSize int64
+	)
+	if prev != nil {
+		prevRoot = prev.WM.AllocationRoot
+		prevPrevRoot = prev.WM.PreviousAllocationRoot
+		prevChainHash = prev.WM.ChainHash
+		prevChainSize = prev.WM.ChainSize
+	}
+
+	isRollback := prev != nil && wm.AllocationRoot == wm.PreviousAllocationRoot
+	if isRollback {
+		if wm.AllocationRoot != prevPrevRoot {
+			newBreak(AuditBreakRollbackRoot, prevPrevRoot, wm.AllocationRoot,
+				"Rollback marker root does not match the previous allocation root of the marker it reverts")
+		}
+	} else if wm.PreviousAllocationRoot != prevRoot {
+		newBreak(AuditBreakPrevRoot, prevRoot, wm.PreviousAllocationRoot,
+			"Previous allocation root does not match the allocation root of the previous marker")
+	}
+
+	// markers written before chain hashes were introduced have no chain hash
+	if wm.ChainHash != "" {
+		chainHash := CalculateChainHash(prevChainHash, wm.AllocationRoot)
+		if chainHash != wm.ChainHash {
+			newBreak(AuditBreakChainHash, chainHash, wm.ChainHash,
+				"Chain hash does not match the hash of the previous chain hash and the allocation root")
+		}
+
+		if wm.ChainSize != prevChainSize+wm.Size {
+			newBreak(AuditBreakChainSize, fmt.Sprint(prevChainSize+wm.Size), fmt.Sprint(wm.ChainSize),
+				"Chain size is not the previous chain size plus the marker size")
+		}
+
+		// chain length restarts from 1 when the previous marker was redeemed on chain
+		if prev != nil && prev.WM.ChainHash != "" && wm.ChainLength != prev.WM.ChainLength+1 && wm.ChainLength != 1 {
+			newBreak(AuditBreakChainLength, fmt.Sprint(prev.WM.ChainLength+1), fmt.Sprint(wm.ChainLength),
+				"Chain length does not follow the previous marker")
+		}
+	}
+
+	if prev != nil && wm.Timestamp < prev.WM.Timestamp {
+		newBreak(AuditBreakTimestamp, fmt.Sprint(prev.WM.Timestamp), fmt.Sprint(wm.Timestamp),
+			"Marker timestamp is before the timestamp of the previous marker")
+	}
+
+	sigOK, err := encryption.Verify(wme.ClientPublicKey, wm.Signature, encryption.Hash(wm.GetHashData()))
+	if err != nil {
+		newBreak(AuditBreakSignature, "", wm.Signature, "Error verifying signature: "+err.Error())
+	} else if !sigOK {
+		newBreak(AuditBreakSignature, "", wm.Signature, "Signature does not verify against the client public key")
+	}
+}
+
+func checkLatestRoots(report *AuditReport, latest *WriteMarkerEntity, alloc *allocation.Allocation, rootRef *reference.Ref) {
+	var latestRoot, latestFileMetaRoot string
+	var seq int64
+	if latest != nil {
+		latestRoot = latest.WM.AllocationRoot
+		latestFileMetaRoot = latest.WM.FileMetaRoot
+		seq = latest.Sequence
+	}
+
+	if alloc.AllocationRoot != latestRoot {
+		report.addBreak(&AuditBreak{
+			Kind:           AuditBreakAllocationRoot,
+			Sequence:       seq,
+			AllocationRoot: latestRoot,
+			Expected:       latestRoot,
+			Actual:         alloc.AllocationRoot,
+			Message:        "Allocation root on record does not match the latest write marker",
+		})
+	}
+
+	if rootRef.Hash != latestRoot {
+		report.addBreak(&AuditBreak{
+			Kind:           AuditBreakRefRoot,
+			Sequence:       seq,
+			AllocationRoot: latestRoot,
+			Expected:       latestRoot,
+			Actual:         rootRef.Hash,
+			Message:        "Hash of the current ref tree does not match the latest write marker",
+		})
+	}
+
+	if latest != nil && rootRef.FileMetaHash != latestFileMetaRoot {
+		report.addBreak(&AuditBreak{
+			Kind:           AuditBreakFileMetaRoot,
+			Sequence:       seq,
+			AllocationRoot: latestRoot,
+			Expected:       latestFileMetaRoot,
+			Actual:         rootRef.FileMetaHash,
+			Message:        "File meta hash of the current ref tree does not match the latest write marker",
+		})
+	}
+}
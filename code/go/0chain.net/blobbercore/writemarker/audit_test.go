@@ -0,0 +1,106 @@
+package writemarker
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	coreConfig "github.com/0chain/blobber/code/go/0chain.net/core/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+)
+
+func makeAuditChain(t *testing.T, n int) []*WriteMarkerEntity {
+	coreConfig.Configuration.SignatureScheme = "bls0chain"
+	sch := zcncrypto.NewSignatureScheme("bls0chain")
+	wallet, err := sch.GenerateKeys()
+	require.NoError(t, err)
+
+	markers := make([]*WriteMarkerEntity, 0, n)
+	var prev *WriteMarkerEntity
+	for i := 0; i < n; i++ {
+		root := hex.EncodeToString(encryption.RawHash([]byte{byte(i)}))
+		wme := &WriteMarkerEntity{
+			WM: WriteMarker{
+				AllocationRoot: root,
+				FileMetaRoot:   root,
+				AllocationID:   "audit_allocation",
+				BlobberID:      "audit_blobber",
+				ClientID:       wallet.ClientID,
+				Size:           int64(i + 1),
+				ChainSize:      int64(i + 1),
+				ChainLength:    i + 1,
+				Timestamp:      common.Timestamp(1000 + i),
+			},
+			ClientPublicKey: wallet.ClientKey,
+			Sequence:        int64(i + 10),
+		}
+		if prev != nil {
+			wme.WM.PreviousAllocationRoot = prev.WM.AllocationRoot
+			wme.WM.ChainSize += prev.WM.ChainSize
+		}
+		prevChainHash := ""
+		if prev != nil {
+			prevChainHash = prev.WM.ChainHash
+		}
+		wme.WM.ChainHash = CalculateChainHash(prevChainHash, root)
+		wme.WM.Signature, err = sch.Sign(encryption.Hash(wme.WM.GetHashData()))
+		require.NoError(t, err)
+
+		markers = append(markers, wme)
+		prev = wme
+	}
+	return markers
+}
+
+func breakKinds(report *AuditReport) []string {
+	kinds := make([]string, 0, len(report.Breaks))
+	for _, b := range report.Breaks {
+		kinds = append(kinds, b.Kind)
+	}
+	return kinds
+}
+
+func TestAuditMarkers(t *testing.T) {
+	t.Run("valid chain has no breaks", func(t *testing.T) {
+		markers := makeAuditChain(t, 4)
+		report := AuditMarkers("audit_allocation", markers)
+		require.True(t, report.OK)
+		require.Empty(t, report.Breaks)
+		require.Equal(t, 4, report.MarkersChecked)
+		require.Equal(t, int64(10), report.FirstSequence)
+		require.Equal(t, int64(13), report.LastSequence)
+		require.Equal(t, markers[3].WM.AllocationRoot, report.LatestMarkerRoot)
+	})
+
+	t.Run("tampered root breaks link, chain hash and signature", func(t *testing.T) {
+		markers := makeAuditChain(t, 3)
+		markers[1].WM.AllocationRoot = hex.EncodeToString(encryption.RawHash("tampered"))
+		report := AuditMarkers("audit_allocation", markers)
+		require.False(t, report.OK)
+		require.ElementsMatch(t, []string{
+			AuditBreakChainHash,
+			AuditBreakSignature,
+			AuditBreakPrevRoot,
+		}, breakKinds(report))
+	})
+
+	t.Run("wrong chain size", func(t *testing.T) {
+		markers := makeAuditChain(t, 2)
+		markers[1].WM.ChainSize = 100
+		report := AuditMarkers("audit_allocation", markers)
+		require.Contains(t, breakKinds(report), AuditBreakChainSize)
+	})
+
+	t.Run("chain length restarting after redeem is accepted", func(t *testing.T) {
+		markers := makeAuditChain(t, 3)
+		markers[2].WM.ChainLength = 1
+		report := AuditMarkers("audit_allocation", markers)
+		require.NotContains(t, breakKinds(report), AuditBreakChainLength)
+
+		markers[2].WM.ChainLength = 7
+		report = AuditMarkers("audit_allocation", markers)
+		require.Contains(t, breakKinds(report), AuditBreakChainLength)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -195,6 +196,9 @@ func GetWriteMarkersInRange(ctx context.Context, allocationID string, startAlloc
 		Take(&startWM).Error
 
 	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
 		logging.Logger.Error("write_marker_not_found", zap.Error(err), zap.Any("allocation_root", startAllocationRoot), zap.Any("timestamp", startTimestamp))
 		return nil, common.NewError("write_marker_not_found", "Could not find the start write marker in the range")
 	}
@@ -207,6 +211,9 @@ func GetWriteMarkersInRange(ctx context.Context, allocationID string, startAlloc
 		Order("sequence desc").
 		Take(&endWM).Error
 	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
 		return nil, common.NewError("write_marker_not_found", "Could not find the end write marker in the range")
 	}
 
@@ -0,0 +1,34 @@
+package writemarker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of the redemptions of the write markers.
+const (
+	redeemSuccess = "success"
+	// redeemFailed is a redemption rejected by, or not confirmed on, the chain.
+	redeemFailed = "failed"
+	// redeemError is a redemption not sent to the chain for a local error.
+	redeemError   = "error"
+	redeemSkipped = "skipped"
+)
+
+var (
+	redemptions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blobber_writemarker_redemptions_total",
+		Help: "Redemptions of the write markers, by outcome.",
+	}, []string{"result"})
+
+	lockEvictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blobber_writemarker_lock_evictions_total",
+		Help: "Holders evicted from, and waiters dropped from the queues of, the write marker locks, by reason.",
+	}, []string{"reason"})
+
+	lockWaitSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "blobber_writemarker_lock_wait_seconds",
+		Help:    "Time the connections waited in the queues of the write marker locks.",
+		Buckets: []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	})
+)
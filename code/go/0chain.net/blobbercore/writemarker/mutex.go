@@ -22,10 +22,29 @@ const (
 	LockStatusOK
 )
 
+// Reasons of the evictions of the holders of the locks.
+const (
+	// EvictTimeout is a holder which kept the lock longer than
+	// write_marker_lock_timeout while other connections waited for it.
+	EvictTimeout = "lock_timeout"
+	// dropWaiterGone is a waiter which stopped asking for the lock.
+	dropWaiterGone = "waiter_gone"
+)
+
+// ticketTTL is the time a waiter is kept in the queue after it last asked for
+// the lock.
+const ticketTTL = 10 * time.Second
+
 // swagger:model WriteMarkerLockResult
 type LockResult struct {
 	Status    LockStatus `json:"status,omitempty"`
 	CreatedAt int64      `json:"created_at,omitempty"`
+	// Position is the place of the connection in the queue of the lock,
+	// starting at 1, when the lock is pending.
+	Position int `json:"position,omitempty"`
+	// ExpectedWait is the expected time, in seconds, before the connection
+	// gets the lock, when the lock is pending.
+	ExpectedWait int64 `json:"expected_wait,omitempty"`
 }
 
 var (
@@ -43,10 +62,14 @@ var WriteMarkerMutext = &Mutex{
 	ML: common.GetNewLocker(),
 }
 
-// Lock will create/update lock in postgres.
-// If no lock exists for an allocation then new lock is created.
-// If lock exists and is of same connection ID then lock's createdAt is updated
-// If lock exists and is of other connection ID then `pending` response is sent.
+// Lock gives the lock of the allocation to the connection, first come first
+// served.
+// If the lock is free and no connection waits for it, or the connection is the
+// first one waiting, the lock is given to the connection.
+// If the lock is held by the connection then its createdAt is updated.
+// Otherwise the connection is queued and a `pending` response is sent with its
+// place in the queue. A holder keeping the lock longer than
+// write_marker_lock_timeout is evicted.
 func (m *Mutex) Lock(ctx context.Context, allocationID, connectionID string) (*LockResult, error) {
 	logging.Logger.Info("Locking write marker", zap.String("allocation_id", allocationID), zap.String("connection_id", connectionID))
 	if allocationID == "" {
@@ -62,46 +85,57 @@ func (m *Mutex) Lock(ctx context.Context, allocationID, connectionID string) (*L
 	defer l.Unlock()
 	lockMutex.Lock()
 	defer lockMutex.Unlock()
+
+	now := time.Now()
 	lock, ok := lockPool[allocationID]
 	if !ok {
 		// new lock
 		logging.Logger.Info("Creating new lock")
-		lock = &WriteLock{
-			CreatedAt:    time.Now(),
-			ConnectionID: connectionID,
-		}
+		lock = &WriteLock{}
 		lockPool[allocationID] = lock
+	}
+	lock.dropExpiredTickets(allocationID, now)
+
+	if lock.ConnectionID == connectionID && (lock.held(now) || len(lock.Waiters) == 0) {
+		lock.CreatedAt = now
 		return &LockResult{
 			Status:    LockStatusOK,
 			CreatedAt: lock.CreatedAt.Unix(),
 		}, nil
 	}
 
-	if lock.ConnectionID != connectionID {
-		if time.Since(lock.CreatedAt) > config.Configuration.WriteMarkerLockTimeout || lock.ConnectionID == "" {
-			// Lock expired. Provide lock to other connection id
-			lock.ConnectionID = connectionID
-			lock.CreatedAt = time.Now()
-			return &LockResult{
-				Status:    LockStatusOK,
-				CreatedAt: lock.CreatedAt.Unix(),
-			}, nil
-		}
+	if lock.ConnectionID != "" && !lock.held(now) {
+		lock.evict(allocationID, EvictTimeout, now)
+	}
 
+	if lock.ConnectionID == "" && (len(lock.Waiters) == 0 || lock.Waiters[0].ConnectionID == connectionID) {
+		if len(lock.Waiters) > 0 {
+			lockWaitSeconds.Observe(now.Sub(lock.Waiters[0].EnqueuedAt).Seconds())
+			lock.Waiters = lock.Waiters[1:]
+			lock.notify()
+		}
+		lock.ConnectionID = connectionID
+		lock.CreatedAt = now
 		return &LockResult{
-			Status:    LockStatusPending,
+			Status:    LockStatusOK,
 			CreatedAt: lock.CreatedAt.Unix(),
 		}, nil
 	}
 
-	lock.CreatedAt = time.Now()
-
-	return &LockResult{
-		Status:    LockStatusOK,
-		CreatedAt: lock.CreatedAt.Unix(),
-	}, nil
+	position := lock.enqueue(connectionID, now)
+	result := &LockResult{
+		Status:       LockStatusPending,
+		Position:     position,
+		ExpectedWait: int64((lock.expectedWait(position, now) + time.Second - 1) / time.Second),
+	}
+	if lock.ConnectionID != "" {
+		result.CreatedAt = lock.CreatedAt.Unix()
+	}
+	return result, nil
 }
 
+// Unlock releases the lock of the allocation held by the connection, or drops
+// the connection from the queue of the lock.
 func (*Mutex) Unlock(allocationID string, connectionID string) error {
 	if allocationID == "" || connectionID == "" {
 		return nil
@@ -109,9 +143,237 @@ func (*Mutex) Unlock(allocationID string, connectionID string) error {
 	lockMutex.Lock()
 	defer lockMutex.Unlock()
 	lock, ok := lockPool[allocationID]
+	if !ok {
+		return nil
+	}
 	// reset lock if connection id matches
-	if ok && lock.ConnectionID == connectionID {
+	if lock.ConnectionID == connectionID {
+		lock.observeHold(time.Since(lock.CreatedAt))
 		lock.ConnectionID = ""
+		lock.notify()
+		return nil
+	}
+	if i := lock.ticket(connectionID); i >= 0 {
+		lock.Waiters = append(lock.Waiters[:i], lock.Waiters[i+1:]...)
+		lock.notify()
 	}
 	return nil
 }
+
+// Wait waits, for at most d, until the lock of the allocation can be given to
+// the connection. It returns at once unless the connection is queued for the
+// lock, and keeps it in the queue while it waits. The lock is then taken with
+// Lock.
+func (*Mutex) Wait(ctx context.Context, allocationID, connectionID string, d time.Duration) {
+	deadline := time.Now().Add(d)
+	for {
+		lockMutex.Lock()
+		lock, ok := lockPool[allocationID]
+		if !ok {
+			lockMutex.Unlock()
+			return
+		}
+		now := time.Now()
+		i := lock.ticket(connectionID)
+		if i < 0 || !now.Before(deadline) || (i == 0 && !lock.held(now)) {
+			lockMutex.Unlock()
+			return
+		}
+		if t := lock.Waiters[i]; t.expiresAt.Before(deadline.Add(ticketTTL)) {
+			t.expiresAt = deadline.Add(ticketTTL)
+		}
+		wake := deadline
+		if expiry := lock.CreatedAt.Add(config.Configuration.WriteMarkerLockTimeout); lock.held(now) && expiry.Before(wake) {
+			// the holder times out before the deadline
+			wake = expiry
+		}
+		released := lock.waitRelease()
+		lockMutex.Unlock()
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-released:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// held reports whether a connection holds the lock, which has not expired.
+func (lock *WriteLock) held(now time.Time) bool {
+	return lock.ConnectionID != "" && now.Sub(lock.CreatedAt) <= config.Configuration.WriteMarkerLockTimeout
+}
+
+// evict takes the lock from its holder, recording why.
+func (lock *WriteLock) evict(allocationID, reason string, now time.Time) {
+	lock.LastEviction = &LockEviction{
+		ConnectionID: lock.ConnectionID,
+		Reason:       reason,
+		HeldSince:    lock.CreatedAt,
+		EvictedAt:    now,
+	}
+	logging.Logger.Warn("Evicting write marker lock holder",
+		zap.String("allocation_id", allocationID),
+		zap.String("connection_id", lock.ConnectionID),
+		zap.String("reason", reason),
+		zap.Duration("held", now.Sub(lock.CreatedAt)))
+	lockEvictions.WithLabelValues(reason).Inc()
+	lock.observeHold(now.Sub(lock.CreatedAt))
+	lock.ConnectionID = ""
+	lock.notify()
+}
+
+// observeHold updates the average time the lock is held.
+func (lock *WriteLock) observeHold(d time.Duration) {
+	if lock.avgHold == 0 {
+		lock.avgHold = d
+		return
+	}
+	lock.avgHold = (3*lock.avgHold + d) / 4
+}
+
+// expectedWait is the time the connection at position in the queue is
+// expected to wait for the lock. Until the lock is released once, a holder is
+// expected to keep it up to its timeout.
+func (lock *WriteLock) expectedWait(position int, now time.Time) time.Duration {
+	timeout := config.Configuration.WriteMarkerLockTimeout
+	hold := lock.avgHold
+	if hold == 0 {
+		hold = timeout
+	}
+
+	var wait time.Duration
+	if lock.ConnectionID != "" {
+		elapsed := now.Sub(lock.CreatedAt)
+		if wait = hold - elapsed; wait <= 0 {
+			wait = timeout - elapsed
+		}
+		if wait < 0 {
+			wait = 0
+		}
+	}
+	return wait + time.Duration(position-1)*hold
+}
+
+// ticket returns the index of the ticket of the connection in the queue, -1
+// when it is not queued.
+func (lock *WriteLock) ticket(connectionID string) int {
+	for i, t := range lock.Waiters {
+		if t.ConnectionID == connectionID {
+			return i
+		}
+	}
+	return -1
+}
+
+// enqueue queues the connection, unless it already is, and returns its
+// position in the queue.
+func (lock *WriteLock) enqueue(connectionID string, now time.Time) int {
+	i := lock.ticket(connectionID)
+	if i < 0 {
+		lock.Waiters = append(lock.Waiters, &LockTicket{
+			ConnectionID: connectionID,
+			EnqueuedAt:   now,
+		})
+		i = len(lock.Waiters) - 1
+	}
+	if t := lock.Waiters[i]; t.expiresAt.Before(now.Add(ticketTTL)) {
+		t.expiresAt = now.Add(ticketTTL)
+	}
+	return i + 1
+}
+
+// dropExpiredTickets drops the waiters which stopped asking for the lock.
+func (lock *WriteLock) dropExpiredTickets(allocationID string, now time.Time) {
+	waiters := lock.Waiters[:0]
+	for _, t := range lock.Waiters {
+		if now.After(t.expiresAt) {
+			logging.Logger.Info("Dropping write marker lock waiter",
+				zap.String("allocation_id", allocationID),
+				zap.String("connection_id", t.ConnectionID))
+			lockEvictions.WithLabelValues(dropWaiterGone).Inc()
+			continue
+		}
+		waiters = append(waiters, t)
+	}
+	if len(waiters) != len(lock.Waiters) {
+		lock.notify()
+	}
+	lock.Waiters = waiters
+}
+
+// waitRelease returns a channel closed when the lock is released or the queue
+// moves.
+func (lock *WriteLock) waitRelease() <-chan struct{} {
+	if lock.released == nil {
+		lock.released = make(chan struct{})
+	}
+	return lock.released
+}
+
+func (lock *WriteLock) notify() {
+	if lock.released != nil {
+		close(lock.released)
+		lock.released = nil
+	}
+}
+
+// Held reports whether the connection holds the lock of the allocation.
+func (*Mutex) Held(allocationID, connectionID string) bool {
+	if connectionID == "" {
+		return false
+	}
+	lockMutex.Lock()
+	defer lockMutex.Unlock()
+	lock, ok := lockPool[allocationID]
+	return ok && lock.ConnectionID == connectionID && lock.held(time.Now())
+}
+
+// HeldCount returns the number of allocations with their lock held.
+func (*Mutex) HeldCount() int {
+	lockMutex.Lock()
+	defer lockMutex.Unlock()
+	var n int
+	now := time.Now()
+	for _, lock := range lockPool {
+		if lock.held(now) {
+			n++
+		}
+	}
+	return n
+}
+
+// LockState is the state of the write marker lock of an allocation.
+type LockState struct {
+	Holder       string        `json:"holder,omitempty"`
+	HeldSince    *time.Time    `json:"held_since,omitempty"`
+	Waiters      []*LockTicket `json:"waiters"`
+	LastEviction *LockEviction `json:"last_eviction,omitempty"`
+}
+
+// GetLockState returns the state of the write marker lock of the allocation.
+func GetLockState(allocationID string) *LockState {
+	lockMutex.Lock()
+	defer lockMutex.Unlock()
+	state := &LockState{Waiters: []*LockTicket{}}
+	lock, ok := lockPool[allocationID]
+	if !ok {
+		return state
+	}
+	if lock.held(time.Now()) {
+		since := lock.CreatedAt
+		state.Holder, state.HeldSince = lock.ConnectionID, &since
+	}
+	for _, t := range lock.Waiters {
+		cp := *t
+		state.Waiters = append(state.Waiters, &cp)
+	}
+	if lock.LastEviction != nil {
+		e := *lock.LastEviction
+		state.LastEviction = &e
+	}
+	return state
+}
@@ -140,3 +140,129 @@ func TestMutext_LockShouldWork(t *testing.T) {
 	}
 
 }
+
+func TestMutex_FIFO(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 30 * time.Second
+	m := &Mutex{ML: common.GetNewLocker()}
+	ctx := context.Background()
+	const alloc = "fifo_allocation_id"
+
+	r, err := m.Lock(ctx, alloc, "a")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+
+	r, err = m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusPending, r.Status)
+	require.Equal(t, 1, r.Position)
+	require.EqualValues(t, 30, r.ExpectedWait)
+
+	r, err = m.Lock(ctx, alloc, "c")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Position)
+	require.EqualValues(t, 60, r.ExpectedWait)
+
+	// asking again keeps the place in the queue
+	r, err = m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, 1, r.Position)
+
+	require.NoError(t, m.Unlock(alloc, "a"))
+
+	// the lock goes to the first waiter, not to the first one asking
+	r, err = m.Lock(ctx, alloc, "c")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusPending, r.Status)
+	require.Equal(t, 2, r.Position)
+
+	r, err = m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+	require.True(t, m.Held(alloc, "b"))
+
+	require.NoError(t, m.Unlock(alloc, "b"))
+	r, err = m.Lock(ctx, alloc, "c")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+
+	state := GetLockState(alloc)
+	require.Equal(t, "c", state.Holder)
+	require.Empty(t, state.Waiters)
+}
+
+func TestMutex_EvictStaleHolder(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { config.Configuration.WriteMarkerLockTimeout = 30 * time.Second })
+	m := &Mutex{ML: common.GetNewLocker()}
+	ctx := context.Background()
+	const alloc = "evict_allocation_id"
+
+	_, err := m.Lock(ctx, alloc, "stale")
+	require.NoError(t, err)
+	r, err := m.Lock(ctx, alloc, "next")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusPending, r.Status)
+
+	time.Sleep(60 * time.Millisecond)
+	r, err = m.Lock(ctx, alloc, "next")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+
+	state := GetLockState(alloc)
+	require.NotNil(t, state.LastEviction)
+	require.Equal(t, "stale", state.LastEviction.ConnectionID)
+	require.Equal(t, EvictTimeout, state.LastEviction.Reason)
+}
+
+func TestMutex_DropGoneWaiter(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 30 * time.Second
+	m := &Mutex{ML: common.GetNewLocker()}
+	ctx := context.Background()
+	const alloc = "gone_allocation_id"
+
+	_, err := m.Lock(ctx, alloc, "a")
+	require.NoError(t, err)
+	_, err = m.Lock(ctx, alloc, "gone")
+	require.NoError(t, err)
+	r, err := m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Position)
+
+	lockMutex.Lock()
+	lockPool[alloc].Waiters[0].expiresAt = time.Now().Add(-time.Second)
+	lockMutex.Unlock()
+	require.NoError(t, m.Unlock(alloc, "a"))
+
+	r, err = m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+}
+
+func TestMutex_WaitWakesOnRelease(t *testing.T) {
+	config.Configuration.WriteMarkerLockTimeout = 30 * time.Second
+	m := &Mutex{ML: common.GetNewLocker()}
+	ctx := context.Background()
+	const alloc = "wait_allocation_id"
+
+	_, err := m.Lock(ctx, alloc, "a")
+	require.NoError(t, err)
+	_, err = m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = m.Unlock(alloc, "a")
+	}()
+	start := time.Now()
+	m.Wait(ctx, alloc, "b", 5*time.Second)
+	require.Less(t, time.Since(start), 2*time.Second)
+
+	r, err := m.Lock(ctx, alloc, "b")
+	require.NoError(t, err)
+	require.Equal(t, LockStatusOK, r.Status)
+
+	// a connection not queued does not wait
+	start = time.Now()
+	m.Wait(ctx, alloc, "unknown", 5*time.Second)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -120,8 +120,10 @@ func redeemWriteMarker(md *markerData) error {
 	allocationID := md.allocationID
 	shouldRollback := false
 	start := time.Now()
+	result := redeemError
 	logging.Logger.Info("redeeming_write_marker", zap.String("allocationID", allocationID))
 	defer func() {
+		redemptions.WithLabelValues(result).Inc()
 		if shouldRollback {
 			if rollbackErr := db.Rollback().Error; rollbackErr != nil {
 				logging.Logger.Error("Error rollback on redeeming the write marker.",
@@ -152,6 +154,15 @@ func redeemWriteMarker(md *markerData) error {
 		logging.Logger.Info("Allocation is finalized. Skipping redeeming the write marker.", zap.Any("allocation", allocationID))
 		go deleteMarkerData(allocationID)
 		shouldRollback = true
+		result = redeemSkipped
+		return nil
+	}
+
+	if alloc.RedeemPaused {
+		logging.Logger.Info("Redemption is paused for the allocation. Skipping redeeming the write marker.", zap.Any("allocation", allocationID))
+		go deleteMarkerData(allocationID)
+		shouldRollback = true
+		result = redeemSkipped
 		return nil
 	}
 
@@ -177,6 +188,7 @@ func redeemWriteMarker(md *markerData) error {
 			go deleteMarkerData(allocationID)
 		}
 		shouldRollback = true
+		result = redeemFailed
 		return err
 	}
 
@@ -199,6 +211,7 @@ func redeemWriteMarker(md *markerData) error {
 		go tryAgain(md)
 		return err
 	}
+	result = redeemSuccess
 	elapsedTime := time.Since(start)
 	logging.Logger.Info("Success Redeeming the write marker",
 		zap.Any("allocation", allocationID),
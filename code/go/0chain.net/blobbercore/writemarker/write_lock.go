@@ -2,8 +2,37 @@ package writemarker
 
 import "time"
 
-// WriteLock WriteMarker lock
+// WriteLock WriteMarker lock, with the queue of the connections waiting for it
 type WriteLock struct {
 	ConnectionID string
 	CreatedAt    time.Time
+	// Waiters are the connections waiting for the lock, served first come
+	// first served.
+	Waiters []*LockTicket
+	// LastEviction is the last holder evicted from the lock, if any.
+	LastEviction *LockEviction
+
+	// avgHold is the moving average of the time the lock is held, zero until
+	// it is released once.
+	avgHold time.Duration
+	// released is closed when the lock is released or the queue moves, to
+	// wake up the connections waiting for their turn.
+	released chan struct{}
+}
+
+// LockTicket is the place of a connection in the queue of a lock.
+type LockTicket struct {
+	ConnectionID string    `json:"connection_id"`
+	EnqueuedAt   time.Time `json:"enqueued_at"`
+	// expiresAt is the time the ticket is dropped unless the connection asks
+	// for the lock again, so a client gone does not block the queue.
+	expiresAt time.Time
+}
+
+// LockEviction is a holder evicted from a lock.
+type LockEviction struct {
+	ConnectionID string    `json:"connection_id"`
+	Reason       string    `json:"reason"`
+	HeldSince    time.Time `json:"held_since"`
+	EvictedAt    time.Time `json:"evicted_at"`
 }
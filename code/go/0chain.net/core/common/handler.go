@@ -8,6 +8,9 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -64,8 +67,10 @@ var domainRE = regexp.MustCompile(`^(?:https?:\/\/)?(?:[^@\/\n]+@)?(?:www\.)?([^
 
 func ToByteStream(handler JSONResponderF) ReqRespHandlerf {
 	return func(w http.ResponseWriter, r *http.Request) {
+		r, span := tracing.StartHTTP(r)
 		ctx := r.Context()
 		data, err := handler(ctx, r)
+		defer tracing.End(span, err)
 		if err != nil {
 			if cerr, ok := err.(*Error); ok {
 				w.Header().Set(AppErrorHeader, cerr.Code)
@@ -113,9 +118,11 @@ func ToJSONResponse(handler JSONResponderF) ReqRespHandlerf {
 			SetupCORSResponse(w, r)
 			return
 		}
+		r, span := tracing.StartHTTP(r)
 		ctx := r.Context()
 		data, err := handler(ctx, r)
 		Respond(w, data, err)
+		tracing.End(span, err)
 	}
 }
 
@@ -173,9 +180,12 @@ func ToStatusCode(handler StatusCodeResponderF) ReqRespHandlerf {
 			return
 		}
 
+		r, span := tracing.StartHTTP(r)
 		ctx := r.Context()
 
 		data, statusCode, err := handler(ctx, r)
+		span.SetAttributes(attribute.Int("http.status_code", statusCode))
+		defer tracing.End(span, err)
 
 		if err != nil {
 			if statusCode == 0 {
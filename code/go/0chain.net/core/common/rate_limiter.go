@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
 	tollbooth "github.com/didip/tollbooth/v6"
 	"github.com/didip/tollbooth/v6/limiter"
 )
@@ -38,6 +39,7 @@ func RateLimit(handler ReqRespHandlerf, lmt *limiter.Limiter) ReqRespHandlerf {
 				httpError := tollbooth.LimitByKeys(lmt, k)
 				if httpError != nil {
 					logging.Logger.Error(fmt.Sprintf("Rate limit error: %s", httpError.Error()))
+					metrics.RateLimited(metrics.Route(r))
 					lmt.ExecOnLimitReached(w, r)
 					setResponseHeaders(lmt, w, r)
 					w.Header().Add("Content-Type", lmt.GetMessageContentType())
@@ -60,6 +62,7 @@ func RateLimitByIP(handler ReqRespHandlerf, lmt *limiter.Limiter) ReqRespHandler
 				httpError := tollbooth.LimitByKeys(lmt, k)
 				if httpError != nil {
 					logging.Logger.Error(fmt.Sprintf("Rate limit error: %s", httpError.Error()))
+					metrics.RateLimited(metrics.Route(r))
 					lmt.ExecOnLimitReached(w, r)
 					setResponseHeaders(lmt, w, r)
 					w.Header().Add("Content-Type", lmt.GetMessageContentType())
@@ -0,0 +1,122 @@
+// Package metrics exposes the metrics of the blobber and the validator to
+// Prometheus. The metrics shared by both services live here; the metrics of
+// a component are declared next to the code recording them.
+package metrics
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests by route, method and status code.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"route", "method", "code"})
+
+	rateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected by the rate limiters, by route or gRPC method.",
+	}, []string{"route"})
+)
+
+// Handler serves the metrics to Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve serves the metrics on a listener of their own, kept apart from the
+// public API of the service. It returns once the listener fails.
+func Serve(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 30 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+		Handler:           Handler(),
+	}
+	return server.ListenAndServe()
+}
+
+// UseHTTP is a middleware recording the latency of the requests of the routes
+// of the router.
+func UseHTTP(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		requestDuration.WithLabelValues(Route(r), r.Method, strconv.Itoa(sw.code)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// Route is the template of the route of the request, which unlike its path
+// does not hold the ids of the allocations.
+func Route(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return "unknown"
+}
+
+// RateLimited counts a request of the route rejected by a rate limiter.
+func RateLimited(route string) {
+	rateLimited.WithLabelValues(route).Inc()
+}
+
+// RegisterDBStats exposes the statistics of the connection pool of db.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wroteHeader {
+		sw.code, sw.wroteHeader = code, true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(b)
+}
+
+// Flush lets the streamed and shaped responses reach the connection.
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		sw.wroteHeader = true
+		f.Flush()
+	}
+}
+
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
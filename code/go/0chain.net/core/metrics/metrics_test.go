@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseHTTP(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(UseHTTP)
+	router.HandleFunc("/v1/file/meta/{allocation}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.WriteHeader(http.StatusOK) // superfluous, the first code is sent
+	})
+	router.Handle("/metrics", Handler())
+
+	for i := 0; i < 2; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/file/meta/alloc-1", nil))
+	}
+	require.Equal(t, 1, testutil.CollectAndCount(requestDuration))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(),
+		`http_request_duration_seconds_count{code="404",method="GET",route="/v1/file/meta/{allocation}"} 2`)
+	require.NotContains(t, w.Body.String(), "alloc-1")
+}
+
+func TestUseHTTP_Passthrough(t *testing.T) {
+	var flushed, hijackable bool
+	h := UseHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hijackable = w.(http.Hijacker)
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+			flushed = true
+		}
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.True(t, flushed)
+	require.True(t, w.Flushed, "the flush reaches the underlying writer")
+	require.True(t, hijackable)
+}
+
+func TestRateLimited(t *testing.T) {
+	RateLimited("/v1/file/upload/{allocation}")
+	RateLimited("/v1/file/upload/{allocation}")
+	require.Equal(t, 2.0, testutil.ToFloat64(rateLimited.WithLabelValues("/v1/file/upload/{allocation}")))
+
+	expected := `
+# HELP rate_limited_requests_total Requests rejected by the rate limiters, by route or gRPC method.
+# TYPE rate_limited_requests_total counter
+rate_limited_requests_total{route="/v1/file/upload/{allocation}"} 2
+`
+	require.NoError(t, testutil.CollectAndCompare(rateLimited, strings.NewReader(expected)))
+}
@@ -0,0 +1,195 @@
+// Package tracing traces the requests and the background work of the blobber
+// and the validator with OpenTelemetry. Until Setup is called with tracing
+// enabled the spans are not recorded.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0chain/blobber"
+
+// Exporters of the spans.
+const (
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Config of the tracing.
+type Config struct {
+	Enabled bool
+	// Exporter is one of "stdout", "file" or "otlp".
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint string
+	// Insecure sends the spans to the collector over plain HTTP.
+	Insecure bool
+	// File the spans are written to by the "file" exporter.
+	File string
+	// SampleRatio is the ratio of the traces started here that are recorded.
+	// The traces continued from a caller follow the decision of the caller.
+	SampleRatio float64
+}
+
+var tracer = otel.Tracer(instrumentationName)
+
+// Setup installs the tracer provider of the service. The returned function
+// flushes the spans not exported yet and stops the tracing.
+func Setup(serviceName string, cfg Config) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			closer.Close()
+		}
+		return err
+	}, nil
+}
+
+func newExporter(cfg Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterStdout, "":
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exp, nil, err
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing: open %q: %w", cfg.File, err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return exp, f, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(context.Background(), opts...)
+		return exp, nil, err
+	default:
+		return nil, nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Exporter)
+	}
+}
+
+// Start starts a span as a child of the span of ctx, if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartLinked starts a span as a child of the span of ctx, linked to the
+// spans of others, for work done at once for several traces.
+func StartLinked(ctx context.Context, name string, others []context.Context, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(others))
+	for _, other := range others {
+		links = append(links, trace.LinkFromContext(other))
+	}
+	return tracer.Start(ctx, name, trace.WithLinks(links...), trace.WithAttributes(attrs...))
+}
+
+// End records the error of the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach returns a context carrying the span of ctx, but neither its
+// deadline nor its cancellation.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// Inject adds the trace context of ctx to the headers of an outgoing request.
+func Inject(ctx context.Context, h http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// StartHTTP starts the server span of the request, continuing the trace of
+// the caller. The span is named after the route of the request, not its path,
+// which holds the ids of the allocations.
+func StartHTTP(r *http.Request) (*http.Request, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	name := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			name = tmpl
+		}
+	}
+	ctx, span := tracer.Start(ctx, r.Method+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethod(r.Method),
+			semconv.HTTPRoute(name),
+		))
+	return r.WithContext(ctx), span
+}
+
+// StartRPC starts the server span of a gRPC call, continuing the trace of the
+// caller found in the metadata of the call.
+func StartRPC(ctx context.Context, fullMethod string, md map[string][]string) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	return tracer.Start(ctx, fullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC))
+}
+
+// metadataCarrier reads the trace context from gRPC metadata, whose keys are
+// lower case.
+type metadataCarrier map[string][]string
+
+func (c metadataCarrier) Get(key string) string {
+	if v := c[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_FileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup("test", Config{Enabled: true, Exporter: ExporterFile, File: file, SampleRatio: 1})
+	require.NoError(t, err)
+
+	// the caller of the request
+	callerCtx, caller := Start(context.Background(), "caller")
+	outgoing := httptest.NewRequest(http.MethodGet, "/v1/file/download/alloc-1", nil)
+	Inject(callerCtx, outgoing.Header)
+	require.NotEmpty(t, outgoing.Header.Get("traceparent"))
+
+	var served trace.SpanContext
+	router := mux.NewRouter()
+	router.HandleFunc("/v1/file/download/{allocation}", func(w http.ResponseWriter, r *http.Request) {
+		r, span := StartHTTP(r)
+		served = trace.SpanContextFromContext(r.Context())
+
+		// a transaction is not cancelled with the request, but is in its trace
+		detached := Detach(r.Context())
+		_, child := Start(detached, "datastore.WithNewTransaction")
+		End(child, errors.New("rollback"))
+		End(span, nil)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), outgoing)
+	End(caller, nil)
+
+	require.Equal(t, caller.SpanContext().TraceID(), served.TraceID())
+
+	require.NoError(t, shutdown(context.Background()))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	spans := string(data)
+	require.Contains(t, spans, `"Name":"GET /v1/file/download/{allocation}"`)
+	require.Contains(t, spans, `"Name":"datastore.WithNewTransaction"`)
+	require.Contains(t, spans, "rollback")
+	require.NotContains(t, spans, "alloc-1")
+	require.Contains(t, spans, caller.SpanContext().TraceID().String())
+}
+
+func TestTracing_RPCMetadata(t *testing.T) {
+	ctx, span := Start(context.Background(), "client")
+	defer span.End()
+
+	h := http.Header{}
+	Inject(ctx, h)
+	md := map[string][]string{}
+	for k, v := range h {
+		md[strings.ToLower(k)] = v
+	}
+
+	rpcCtx, rpcSpan := StartRPC(context.Background(), "/blobber.service.v1.BlobberService/DownloadFile", md)
+	defer rpcSpan.End()
+	require.Equal(t, span.SpanContext().TraceID(), trace.SpanContextFromContext(rpcCtx).TraceID())
+}
+
+func TestTracing_Disabled(t *testing.T) {
+	shutdown, err := Setup("test", Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	_, err = Setup("test", Config{Enabled: true, Exporter: "unknown"})
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"context"
 	"encoding/json"
 	"sync"
 	"time"
@@ -15,6 +16,8 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/core/chain"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -39,6 +42,8 @@ type Transaction struct {
 	OutputHash        string           `json:"txn_output_hash"`
 	zcntxn            zcncore.TransactionScheme
 	wg                *sync.WaitGroup
+	// ctx is the trace the chain calls of the transaction are part of
+	ctx context.Context
 }
 
 type SmartContractTxnData struct {
@@ -76,8 +81,9 @@ type StorageNode struct {
 }
 
 type BlobberAllocation struct {
-	BlobberID string `json:"blobber_id"`
-	Terms     Terms  `json:"terms"`
+	BlobberID      string `json:"blobber_id"`
+	Terms          Terms  `json:"terms"`
+	AllocationRoot string `json:"allocation_root"`
 }
 
 type StorageAllocation struct {
@@ -140,7 +146,29 @@ func (t *Transaction) GetTransaction() zcncore.TransactionScheme {
 	return t.zcntxn
 }
 
+// WithContext makes the chain calls of the transaction part of the trace of
+// ctx.
+func (t *Transaction) WithContext(ctx context.Context) *Transaction {
+	t.ctx = ctx
+	return t
+}
+
+func (t *Transaction) traceContext() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
+
 func (t *Transaction) ExecuteSmartContract(address, methodName string, input interface{}, val uint64) error {
+	_, span := tracing.Start(t.traceContext(), "transaction.ExecuteSmartContract", attribute.String("method", methodName))
+	err := t.executeSmartContract(address, methodName, input, val)
+	span.SetAttributes(attribute.String("hash", t.Hash))
+	tracing.End(span, err)
+	return err
+}
+
+func (t *Transaction) executeSmartContract(address, methodName string, input interface{}, val uint64) error {
 	t.wg.Add(1)
 
 	sn := transaction.SmartContractTxnData{Name: methodName, InputArgs: input}
@@ -189,6 +217,14 @@ func (t *Transaction) ExecuteSmartContract(address, methodName string, input int
 }
 
 func (t *Transaction) ExecuteRollbackWM(address, methodName string, input interface{}, val uint64, prevNonce int64) error {
+	_, span := tracing.Start(t.traceContext(), "transaction.ExecuteRollbackWM", attribute.String("method", methodName))
+	err := t.executeRollbackWM(address, methodName, input, val, prevNonce)
+	span.SetAttributes(attribute.String("hash", t.Hash))
+	tracing.End(span, err)
+	return err
+}
+
+func (t *Transaction) executeRollbackWM(address, methodName string, input interface{}, val uint64, prevNonce int64) error {
 	t.wg.Add(1)
 
 	sn := transaction.SmartContractTxnData{Name: methodName, InputArgs: input}
@@ -246,6 +282,13 @@ func (t *Transaction) ExecuteRollbackWM(address, methodName string, input interf
 }
 
 func (t *Transaction) Verify() error {
+	_, span := tracing.Start(t.traceContext(), "transaction.Verify", attribute.String("hash", t.Hash))
+	err := t.verify()
+	tracing.End(span, err)
+	return err
+}
+
+func (t *Transaction) verify() error {
 	if err := t.zcntxn.SetTransactionHash(t.Hash); err != nil {
 		monitor.recordFailedNonce(t.zcntxn.GetTransactionNonce())
 		logging.Logger.Error("Failed to set txn hash.",
@@ -1,9 +1,13 @@
 package transaction
 
 import (
+	"context"
+
 	"github.com/0chain/blobber/code/go/0chain.net/core/chain"
 	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/0chain/gosdk/zboxcore/zboxutil"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const TXN_SUBMIT_URL = "v1/transaction/put"
@@ -16,10 +20,32 @@ const (
 )
 
 var ErrNoTxnDetail = common.NewError("missing_transaction_detail", "No transaction detail was found on any of the sharders")
-var MakeSCRestAPICall func(scAddress string, relativePath string, params map[string]string) ([]byte, error) = MakeSCRestAPICallNoHandler
+var MakeSCRestAPICall func(ctx context.Context, scAddress string, relativePath string, params map[string]string) ([]byte, error) = MakeSCRestAPICallNoHandler
+
+// MakeSCRestAPICallNoHandler makes the SC REST API call as part of the trace of ctx.
+func MakeSCRestAPICallNoHandler(ctx context.Context, address string, path string, params map[string]string) ([]byte, error) {
+	_, span := tracing.Start(ctx, "transaction.MakeSCRestAPICall", attribute.String("path", path))
+	resp, err := zboxutil.MakeSCRestAPICall(address, path, params, nil)
+	tracing.End(span, err)
+	return resp, err
+}
+
+// MakeSCRestAPICallAllSharders makes the SC REST API call and returns the
+// response of each sharder, by sharder url, as part of the trace of ctx.
+var MakeSCRestAPICallAllSharders func(ctx context.Context, scAddress string, relativePath string, params map[string]string) (map[string][]byte, error) = makeSCRestAPICallAllSharders
 
-func MakeSCRestAPICallNoHandler(address string, path string, params map[string]string) ([]byte, error) {
-	return zboxutil.MakeSCRestAPICall(address, path, params, nil)
+func makeSCRestAPICallAllSharders(ctx context.Context, address string, path string, params map[string]string) (_ map[string][]byte, err error) {
+	_, span := tracing.Start(ctx, "transaction.MakeSCRestAPICallAllSharders", attribute.String("path", path))
+	defer func() { tracing.End(span, err) }()
+
+	var responses map[string][]byte
+	_, err = zboxutil.MakeSCRestAPICall(address, path, params, func(response map[string][]byte, numSharders int, err error) {
+		responses = response
+	})
+	if err != nil && len(responses) == 0 {
+		return nil, err
+	}
+	return responses, nil
 }
 
 func VerifyTransaction(txnHash string, chain *chain.Chain) (*Transaction, error) {
@@ -13,6 +13,7 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 
 	"go.uber.org/zap"
 )
@@ -21,8 +22,18 @@ const MAX_RETRIES = 3
 const SLEEP_BETWEEN_RETRIES = 5
 
 func NewHTTPRequest(method, url string, data []byte) (*http.Request, context.Context, context.CancelFunc, error) {
+	return NewHTTPRequestContext(context.Background(), method, url, data)
+}
+
+// NewHTTPRequestContext is NewHTTPRequest for a request that is part of the
+// trace of parent. The request is not bound to the cancellation of parent.
+func NewHTTPRequestContext(parent context.Context, method, url string, data []byte) (*http.Request, context.Context, context.CancelFunc, error) {
 	requestHash := encryption.Hash(data)
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, context.Background(), func() {}, err
+	}
+	tracing.Inject(parent, req.Header)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Access-Control-Allow-Origin", "*")
 	req.Header.Set("X-App-Client-ID", node.Self.ID)
@@ -43,6 +54,12 @@ func SendMultiPostRequest(urls []string, data []byte) {
 }
 
 func SendPostRequest(postURL string, data []byte, wg *sync.WaitGroup) (body []byte, err error) {
+	return SendPostRequestContext(context.Background(), postURL, data, wg)
+}
+
+// SendPostRequestContext is SendPostRequest for a request that is part of the
+// trace of parent.
+func SendPostRequestContext(parent context.Context, postURL string, data []byte, wg *sync.WaitGroup) (body []byte, err error) {
 	if wg != nil {
 		defer wg.Done()
 	}
@@ -61,7 +78,7 @@ func SendPostRequest(postURL string, data []byte, wg *sync.WaitGroup) (body []by
 			cncl context.CancelFunc
 		)
 
-		req, ctx, cncl, err = NewHTTPRequest(http.MethodPost, u.String(), data)
+		req, ctx, cncl, err = NewHTTPRequestContext(parent, http.MethodPost, u.String(), data)
 		defer cncl()
 
 		resp, err = http.DefaultClient.Do(req.WithContext(ctx))
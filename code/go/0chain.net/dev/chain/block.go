@@ -0,0 +1,106 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/util"
+)
+
+// emptyRoot is the merkle root of a block without transactions.
+var emptyRoot = encryption.Hash("")
+
+// BlockHeader is the header of a block, as served by the sharders.
+type BlockHeader struct {
+	Version               string `json:"version"`
+	CreationDate          int64  `json:"creation_date"`
+	Hash                  string `json:"hash"`
+	PrevHash              string `json:"prev_hash"`
+	MinerID               string `json:"miner_id"`
+	Round                 int64  `json:"round"`
+	RoundRandomSeed       int64  `json:"round_random_seed"`
+	StateChangesCount     int    `json:"state_changes_count"`
+	MerkleTreeRoot        string `json:"merkle_tree_root"`
+	ReceiptMerkleTreeRoot string `json:"receipt_merkle_tree_root"`
+	NumTxns               int64  `json:"num_txns"`
+}
+
+// Block is the block of a round, with the transactions sealed in it.
+type Block struct {
+	BlockHeader
+	Txns []*transaction.Transaction
+
+	txnPaths     []*util.MTPath
+	receiptPaths []*util.MTPath
+}
+
+func newBlock(prev *Block, minerID string, round, creationDate, seed int64, txns []*transaction.Transaction) *Block {
+	b := &Block{
+		BlockHeader: BlockHeader{
+			Version:           "1.0",
+			CreationDate:      creationDate,
+			MinerID:           minerID,
+			Round:             round,
+			RoundRandomSeed:   seed,
+			StateChangesCount: len(txns),
+			NumTxns:           int64(len(txns)),
+		},
+		Txns: txns,
+	}
+	if prev != nil {
+		b.PrevHash = prev.Hash
+	}
+
+	hashes := make([]string, len(txns))
+	receipts := make([]string, len(txns))
+	for i, t := range txns {
+		hashes[i] = t.Hash
+		receipts[i] = transaction.NewTransactionReceipt(t).GetHash()
+	}
+	b.MerkleTreeRoot, b.txnPaths = merkleTree(hashes)
+	b.ReceiptMerkleTreeRoot, b.receiptPaths = merkleTree(receipts)
+
+	b.Hash = encryption.Hash(fmt.Sprintf("%v:%v:%v:%v:%v:%v:%v:%v", b.MinerID, b.PrevHash, b.CreationDate,
+		b.Round, b.RoundRandomSeed, b.StateChangesCount, b.MerkleTreeRoot, b.ReceiptMerkleTreeRoot))
+	return b
+}
+
+// merkleTree returns the root of the merkle tree of the leaves and the path of
+// each leaf, as verified by util.VerifyMerklePath. The last node of a level
+// with an odd number of nodes is paired with itself.
+func merkleTree(leaves []string) (string, []*util.MTPath) {
+	if len(leaves) == 0 {
+		return emptyRoot, nil
+	}
+
+	paths := make([]*util.MTPath, len(leaves))
+	index := make([]int, len(leaves))
+	for i := range leaves {
+		paths[i] = &util.MTPath{Nodes: []string{}, LeafIndex: i}
+		index[i] = i
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		for i, idx := range index {
+			sibling := idx ^ 1
+			if sibling >= len(level) {
+				sibling = idx
+			}
+			paths[i].Nodes = append(paths[i].Nodes, level[sibling])
+			index[i] = idx / 2
+		}
+
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, util.MHash(level[i], right))
+		}
+		level = next
+	}
+	return level[0], paths
+}
@@ -0,0 +1,389 @@
+// Package chain is an in-process simulator of the chain the blobbers and the
+// validators talk to. It takes the transactions of the clients, seals them in
+// the blocks of its rounds and keeps the state of the storage smart contract,
+// so end-to-end tests and local sandboxes need no network.
+package chain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	sctxn "github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/util"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"go.uber.org/zap"
+)
+
+const (
+	// StorageSCAddress is the address of the storage smart contract.
+	StorageSCAddress = sctxn.STORAGE_CONTRACT_ADDRESS
+	// FaucetSCAddress is the address of the faucet smart contract.
+	FaucetSCAddress = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712d3"
+)
+
+// txnExpiration is the time, in seconds, a transaction can be sealed in a
+// block after its creation.
+const txnExpiration = 60
+
+// Config configures the chain.
+type Config struct {
+	// ChainID is the id of the chain. The transactions of other chains are refused.
+	ChainID string
+	// SignatureScheme is the scheme the signatures of the clients are verified with.
+	SignatureScheme string
+	// ValidatorsPerChallenge is the number of validators picked to validate a challenge.
+	ValidatorsPerChallenge int
+	// ChallengeCompletionRounds is the number of rounds a blobber has to answer a challenge.
+	ChallengeCompletionRounds int64
+	// MaxFileSize is the size of the largest file of the allocations.
+	MaxFileSize int64
+	// FaucetPour is the tokens the faucet pours to the clients.
+	FaucetPour uint64
+}
+
+// DefaultConfig returns the configuration of the chain of the local sandbox.
+func DefaultConfig() Config {
+	return Config{
+		ChainID:                   "0afc093ffb509f059c55478bc1a60351cef7b4e9c008a53a6cc8241ca8617dfe",
+		SignatureScheme:           "bls0chain",
+		ValidatorsPerChallenge:    2,
+		ChallengeCompletionRounds: 720,
+		MaxFileSize:               5 * 1024 * 1024 * 1024,
+		FaucetPour:                10 * 1e10,
+	}
+}
+
+// Client is the account of a client.
+type Client struct {
+	Round   int64  `json:"round"`
+	Balance uint64 `json:"balance"`
+	Nonce   int64  `json:"nonce"`
+}
+
+// Confirmation is the confirmation of a transaction sealed in a block, with
+// the merkle paths proving it is part of the block.
+type Confirmation struct {
+	Version               string                   `json:"version"`
+	Hash                  string                   `json:"hash"`
+	BlockHash             string                   `json:"block_hash"`
+	PreviousBlockHash     string                   `json:"previous_block_hash"`
+	Transaction           *transaction.Transaction `json:"txn"`
+	CreationDate          int64                    `json:"creation_date"`
+	MinerID               string                   `json:"miner_id"`
+	Round                 int64                    `json:"round"`
+	Status                int                      `json:"transaction_status"`
+	RoundRandomSeed       int64                    `json:"round_random_seed"`
+	StateChangesCount     int                      `json:"state_changes_count"`
+	MerkleTreeRoot        string                   `json:"merkle_tree_root"`
+	MerkleTreePath        *util.MTPath             `json:"merkle_tree_path"`
+	ReceiptMerkleTreeRoot string                   `json:"receipt_merkle_tree_root"`
+	ReceiptMerkleTreePath *util.MTPath             `json:"receipt_merkle_tree_path"`
+}
+
+type sealedTxn struct {
+	block *Block
+	index int
+}
+
+// Chain is the simulated chain. Its blocks are final as soon as they are
+// sealed.
+type Chain struct {
+	cfg     Config
+	minerID string
+
+	mu      sync.RWMutex
+	rand    *rand.Rand
+	blocks  []*Block
+	pending []*transaction.Transaction
+	txns    map[string]*sealedTxn
+	clients map[string]*Client
+	storage *storageSC
+}
+
+// New creates a chain with its genesis block.
+func New(cfg Config) (*Chain, error) {
+	switch cfg.SignatureScheme {
+	case "bls0chain", "ed25519":
+	default:
+		return nil, common.NewErrorf("invalid_config", "unknown signature scheme %q", cfg.SignatureScheme)
+	}
+
+	c := &Chain{
+		cfg:     cfg,
+		minerID: encryption.Hash("dev_miner"),
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		txns:    make(map[string]*sealedTxn),
+		clients: make(map[string]*Client),
+		storage: newStorageSC(),
+	}
+	c.blocks = []*Block{newBlock(nil, c.minerID, 0, int64(common.Now()), c.rand.Int63(), nil)}
+	return c, nil
+}
+
+// Config returns the configuration of the chain.
+func (c *Chain) Config() Config {
+	return c.cfg
+}
+
+// Run advances a round every interval until the context is done.
+func (c *Chain) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.AdvanceRound()
+		}
+	}
+}
+
+// Submit checks the transaction, as a miner would, and queues it for the
+// next round.
+func (c *Chain) Submit(t *transaction.Transaction) error {
+	if t.ChainID != c.cfg.ChainID {
+		return common.NewErrorf("invalid_chain", "transaction of chain %v", t.ChainID)
+	}
+	if int64(common.Now())-t.CreationDate > txnExpiration {
+		return common.NewError("stale_transaction", "the transaction expired")
+	}
+	if clientID(t.PublicKey) != t.ClientID {
+		return common.NewError("invalid_client", "the public key is not the one of the client")
+	}
+	ok, err := t.VerifyTransaction(c.verifySignature)
+	if err != nil {
+		return common.NewError("invalid_transaction", err.Error())
+	}
+	if !ok {
+		return common.NewError("invalid_signature", "the signature does not match the public key")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.txns[t.Hash]; ok {
+		return common.NewErrorf("duplicate_transaction", "transaction %v is already sealed", t.Hash)
+	}
+	if t.TransactionNonce <= c.client(t.ClientID).Nonce {
+		return common.NewErrorf("invalid_nonce", "nonce %v is already used", t.TransactionNonce)
+	}
+	for _, p := range c.pending {
+		if p.Hash == t.Hash || (p.ClientID == t.ClientID && p.TransactionNonce == t.TransactionNonce) {
+			return common.NewErrorf("invalid_nonce", "a transaction with nonce %v is pending", t.TransactionNonce)
+		}
+	}
+	c.pending = append(c.pending, t)
+	return nil
+}
+
+// AdvanceRound seals the pending transactions in the block of the next round.
+func (c *Chain) AdvanceRound() *BlockHeader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := c.seal().BlockHeader
+	return &header
+}
+
+// seal executes the pending transactions, and the given ones, and seals them
+// in the block of the next round.
+func (c *Chain) seal(txns ...*transaction.Transaction) *Block {
+	prev := c.blocks[len(c.blocks)-1]
+	round := prev.Round + 1
+
+	pending := c.pending
+	c.pending = nil
+	// the transactions of a client are executed in the order of their nonces
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].TransactionNonce < pending[j].TransactionNonce
+	})
+	txns = append(pending, txns...)
+	for _, t := range txns {
+		c.execute(t, round)
+	}
+
+	b := newBlock(prev, c.minerID, round, int64(common.Now()), c.rand.Int63(), txns)
+	c.blocks = append(c.blocks, b)
+	for i, t := range txns {
+		c.txns[t.Hash] = &sealedTxn{block: b, index: i}
+	}
+	return b
+}
+
+func (c *Chain) execute(t *transaction.Transaction, round int64) {
+	if cl := c.client(t.ClientID); t.TransactionNonce > cl.Nonce {
+		cl.Nonce = t.TransactionNonce
+	}
+
+	output, err := c.apply(t, round)
+	if err != nil {
+		t.Status = transaction.TxnChargeableError
+		t.TransactionOutput = err.Error()
+	} else {
+		t.Status = transaction.TxnSuccess
+		t.TransactionOutput = output
+	}
+	t.OutputHash = encryption.Hash(t.TransactionOutput)
+
+	logging.Logger.Info("[devchain]transaction",
+		zap.String("hash", t.Hash),
+		zap.String("client_id", t.ClientID),
+		zap.Int64("round", round),
+		zap.Int("status", t.Status),
+		zap.String("output", t.TransactionOutput))
+}
+
+func (c *Chain) apply(t *transaction.Transaction, round int64) (string, error) {
+	switch t.TransactionType {
+	case transaction.TxnTypeSend:
+		if err := c.transfer(t.ClientID, t.ToClientID, t.Value); err != nil {
+			return "", err
+		}
+		return "transfer successful", nil
+
+	case transaction.TxnTypeSmartContract:
+		var data struct {
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		}
+		if err := json.Unmarshal([]byte(t.TransactionData), &data); err != nil {
+			return "", common.NewError("invalid_transaction_data", err.Error())
+		}
+		if err := c.transfer(t.ClientID, t.ToClientID, t.Value); err != nil {
+			return "", err
+		}
+		switch t.ToClientID {
+		case StorageSCAddress:
+			return c.executeStorageSC(t, data.Name, data.Input, round)
+		case FaucetSCAddress:
+			if data.Name != "pour" {
+				return "", common.NewErrorf("invalid_method", "unknown method %v of the faucet", data.Name)
+			}
+			c.client(t.ClientID).Balance += c.cfg.FaucetPour
+			return fmt.Sprintf("%v poured to %v", c.cfg.FaucetPour, t.ClientID), nil
+		}
+		return "", common.NewErrorf("invalid_smart_contract", "unknown smart contract %v", t.ToClientID)
+	}
+	return "", common.NewErrorf("invalid_transaction_type", "unsupported transaction type %v", t.TransactionType)
+}
+
+func (c *Chain) transfer(from, to string, value uint64) error {
+	if value == 0 {
+		return nil
+	}
+	sender := c.client(from)
+	if sender.Balance < value {
+		return common.NewErrorf("insufficient_balance", "balance %v of %v is lower than %v", sender.Balance, from, value)
+	}
+	sender.Balance -= value
+	c.client(to).Balance += value
+	return nil
+}
+
+func (c *Chain) client(id string) *Client {
+	cl, ok := c.clients[id]
+	if !ok {
+		cl = &Client{}
+		c.clients[id] = cl
+	}
+	return cl
+}
+
+// clientID returns the id of the client with the public key.
+func clientID(publicKey string) string {
+	b, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return ""
+	}
+	return encryption.Hash(b)
+}
+
+func (c *Chain) verifySignature(publicKey, signature, hash string) (bool, error) {
+	scheme := zcncrypto.NewSignatureScheme(c.cfg.SignatureScheme)
+	if err := scheme.SetPublicKey(publicKey); err != nil {
+		return false, err
+	}
+	return scheme.Verify(signature, hash)
+}
+
+// FundClient adds tokens to the balance of the client.
+func (c *Chain) FundClient(clientID string, tokens uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client(clientID).Balance += tokens
+}
+
+// Client returns the account of the client as of the current round.
+func (c *Chain) Client(clientID string) *Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cl := Client{Round: c.blocks[len(c.blocks)-1].Round}
+	if known, ok := c.clients[clientID]; ok {
+		cl.Balance, cl.Nonce = known.Balance, known.Nonce
+	}
+	return &cl
+}
+
+// CurrentRound returns the round of the latest block.
+func (c *Chain) CurrentRound() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.blocks[len(c.blocks)-1].Round
+}
+
+// LatestBlock returns the header of the latest block.
+func (c *Chain) LatestBlock() *BlockHeader {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	header := c.blocks[len(c.blocks)-1].BlockHeader
+	return &header
+}
+
+// Block returns the header of the block of the round.
+func (c *Chain) Block(round int64) (*BlockHeader, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if round < 0 || round >= int64(len(c.blocks)) {
+		return nil, false
+	}
+	header := c.blocks[round].BlockHeader
+	return &header, true
+}
+
+// Confirmation returns the confirmation of the transaction, once it is sealed
+// in a block.
+func (c *Chain) Confirmation(hash string) (*Confirmation, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	st, ok := c.txns[hash]
+	if !ok {
+		return nil, false
+	}
+	b, t := st.block, st.block.Txns[st.index]
+	return &Confirmation{
+		Version:               b.Version,
+		Hash:                  encryption.Hash(t.Hash + ":" + b.Hash),
+		BlockHash:             b.Hash,
+		PreviousBlockHash:     b.PrevHash,
+		Transaction:           t,
+		CreationDate:          b.CreationDate,
+		MinerID:               b.MinerID,
+		Round:                 b.Round,
+		Status:                t.Status,
+		RoundRandomSeed:       b.RoundRandomSeed,
+		StateChangesCount:     b.StateChangesCount,
+		MerkleTreeRoot:        b.MerkleTreeRoot,
+		MerkleTreePath:        b.txnPaths[st.index],
+		ReceiptMerkleTreeRoot: b.ReceiptMerkleTreeRoot,
+		ReceiptMerkleTreePath: b.receiptPaths[st.index],
+	}, true
+}
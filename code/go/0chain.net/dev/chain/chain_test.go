@@ -0,0 +1,302 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	sctxn "github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/util"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logging.Logger = zap.NewNop()
+}
+
+type testClient struct {
+	*zcncrypto.Wallet
+	nonce int64
+}
+
+func newTestClient(t *testing.T) *testClient {
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	return &testClient{Wallet: w}
+}
+
+func (tc *testClient) sign(t *testing.T, hashData string) string {
+	sig, err := tc.Wallet.Sign(encryption.Hash(hashData), "bls0chain")
+	require.NoError(t, err)
+	return sig
+}
+
+// execute submits the smart contract transaction of the client and seals it.
+func (tc *testClient) execute(t *testing.T, c *Chain, to, name string, input interface{}, value uint64) *transaction.Transaction {
+	data, err := json.Marshal(&transaction.SmartContractTxnData{Name: name, InputArgs: input})
+	require.NoError(t, err)
+	tc.nonce++
+	txn := &transaction.Transaction{
+		Version:          "1.0",
+		ClientID:         tc.ClientID,
+		PublicKey:        tc.ClientKey,
+		ToClientID:       to,
+		ChainID:          c.Config().ChainID,
+		TransactionData:  string(data),
+		Value:            value,
+		CreationDate:     int64(common.Now()),
+		TransactionType:  transaction.TxnTypeSmartContract,
+		TransactionNonce: tc.nonce,
+	}
+	require.NoError(t, txn.ComputeHashAndSign(func(hash string) (string, error) {
+		return tc.Wallet.Sign(hash, "bls0chain")
+	}))
+	require.NoError(t, c.Submit(txn))
+	c.AdvanceRound()
+
+	confirmation, ok := c.Confirmation(txn.Hash)
+	require.True(t, ok)
+	return confirmation.Transaction
+}
+
+func newTestChain(t *testing.T) *Chain {
+	c, err := New(DefaultConfig())
+	require.NoError(t, err)
+	return c
+}
+
+func TestChain_Confirmation(t *testing.T) {
+	c := newTestChain(t)
+	client := newTestClient(t)
+
+	var hashes []string
+	for i := 0; i < 3; i++ {
+		client.nonce++
+		txn := &transaction.Transaction{
+			Version:          "1.0",
+			ClientID:         client.ClientID,
+			PublicKey:        client.ClientKey,
+			ToClientID:       FaucetSCAddress,
+			ChainID:          c.Config().ChainID,
+			TransactionData:  `{"name":"pour","input":{}}`,
+			CreationDate:     int64(common.Now()),
+			TransactionType:  transaction.TxnTypeSmartContract,
+			TransactionNonce: client.nonce,
+		}
+		require.NoError(t, txn.ComputeHashAndSign(func(hash string) (string, error) {
+			return client.Wallet.Sign(hash, "bls0chain")
+		}))
+		require.NoError(t, c.Submit(txn))
+		hashes = append(hashes, txn.Hash)
+	}
+	header := c.AdvanceRound()
+	require.EqualValues(t, 1, header.Round)
+	require.EqualValues(t, 3, header.NumTxns)
+
+	for _, hash := range hashes {
+		confirmation, ok := c.Confirmation(hash)
+		require.True(t, ok)
+		require.Equal(t, transaction.TxnSuccess, confirmation.Status)
+		require.True(t, util.VerifyMerklePath(hash, confirmation.MerkleTreePath, confirmation.MerkleTreeRoot))
+		receipt := transaction.NewTransactionReceipt(confirmation.Transaction).GetHash()
+		require.True(t, util.VerifyMerklePath(receipt, confirmation.ReceiptMerkleTreePath, confirmation.ReceiptMerkleTreeRoot))
+		require.Equal(t, header.Hash, confirmation.BlockHash)
+	}
+	require.Equal(t, encryption.Hash(fmt.Sprintf("%v:%v:%v:%v:%v:%v:%v:%v", header.MinerID, header.PrevHash,
+		header.CreationDate, header.Round, header.RoundRandomSeed, header.StateChangesCount,
+		header.MerkleTreeRoot, header.ReceiptMerkleTreeRoot)), header.Hash)
+
+	balance := c.Client(client.ClientID)
+	require.Equal(t, 3*c.Config().FaucetPour, balance.Balance)
+	require.EqualValues(t, 3, balance.Nonce)
+}
+
+func TestChain_Submit(t *testing.T) {
+	c := newTestChain(t)
+	client := newTestClient(t)
+	other := newTestClient(t)
+
+	newTxn := func(nonce int64) *transaction.Transaction {
+		txn := &transaction.Transaction{
+			Version:          "1.0",
+			ClientID:         client.ClientID,
+			PublicKey:        client.ClientKey,
+			ToClientID:       other.ClientID,
+			ChainID:          c.Config().ChainID,
+			CreationDate:     int64(common.Now()),
+			TransactionType:  transaction.TxnTypeSend,
+			TransactionNonce: nonce,
+		}
+		require.NoError(t, txn.ComputeHashAndSign(func(hash string) (string, error) {
+			return client.Wallet.Sign(hash, "bls0chain")
+		}))
+		return txn
+	}
+
+	txn := newTxn(1)
+	txn.ChainID = "other"
+	require.Error(t, c.Submit(txn), "transaction of another chain")
+
+	txn = newTxn(1)
+	txn.ClientID = other.ClientID
+	require.Error(t, c.Submit(txn), "public key of another client")
+
+	txn = newTxn(1)
+	txn.Value = 1
+	require.Error(t, c.Submit(txn), "hash does not match")
+
+	txn = newTxn(1)
+	require.NoError(t, c.Submit(txn))
+	require.Error(t, c.Submit(newTxn(1)), "nonce pending")
+	c.AdvanceRound()
+	require.Error(t, c.Submit(txn), "already sealed")
+	require.Error(t, c.Submit(newTxn(1)), "nonce used")
+	require.NoError(t, c.Submit(newTxn(2)))
+}
+
+func TestChain_Storage(t *testing.T) {
+	c := newTestChain(t)
+	owner := newTestClient(t)
+
+	var blobbers, validators []*testClient
+	for i := 0; i < 2; i++ {
+		b := newTestClient(t)
+		txn := b.execute(t, c, StorageSCAddress, sctxn.ADD_BLOBBER_SC_NAME, &sctxn.StorageNode{
+			ID:       b.ClientID,
+			BaseURL:  fmt.Sprintf("http://blobber%v", i),
+			Terms:    sctxn.Terms{ReadPrice: 1e10, WritePrice: 1e10},
+			Capacity: 1 << 30,
+		}, 0)
+		require.Equal(t, transaction.TxnSuccess, txn.Status, txn.TransactionOutput)
+		blobbers = append(blobbers, b)
+
+		v := newTestClient(t)
+		txn = v.execute(t, c, StorageSCAddress, sctxn.ADD_VALIDATOR_SC_NAME, &sctxn.StorageNode{
+			ID:      v.ClientID,
+			BaseURL: fmt.Sprintf("http://validator%v", i),
+		}, 0)
+		require.Equal(t, transaction.TxnSuccess, txn.Status, txn.TransactionOutput)
+		validators = append(validators, v)
+	}
+
+	alloc, err := c.CreateAllocation(&AllocationRequest{
+		OwnerID:        owner.ClientID,
+		OwnerPublicKey: owner.ClientKey,
+		Size:           1 << 20,
+		DataShards:     1,
+		ParityShards:   1,
+	})
+	require.NoError(t, err)
+	require.Len(t, alloc.BlobberDetails, 2)
+	confirmation, ok := c.Confirmation(alloc.Tx)
+	require.True(t, ok, "the validators verify the allocation by its transaction")
+	var output sctxn.StorageAllocation
+	require.NoError(t, json.Unmarshal([]byte(confirmation.Transaction.TransactionOutput), &output))
+	require.Equal(t, alloc.ID, output.ID)
+
+	blobber := blobbers[0]
+	commit := func(wm *WriteMarker, prevRoot string) *transaction.Transaction {
+		wm.Signature = owner.sign(t, wm.hashData())
+		return blobber.execute(t, c, StorageSCAddress, sctxn.CLOSE_CONNECTION_SC_NAME, map[string]interface{}{
+			"allocation_root":      wm.AllocationRoot,
+			"prev_allocation_root": prevRoot,
+			"write_marker":         wm,
+		}, 0)
+	}
+	wm := &WriteMarker{
+		AllocationRoot: "root1",
+		AllocationID:   alloc.ID,
+		Size:           1024,
+		BlobberID:      blobber.ClientID,
+		ClientID:       owner.ClientID,
+		Timestamp:      common.Now(),
+	}
+	txn := commit(wm, "")
+	require.Equal(t, transaction.TxnSuccess, txn.Status, txn.TransactionOutput)
+
+	wm = &WriteMarker{
+		AllocationRoot:         "root2",
+		PreviousAllocationRoot: "other",
+		AllocationID:           alloc.ID,
+		Size:                   1024,
+		BlobberID:              blobber.ClientID,
+		ClientID:               owner.ClientID,
+		Timestamp:              common.Now(),
+	}
+	txn = commit(wm, "root1")
+	require.Equal(t, transaction.TxnChargeableError, txn.Status, "the previous root is not the current one")
+
+	wm.PreviousAllocationRoot = "root1"
+	wm.Size = 1 << 20
+	txn = commit(wm, "root1")
+	require.Equal(t, transaction.TxnChargeableError, txn.Status, "above the allocation size")
+
+	_, err = c.IssueChallenge(alloc.ID, blobbers[1].ClientID)
+	require.Error(t, err, "the blobber stores no data")
+	ch, err := c.IssueChallenge(alloc.ID, blobber.ClientID)
+	require.NoError(t, err)
+	require.Equal(t, "root1", ch.AllocationRoot)
+	require.Len(t, ch.Validators, 2)
+
+	open := c.OpenChallenges(blobber.ClientID, 0, 20)
+	require.Len(t, open, 1)
+	require.Empty(t, c.OpenChallenges(blobber.ClientID, ch.RoundCreatedAt, 20))
+
+	var tickets []*ValidationTicket
+	for _, v := range validators {
+		vt := &ValidationTicket{
+			ChallengeID:  ch.ID,
+			BlobberID:    blobber.ClientID,
+			ValidatorID:  v.ClientID,
+			ValidatorKey: v.ClientKey,
+			Result:       true,
+			Timestamp:    common.Now(),
+		}
+		vt.Signature = v.sign(t, vt.hashData())
+		tickets = append(tickets, vt)
+	}
+	respond := func(tickets []*ValidationTicket) *transaction.Transaction {
+		return blobber.execute(t, c, StorageSCAddress, sctxn.CHALLENGE_RESPONSE, map[string]interface{}{
+			"challenge_id":       ch.ID,
+			"validation_tickets": tickets,
+		}, 0)
+	}
+	txn = respond(tickets[:1])
+	require.Equal(t, transaction.TxnChargeableError, txn.Status, "not enough validations")
+	txn = respond(tickets)
+	require.Equal(t, transaction.TxnSuccess, txn.Status, txn.TransactionOutput)
+	ch, ok = c.Challenge(blobber.ClientID, ch.ID)
+	require.True(t, ok)
+	require.True(t, ch.Passed)
+	require.Empty(t, c.OpenChallenges(blobber.ClientID, 0, 20))
+
+	reader := newTestClient(t)
+	rm := &ReadMarker{
+		ClientID:        reader.ClientID,
+		ClientPublicKey: reader.ClientKey,
+		AllocationID:    alloc.ID,
+		BlobberID:       blobber.ClientID,
+		OwnerID:         owner.ClientID,
+		ReadCounter:     16 * 1024,
+		Timestamp:       common.Now(),
+	}
+	rm.Signature = reader.sign(t, rm.hashData())
+	redeem := map[string]interface{}{"read_marker": rm}
+	txn = blobber.execute(t, c, StorageSCAddress, sctxn.READ_REDEEM, redeem, 0)
+	require.Equal(t, transaction.TxnChargeableError, txn.Status, "empty read pool")
+
+	c.FundReadPool(reader.ClientID, 1e10)
+	txn = blobber.execute(t, c, StorageSCAddress, sctxn.READ_REDEEM, redeem, 0)
+	require.Equal(t, transaction.TxnSuccess, txn.Status, txn.TransactionOutput)
+	require.Zero(t, c.ReadPool(reader.ClientID), "a GB read at 1e10 per GB")
+	require.Equal(t, rm.ReadCounter, c.LatestReadMarker(alloc.ID, blobber.ClientID, reader.ClientID).ReadCounter)
+
+	txn = blobber.execute(t, c, StorageSCAddress, sctxn.READ_REDEEM, redeem, 0)
+	require.Equal(t, transaction.TxnChargeableError, txn.Status, "the counter is already redeemed")
+}
@@ -0,0 +1,791 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
+	sctxn "github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"github.com/0chain/gosdk/core/transaction"
+)
+
+// Methods of the storage smart contract only sent by the clients, the others
+// are named in core/transaction.
+const (
+	NewAllocationRequest = "new_allocation_request"
+	ReadPoolLock         = "read_pool_lock"
+)
+
+const (
+	// defaultAllocationDuration is the duration of the allocations created
+	// without an expiration date.
+	defaultAllocationDuration = 30 * 24 * time.Hour
+	// allocationTimeUnit is the time unit of the prices of the allocations.
+	allocationTimeUnit = 720 * time.Hour
+	// readBlockSize is the size of the blocks counted by the read markers.
+	readBlockSize = 64 * 1024
+)
+
+// Blobber is a blobber registered on the chain, as served by /getBlobber.
+type Blobber struct {
+	ID                string                  `json:"id"`
+	BaseURL           string                  `json:"url"`
+	Terms             sctxn.Terms             `json:"terms"`
+	Capacity          int64                   `json:"capacity"`
+	Allocated         int64                   `json:"allocated"`
+	LastHealthCheck   common.Timestamp        `json:"last_health_check"`
+	StakePoolSettings sctxn.StakePoolSettings `json:"stake_pool_settings"`
+	NotAvailable      bool                    `json:"not_available"`
+	IsRestricted      bool                    `json:"is_restricted"`
+	PublicKey         string                  `json:"-"`
+}
+
+// Validator is a validator registered on the chain, as served by
+// /get_validator.
+type Validator struct {
+	ID              string           `json:"validator_id"`
+	BaseURL         string           `json:"url"`
+	DelegateWallet  string           `json:"delegate_wallet"`
+	NumDelegates    int              `json:"num_delegates"`
+	ServiceCharge   float64          `json:"service_charge"`
+	LastHealthCheck common.Timestamp `json:"last_health_check"`
+	PublicKey       string           `json:"-"`
+}
+
+// ValidationNode is a validator picked to validate a challenge.
+type ValidationNode struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// Challenge is a challenge of a blobber, as served by /openchallenges and
+// /getchallenge.
+type Challenge struct {
+	ID             string            `json:"id"`
+	PrevID         string            `json:"prev_id"`
+	Seed           int64             `json:"seed"`
+	AllocationID   string            `json:"allocation_id"`
+	AllocationRoot string            `json:"allocation_root"`
+	BlobberID      string            `json:"blobber_id"`
+	Validators     []*ValidationNode `json:"validators"`
+	RoundCreatedAt int64             `json:"round_created_at"`
+	Created        common.Timestamp  `json:"created"`
+	Timestamp      common.Timestamp  `json:"timestamp"`
+	// Responded is set once the blobber answered the challenge, and Passed
+	// when enough validators validated its answer.
+	Responded bool `json:"responded"`
+	Passed    bool `json:"passed"`
+}
+
+// AllocationRequest is the input of new_allocation_request.
+type AllocationRequest struct {
+	OwnerID        string `json:"owner_id"`
+	OwnerPublicKey string `json:"owner_public_key"`
+	Size           int64  `json:"size"`
+	DataShards     int64  `json:"data_shards"`
+	ParityShards   int64  `json:"parity_shards"`
+	// Expiration is the time the allocation expires, 30 days after its
+	// creation when not set.
+	Expiration common.Timestamp `json:"expiration_date"`
+	// Blobbers are the ids of the blobbers of the allocation. The blobbers are
+	// picked from the registered ones when not set.
+	Blobbers    []string `json:"blobbers"`
+	FileOptions uint16   `json:"file_options"`
+}
+
+// WriteMarker is the write marker committed by a blobber, signed by the owner
+// of the allocation.
+type WriteMarker struct {
+	Version                string           `json:"version"`
+	AllocationRoot         string           `json:"allocation_root"`
+	PreviousAllocationRoot string           `json:"prev_allocation_root"`
+	FileMetaRoot           string           `json:"file_meta_root"`
+	AllocationID           string           `json:"allocation_id"`
+	Size                   int64            `json:"size"`
+	ChainSize              int64            `json:"chain_size"`
+	ChainHash              string           `json:"chain_hash"`
+	ChainLength            int              `json:"chain_length"`
+	BlobberID              string           `json:"blobber_id"`
+	Timestamp              common.Timestamp `json:"timestamp"`
+	ClientID               string           `json:"client_id"`
+	Signature              string           `json:"signature"`
+}
+
+func (wm *WriteMarker) hashData() string {
+	return fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s:%d:%d:%d",
+		wm.AllocationRoot, wm.PreviousAllocationRoot,
+		wm.FileMetaRoot, wm.ChainHash, wm.AllocationID, wm.BlobberID,
+		wm.ClientID, wm.Size, wm.ChainSize, wm.Timestamp)
+}
+
+// ReadMarker is the read marker redeemed by a blobber, signed by the client
+// which read.
+type ReadMarker struct {
+	ClientID        string           `json:"client_id"`
+	AllocationID    string           `json:"allocation_id"`
+	ClientPublicKey string           `json:"client_public_key"`
+	BlobberID       string           `json:"blobber_id"`
+	OwnerID         string           `json:"owner_id"`
+	Timestamp       common.Timestamp `json:"timestamp"`
+	ReadCounter     int64            `json:"counter"`
+	Signature       string           `json:"signature"`
+	SessionRC       int64            `json:"session_rc"`
+}
+
+func (rm *ReadMarker) hashData() string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v:%v:%v", rm.AllocationID,
+		rm.BlobberID, rm.ClientID, rm.ClientPublicKey, rm.OwnerID,
+		rm.ReadCounter, rm.Timestamp)
+}
+
+// ValidationTicket is the verdict of a validator on the answer of a blobber to
+// a challenge.
+type ValidationTicket struct {
+	ChallengeID  string           `json:"challenge_id"`
+	BlobberID    string           `json:"blobber_id"`
+	ValidatorID  string           `json:"validator_id"`
+	ValidatorKey string           `json:"validator_key"`
+	Result       bool             `json:"success"`
+	Message      string           `json:"message"`
+	MessageCode  string           `json:"message_code"`
+	Timestamp    common.Timestamp `json:"timestamp"`
+	Signature    string           `json:"signature"`
+}
+
+func (vt *ValidationTicket) hashData() string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v:%v", vt.ChallengeID, vt.BlobberID, vt.ValidatorID, vt.ValidatorKey, vt.Result, vt.Timestamp)
+}
+
+type allocation struct {
+	*sctxn.StorageAllocation
+	// used is the size written on each blobber.
+	used map[string]int64
+	// readMarkers are the latest read markers redeemed, by blobber and client.
+	readMarkers map[string]*ReadMarker
+}
+
+func (a *allocation) details(blobberID string) *sctxn.BlobberAllocation {
+	for _, d := range a.BlobberDetails {
+		if d.BlobberID == blobberID {
+			return d
+		}
+	}
+	return nil
+}
+
+// blobberSize is the size of the allocation on each of its blobbers.
+func (a *allocation) blobberSize() int64 {
+	return int64(math.Ceil(float64(a.Size) / float64(a.DataShards)))
+}
+
+func (a *allocation) copy() *sctxn.StorageAllocation {
+	sa := *a.StorageAllocation
+	sa.BlobberDetails = make([]*sctxn.BlobberAllocation, 0, len(a.BlobberDetails))
+	for _, d := range a.BlobberDetails {
+		cp := *d
+		sa.BlobberDetails = append(sa.BlobberDetails, &cp)
+	}
+	return &sa
+}
+
+// storageSC is the state of the storage smart contract.
+type storageSC struct {
+	blobbers    map[string]*Blobber
+	validators  map[string]*Validator
+	allocations map[string]*allocation
+	challenges  map[string]*Challenge
+	// blobberChallenges are the challenges of each blobber, oldest first.
+	blobberChallenges map[string][]*Challenge
+	readPools         map[string]uint64
+}
+
+func newStorageSC() *storageSC {
+	return &storageSC{
+		blobbers:          make(map[string]*Blobber),
+		validators:        make(map[string]*Validator),
+		allocations:       make(map[string]*allocation),
+		challenges:        make(map[string]*Challenge),
+		blobberChallenges: make(map[string][]*Challenge),
+		readPools:         make(map[string]uint64),
+	}
+}
+
+func (c *Chain) executeStorageSC(t *transaction.Transaction, method string, input json.RawMessage, round int64) (string, error) {
+	switch method {
+	case sctxn.ADD_BLOBBER_SC_NAME, sctxn.UPDATE_BLOBBER_SC_NAME:
+		return c.addBlobber(t, method, input)
+	case sctxn.ADD_VALIDATOR_SC_NAME:
+		return c.addValidator(t, input)
+	case sctxn.BLOBBER_HEALTH_CHECK:
+		b, ok := c.storage.blobbers[t.ClientID]
+		if !ok {
+			return "", common.NewError("blobber_health_check_failed", "the blobber is not registered")
+		}
+		b.LastHealthCheck = common.Now()
+		return "blobber health check successful", nil
+	case sctxn.VALIDATOR_HEALTH_CHECK:
+		v, ok := c.storage.validators[t.ClientID]
+		if !ok {
+			return "", common.NewError("validator_health_check_failed", "the validator is not registered")
+		}
+		v.LastHealthCheck = common.Now()
+		return "validator health check successful", nil
+	case NewAllocationRequest:
+		return c.newAllocation(t, input)
+	case sctxn.CLOSE_CONNECTION_SC_NAME:
+		return c.commitConnection(t, input)
+	case sctxn.READ_REDEEM:
+		return c.redeemReadMarker(t, input)
+	case sctxn.CHALLENGE_RESPONSE:
+		return c.respondChallenge(t, input, round)
+	case sctxn.FINALIZE_ALLOCATION:
+		return c.finalizeAllocation(t, input)
+	case ReadPoolLock:
+		c.client(StorageSCAddress).Balance -= t.Value
+		c.storage.readPools[t.ClientID] += t.Value
+		return fmt.Sprintf("%v locked in the read pool", t.Value), nil
+	}
+	return "", common.NewErrorf("invalid_method", "unknown method %v of the storage smart contract", method)
+}
+
+func (c *Chain) addBlobber(t *transaction.Transaction, method string, input json.RawMessage) (string, error) {
+	var sn sctxn.StorageNode
+	if err := json.Unmarshal(input, &sn); err != nil {
+		return "", common.NewError("invalid_blobber", err.Error())
+	}
+	if sn.ID != t.ClientID {
+		return "", common.NewError("invalid_blobber", "a blobber can only be registered by itself")
+	}
+	if sn.BaseURL == "" || sn.Capacity <= 0 {
+		return "", common.NewError("invalid_blobber", "the url and the capacity of the blobber are required")
+	}
+
+	b, ok := c.storage.blobbers[sn.ID]
+	if !ok {
+		if method == sctxn.UPDATE_BLOBBER_SC_NAME {
+			return "", common.NewError("invalid_blobber", "the blobber is not registered")
+		}
+		b = &Blobber{ID: sn.ID, PublicKey: t.PublicKey}
+		c.storage.blobbers[sn.ID] = b
+	}
+	b.BaseURL = sn.BaseURL
+	b.Terms = sn.Terms
+	b.Capacity = sn.Capacity
+	b.StakePoolSettings = sn.StakePoolSettings
+	b.LastHealthCheck = common.Now()
+	return marshal(b)
+}
+
+func (c *Chain) addValidator(t *transaction.Transaction, input json.RawMessage) (string, error) {
+	var sn sctxn.StorageNode
+	if err := json.Unmarshal(input, &sn); err != nil {
+		return "", common.NewError("invalid_validator", err.Error())
+	}
+	if sn.ID != t.ClientID {
+		return "", common.NewError("invalid_validator", "a validator can only be registered by itself")
+	}
+	if sn.BaseURL == "" {
+		return "", common.NewError("invalid_validator", "the url of the validator is required")
+	}
+
+	v := &Validator{
+		ID:              sn.ID,
+		BaseURL:         sn.BaseURL,
+		DelegateWallet:  sn.StakePoolSettings.DelegateWallet,
+		NumDelegates:    sn.StakePoolSettings.NumDelegates,
+		ServiceCharge:   sn.StakePoolSettings.ServiceCharge,
+		LastHealthCheck: common.Now(),
+		PublicKey:       t.PublicKey,
+	}
+	c.storage.validators[sn.ID] = v
+	return marshal(v)
+}
+
+func (c *Chain) newAllocation(t *transaction.Transaction, input json.RawMessage) (string, error) {
+	var req AllocationRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return "", common.NewError("invalid_allocation_request", err.Error())
+	}
+	if req.OwnerID == "" {
+		req.OwnerID, req.OwnerPublicKey = t.ClientID, t.PublicKey
+	}
+	if req.OwnerPublicKey == "" {
+		return "", common.NewError("invalid_allocation_request", "the public key of the owner is required")
+	}
+	if req.DataShards <= 0 || req.ParityShards < 0 || req.Size <= 0 {
+		return "", common.NewError("invalid_allocation_request", "the size and the data shards must be positive")
+	}
+	now := common.Now()
+	if req.Expiration == 0 {
+		req.Expiration = common.Timestamp(common.ToTime(now).Add(defaultAllocationDuration).Unix())
+	}
+	if req.Expiration <= now {
+		return "", common.NewError("invalid_allocation_request", "the allocation would already be expired")
+	}
+
+	n := int(req.DataShards + req.ParityShards)
+	if len(req.Blobbers) == 0 {
+		ids := make([]string, 0, len(c.storage.blobbers))
+		for id := range c.storage.blobbers {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if len(ids) > n {
+			ids = ids[:n]
+		}
+		req.Blobbers = ids
+	}
+	if len(req.Blobbers) != n {
+		return "", common.NewErrorf("not_enough_blobbers", "%v blobbers for %v shards", len(req.Blobbers), n)
+	}
+
+	a := &allocation{
+		StorageAllocation: &sctxn.StorageAllocation{
+			ID:             t.Hash,
+			Tx:             t.Hash,
+			OwnerID:        req.OwnerID,
+			OwnerPublicKey: req.OwnerPublicKey,
+			Size:           req.Size,
+			Expiration:     req.Expiration,
+			TimeUnit:       allocationTimeUnit,
+			WritePool:      t.Value,
+			FileOptions:    req.FileOptions,
+			StartTime:      now,
+			DataShards:     req.DataShards,
+			ParityShards:   req.ParityShards,
+		},
+		used:        make(map[string]int64),
+		readMarkers: make(map[string]*ReadMarker),
+	}
+	size := a.blobberSize()
+	for _, id := range req.Blobbers {
+		b, ok := c.storage.blobbers[id]
+		if !ok {
+			return "", common.NewErrorf("invalid_allocation_request", "blobber %v is not registered", id)
+		}
+		if a.details(id) != nil {
+			return "", common.NewErrorf("invalid_allocation_request", "blobber %v is picked twice", id)
+		}
+		if b.Allocated+size > b.Capacity {
+			return "", common.NewErrorf("not_enough_capacity", "blobber %v has %v left for %v", id, b.Capacity-b.Allocated, size)
+		}
+		a.BlobberDetails = append(a.BlobberDetails, &sctxn.BlobberAllocation{BlobberID: id, Terms: b.Terms})
+	}
+	for _, id := range req.Blobbers {
+		c.storage.blobbers[id].Allocated += size
+	}
+	c.storage.allocations[a.ID] = a
+	return marshal(a.StorageAllocation)
+}
+
+func (c *Chain) commitConnection(t *transaction.Transaction, input json.RawMessage) (string, error) {
+	var cc struct {
+		AllocationRoot     string       `json:"allocation_root"`
+		PrevAllocationRoot string       `json:"prev_allocation_root"`
+		WriteMarker        *WriteMarker `json:"write_marker"`
+	}
+	if err := json.Unmarshal(input, &cc); err != nil || cc.WriteMarker == nil {
+		return "", common.NewError("invalid_write_marker", "the write marker is missing or malformed")
+	}
+	wm := cc.WriteMarker
+
+	a, d, err := c.blobberAllocation(wm.AllocationID, t.ClientID)
+	if err != nil {
+		return "", err
+	}
+	if wm.BlobberID != t.ClientID || wm.AllocationRoot != cc.AllocationRoot {
+		return "", common.NewError("invalid_write_marker", "the write marker does not match the commit")
+	}
+	if wm.ClientID != a.OwnerID {
+		return "", common.NewError("invalid_write_marker", "the write marker is not signed by the owner")
+	}
+	if ok, err := c.verifySignature(a.OwnerPublicKey, wm.Signature, encryption.Hash(wm.hashData())); err != nil || !ok {
+		return "", common.NewError("invalid_write_marker", "the signature of the write marker does not match")
+	}
+	// a rollback commits a marker of a previous allocation root
+	if cc.AllocationRoot != cc.PrevAllocationRoot && wm.PreviousAllocationRoot != d.AllocationRoot {
+		return "", common.NewErrorf("invalid_write_marker", "the previous allocation root %v is not the current one %v",
+			wm.PreviousAllocationRoot, d.AllocationRoot)
+	}
+	used := a.used[t.ClientID] + wm.Size
+	if used > a.blobberSize() {
+		return "", common.NewErrorf("max_allocation_size", "%v written on the blobber, above the allocation size %v", used, a.blobberSize())
+	}
+
+	d.AllocationRoot = wm.AllocationRoot
+	a.used[t.ClientID] = used
+	a.UsedSize += wm.Size
+	return fmt.Sprintf("allocation root %v committed", wm.AllocationRoot), nil
+}
+
+func (c *Chain) redeemReadMarker(t *transaction.Transaction, input json.RawMessage) (string, error) {
+	var rr struct {
+		ReadMarker *ReadMarker `json:"read_marker"`
+	}
+	if err := json.Unmarshal(input, &rr); err != nil || rr.ReadMarker == nil {
+		return "", common.NewError("invalid_read_marker", "the read marker is missing or malformed")
+	}
+	rm := rr.ReadMarker
+
+	a, d, err := c.blobberAllocation(rm.AllocationID, t.ClientID)
+	if err != nil {
+		return "", err
+	}
+	if rm.BlobberID != t.ClientID || rm.OwnerID != a.OwnerID {
+		return "", common.NewError("invalid_read_marker", "the read marker does not match the allocation")
+	}
+	if clientID(rm.ClientPublicKey) != rm.ClientID {
+		return "", common.NewError("invalid_read_marker", "the public key is not the one of the client")
+	}
+	if ok, err := c.verifySignature(rm.ClientPublicKey, rm.Signature, encryption.Hash(rm.hashData())); err != nil || !ok {
+		return "", common.NewError("invalid_read_marker", "the signature of the read marker does not match")
+	}
+
+	key := rm.BlobberID + ":" + rm.ClientID
+	var redeemed int64
+	if latest, ok := a.readMarkers[key]; ok {
+		redeemed = latest.ReadCounter
+	}
+	if rm.ReadCounter <= redeemed {
+		return "", common.NewErrorf("invalid_read_marker", "the counter %v is not above the redeemed %v", rm.ReadCounter, redeemed)
+	}
+	blocks := rm.ReadCounter - redeemed
+	cost := uint64(float64(blocks*readBlockSize) / float64(1024*1024*1024) * float64(d.Terms.ReadPrice))
+	if pool := c.storage.readPools[rm.ClientID]; pool < cost {
+		return "", common.NewErrorf("not_enough_tokens", "the read pool of %v has %v, %v are due", rm.ClientID, pool, cost)
+	}
+
+	c.storage.readPools[rm.ClientID] -= cost
+	c.client(t.ClientID).Balance += cost
+	cp := *rm
+	a.readMarkers[key] = &cp
+	return fmt.Sprintf("%v blocks redeemed for %v", blocks, cost), nil
+}
+
+func (c *Chain) respondChallenge(t *transaction.Transaction, input json.RawMessage, round int64) (string, error) {
+	var cr struct {
+		ChallengeID       string              `json:"challenge_id"`
+		ValidationTickets []*ValidationTicket `json:"validation_tickets"`
+	}
+	if err := json.Unmarshal(input, &cr); err != nil {
+		return "", common.NewError("invalid_challenge_response", err.Error())
+	}
+
+	ch, ok := c.storage.challenges[cr.ChallengeID]
+	if !ok || ch.BlobberID != t.ClientID {
+		return "", common.NewErrorf("invalid_challenge", "challenge %v of the blobber not found", cr.ChallengeID)
+	}
+	if ch.Responded {
+		return "", common.NewError("invalid_challenge", "the challenge is already answered")
+	}
+	if round-ch.RoundCreatedAt > c.cfg.ChallengeCompletionRounds {
+		return "", common.NewError("challenge_expired", "the challenge expired")
+	}
+
+	picked := make(map[string]bool, len(ch.Validators))
+	for _, v := range ch.Validators {
+		picked[v.ID] = true
+	}
+	var success, failure int
+	for _, vt := range cr.ValidationTickets {
+		if vt == nil {
+			continue
+		}
+		if vt.ChallengeID != ch.ID || vt.BlobberID != ch.BlobberID || !picked[vt.ValidatorID] {
+			return "", common.NewErrorf("invalid_validation_ticket", "ticket of validator %v is not for the challenge", vt.ValidatorID)
+		}
+		v := c.storage.validators[vt.ValidatorID]
+		if v == nil || v.PublicKey != vt.ValidatorKey {
+			return "", common.NewErrorf("invalid_validation_ticket", "the key of validator %v does not match", vt.ValidatorID)
+		}
+		if ok, err := c.verifySignature(vt.ValidatorKey, vt.Signature, encryption.Hash(vt.hashData())); err != nil || !ok {
+			return "", common.NewErrorf("invalid_validation_ticket", "the signature of validator %v does not match", vt.ValidatorID)
+		}
+		// a validator only counts once
+		delete(picked, vt.ValidatorID)
+		if vt.Result {
+			success++
+		} else {
+			failure++
+		}
+	}
+
+	required := len(ch.Validators)/2 + 1
+	switch {
+	case success >= required:
+		ch.Passed = true
+	case failure > len(ch.Validators)-required:
+		ch.Passed = false
+	default:
+		return "", common.NewErrorf("not_enough_validations", "%v successful and %v failed validations, %v are required",
+			success, failure, required)
+	}
+	ch.Responded = true
+	if ch.Passed {
+		return "challenge passed by blobber", nil
+	}
+	return "challenge failed by blobber", nil
+}
+
+func (c *Chain) finalizeAllocation(t *transaction.Transaction, input json.RawMessage) (string, error) {
+	var req struct {
+		AllocationID string `json:"allocation_id"`
+	}
+	if err := json.Unmarshal(input, &req); err != nil {
+		return "", common.NewError("invalid_finalize_request", err.Error())
+	}
+	a, ok := c.storage.allocations[req.AllocationID]
+	if !ok {
+		return "", common.NewErrorf("invalid_allocation", "allocation %v not found", req.AllocationID)
+	}
+	if t.ClientID != a.OwnerID && a.details(t.ClientID) == nil {
+		return "", common.NewError("invalid_finalize_request", "only the owner and the blobbers can finalize the allocation")
+	}
+	if a.Finalized {
+		return "", common.NewError("allocation_finalized", "the allocation is already finalized")
+	}
+	if common.Now() < a.Expiration {
+		return "", common.NewError("invalid_finalize_request", "the allocation is not expired")
+	}
+
+	a.Finalized = true
+	for _, d := range a.BlobberDetails {
+		if b, ok := c.storage.blobbers[d.BlobberID]; ok {
+			b.Allocated -= a.blobberSize()
+		}
+	}
+	return "allocation finalized", nil
+}
+
+// blobberAllocation returns the allocation, open for redemptions, and the
+// details of the blobber in it.
+func (c *Chain) blobberAllocation(allocationID, blobberID string) (*allocation, *sctxn.BlobberAllocation, error) {
+	a, ok := c.storage.allocations[allocationID]
+	if !ok {
+		return nil, nil, common.NewErrorf("invalid_allocation", "allocation %v not found", allocationID)
+	}
+	if a.Finalized {
+		return nil, nil, common.NewError("allocation_finalized", "the allocation is finalized")
+	}
+	d := a.details(blobberID)
+	if d == nil {
+		return nil, nil, common.NewErrorf("invalid_blobber", "blobber %v is not a blobber of the allocation", blobberID)
+	}
+	return a, d, nil
+}
+
+// CreateAllocation creates the allocation in the block of the next round, as
+// requested by its owner. Its id is the hash of its transaction, whose output
+// is the allocation.
+func (c *Chain) CreateAllocation(req *AllocationRequest) (*sctxn.StorageAllocation, error) {
+	data, err := json.Marshal(&transaction.SmartContractTxnData{Name: NewAllocationRequest, InputArgs: req})
+	if err != nil {
+		return nil, err
+	}
+	t := &transaction.Transaction{
+		Version:         "1.0",
+		ClientID:        req.OwnerID,
+		PublicKey:       req.OwnerPublicKey,
+		ToClientID:      StorageSCAddress,
+		ChainID:         c.cfg.ChainID,
+		TransactionData: string(data),
+		CreationDate:    int64(common.Now()),
+		TransactionType: transaction.TxnTypeSmartContract,
+	}
+	t.ComputeHashData()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seal(t)
+	if t.Status != transaction.TxnSuccess {
+		return nil, common.NewError("create_allocation_failed", t.TransactionOutput)
+	}
+	return c.storage.allocations[t.Hash].copy(), nil
+}
+
+// IssueChallenge challenges the blobber on its data of the allocation, in the
+// block of the next round.
+func (c *Chain) IssueChallenge(allocationID, blobberID string) (*Challenge, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	a, d, err := c.blobberAllocation(allocationID, blobberID)
+	if err != nil {
+		return nil, err
+	}
+	if d.AllocationRoot == "" {
+		return nil, common.NewError("no_data", "the blobber stores no data of the allocation")
+	}
+	validators := make([]*ValidationNode, 0, len(c.storage.validators))
+	for _, v := range c.storage.validators {
+		validators = append(validators, &ValidationNode{ID: v.ID, URL: v.BaseURL})
+	}
+	if len(validators) == 0 {
+		return nil, common.NewError("no_validators", "no validator is registered")
+	}
+	sort.Slice(validators, func(i, j int) bool { return validators[i].ID < validators[j].ID })
+	c.rand.Shuffle(len(validators), func(i, j int) { validators[i], validators[j] = validators[j], validators[i] })
+	if n := c.cfg.ValidatorsPerChallenge; n > 0 && len(validators) > n {
+		validators = validators[:n]
+	}
+
+	b := c.seal()
+	now := common.Now()
+	ch := &Challenge{
+		ID:             encryption.Hash(fmt.Sprintf("%v:%v:%v", b.Hash, a.ID, blobberID)),
+		Seed:           c.rand.Int63(),
+		AllocationID:   a.ID,
+		AllocationRoot: d.AllocationRoot,
+		BlobberID:      blobberID,
+		Validators:     validators,
+		RoundCreatedAt: b.Round,
+		Created:        now,
+		Timestamp:      now,
+	}
+	if prev := c.storage.blobberChallenges[blobberID]; len(prev) > 0 {
+		ch.PrevID = prev[len(prev)-1].ID
+	}
+	c.storage.challenges[ch.ID] = ch
+	c.storage.blobberChallenges[blobberID] = append(c.storage.blobberChallenges[blobberID], ch)
+	cp := *ch
+	return &cp, nil
+}
+
+// FundReadPool adds tokens to the read pool of the client.
+func (c *Chain) FundReadPool(clientID string, tokens uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.storage.readPools[clientID] += tokens
+}
+
+// ReadPool returns the tokens in the read pool of the client.
+func (c *Chain) ReadPool(clientID string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.storage.readPools[clientID]
+}
+
+// Blobber returns the registered blobber.
+func (c *Chain) Blobber(id string) (*Blobber, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	b, ok := c.storage.blobbers[id]
+	if !ok {
+		return nil, false
+	}
+	cp := *b
+	return &cp, true
+}
+
+// Validator returns the registered validator.
+func (c *Chain) Validator(id string) (*Validator, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.storage.validators[id]
+	if !ok {
+		return nil, false
+	}
+	cp := *v
+	return &cp, true
+}
+
+// Allocation returns the allocation.
+func (c *Chain) Allocation(id string) (*sctxn.StorageAllocation, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	a, ok := c.storage.allocations[id]
+	if !ok {
+		return nil, false
+	}
+	return a.copy(), true
+}
+
+// ExpiredAllocations returns the ids of the expired allocations of the
+// blobber, not finalized yet.
+func (c *Chain) ExpiredAllocations(blobberID string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := common.Now()
+	ids := make([]string, 0)
+	for id, a := range c.storage.allocations {
+		if !a.Finalized && a.Expiration <= now && a.details(blobberID) != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LatestReadMarker returns the latest read marker of the client redeemed by the
+// blobber, nil if none was.
+func (c *Chain) LatestReadMarker(allocationID, blobberID, clientID string) *ReadMarker {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	a, ok := c.storage.allocations[allocationID]
+	if !ok {
+		return nil
+	}
+	rm, ok := a.readMarkers[blobberID+":"+clientID]
+	if !ok {
+		return nil
+	}
+	cp := *rm
+	return &cp
+}
+
+// Challenge returns the challenge of the blobber.
+func (c *Chain) Challenge(blobberID, id string) (*Challenge, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ch, ok := c.storage.challenges[id]
+	if !ok || ch.BlobberID != blobberID {
+		return nil, false
+	}
+	cp := *ch
+	return &cp, true
+}
+
+// OpenChallenges returns the challenges of the blobber created after the round
+// from, it has yet to answer in time, oldest first. At most limit rounds of
+// challenges are returned, and all the challenges of a round.
+func (c *Chain) OpenChallenges(blobberID string, from int64, limit int) []*Challenge {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	round := c.blocks[len(c.blocks)-1].Round
+	open := make([]*Challenge, 0)
+	for _, ch := range c.storage.blobberChallenges[blobberID] {
+		if ch.RoundCreatedAt <= from || ch.Responded || round-ch.RoundCreatedAt > c.cfg.ChallengeCompletionRounds {
+			continue
+		}
+		if n := len(open); limit > 0 && n >= limit && open[n-1].RoundCreatedAt != ch.RoundCreatedAt {
+			break
+		}
+		cp := *ch
+		open = append(open, &cp)
+	}
+	return open
+}
+
+// StorageConfig returns the configuration of the storage smart contract, as
+// served by /storage-config.
+func (c *Chain) StorageConfig() map[string]string {
+	return map[string]string{
+		"max_challenge_completion_rounds": strconv.FormatInt(c.cfg.ChallengeCompletionRounds, 10),
+		"max_file_size":                   strconv.FormatInt(c.cfg.MaxFileSize, 10),
+		"validators_per_challenge":        strconv.Itoa(c.cfg.ValidatorsPerChallenge),
+		"time_unit":                       allocationTimeUnit.String(),
+	}
+}
+
+func marshal(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
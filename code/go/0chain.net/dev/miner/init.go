@@ -1,11 +1,51 @@
 package miner
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/dev/chain"
+	"github.com/0chain/gosdk/core/transaction"
 	"github.com/gorilla/mux"
 )
 
-func RegisterHandlers(s *mux.Router) {
-	// s.HandleFunc("/v1/file/upload/{allocation}", uploadAndUpdateFile).Methods(http.MethodPut, http.MethodPost)
-	// s.HandleFunc("/v1/file/referencepath/{allocation}", getReference).Methods(http.MethodGet)
-	// s.HandleFunc("/v1/connection/commit/{allocation}", commitWrite).Methods(http.MethodPost)
+// RegisterHandlers registers the miner APIs of the chain: the submission of
+// the transactions and their fees. The chain charges no fees.
+func RegisterHandlers(s *mux.Router, c *chain.Chain) {
+	h := &handler{chain: c}
+
+	s.HandleFunc("/v1/transaction/put", common.ToJSONResponse(h.putTransaction)).Methods(http.MethodPost)
+	s.HandleFunc("/v1/fees_table", common.ToJSONResponse(h.getFeesTable)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/block/get/fee_stats", common.ToJSONResponse(h.getFeeStats)).Methods(http.MethodGet)
+}
+
+type handler struct {
+	chain *chain.Chain
+}
+
+func (h *handler) putTransaction(ctx context.Context, r *http.Request) (interface{}, error) {
+	var t transaction.Transaction
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		return nil, common.NewError("invalid_transaction", err.Error())
+	}
+	// the submitted transaction is updated once executed
+	entity := t
+	if err := h.chain.Submit(&t); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"async": true, "entity": &entity}, nil
+}
+
+func (h *handler) getFeesTable(ctx context.Context, r *http.Request) (interface{}, error) {
+	return map[string]map[string]int64{
+		chain.StorageSCAddress: {},
+		chain.FaucetSCAddress:  {},
+		"transfer":             {"transfer": 0},
+	}, nil
+}
+
+func (h *handler) getFeeStats(ctx context.Context, r *http.Request) (interface{}, error) {
+	return map[string]int64{"max_fees": 0, "min_fees": 0, "mean_fees": 0}, nil
 }
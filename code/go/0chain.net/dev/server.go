@@ -2,11 +2,21 @@
 package dev
 
 import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/dev/chain"
 	"github.com/0chain/blobber/code/go/0chain.net/dev/miner"
 	"github.com/0chain/blobber/code/go/0chain.net/dev/sharder"
 	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 // Server a local dev server to mock server APIs
@@ -26,20 +36,125 @@ func NewServer() *Server {
 	return s
 }
 
-// NewSharderServer create a local dev sharder server
-func NewSharderServer() *Server {
+// NewSharderServer create a local dev sharder server of the chain
+func NewSharderServer(c *chain.Chain) *Server {
 	s := NewServer()
 
-	sharder.RegisterHandlers(s.Router)
+	sharder.RegisterHandlers(s.Router, c)
 
 	return s
 }
 
-// NewMinerServer create a local dev miner server
-func NewMinerServer() *Server {
+// NewMinerServer create a local dev miner server of the chain
+func NewMinerServer(c *chain.Chain) *Server {
 	s := NewServer()
 
-	miner.RegisterHandlers(s.Router)
+	miner.RegisterHandlers(s.Router, c)
 
 	return s
 }
+
+// NewChainServer create a local dev server acting as the block worker, the
+// miner and the sharder of the chain
+func NewChainServer(c *chain.Chain) *Server {
+	s := NewServer()
+
+	RegisterChainHandlers(s.Router, c, s.URL)
+
+	return s
+}
+
+// RegisterChainHandlers registers the APIs of the block worker, the miner and
+// the sharder of the chain served at url, and the APIs controlling the chain.
+func RegisterChainHandlers(r *mux.Router, c *chain.Chain, url string) {
+	r.HandleFunc("/network", common.ToJSONResponse(func(ctx context.Context, r *http.Request) (interface{}, error) {
+		return map[string][]string{"miners": {url}, "sharders": {url}}, nil
+	})).Methods(http.MethodGet)
+
+	miner.RegisterHandlers(r, c)
+	sharder.RegisterHandlers(r, c)
+
+	r.HandleFunc("/_dev/round", common.ToJSONResponse(func(ctx context.Context, r *http.Request) (interface{}, error) {
+		return c.AdvanceRound(), nil
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/_dev/allocation", common.ToJSONResponse(func(ctx context.Context, r *http.Request) (interface{}, error) {
+		var req chain.AllocationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, common.NewError("invalid_parameters", err.Error())
+		}
+		return c.CreateAllocation(&req)
+	})).Methods(http.MethodPost)
+
+	r.HandleFunc("/_dev/challenge", common.ToJSONResponse(func(ctx context.Context, r *http.Request) (interface{}, error) {
+		return c.IssueChallenge(r.FormValue("allocation"), r.FormValue("blobber"))
+	})).Methods(http.MethodPost)
+}
+
+// StartChain starts the chain on addr, its rounds advancing every interval,
+// and returns the url of its block worker. The chain started by another
+// process on addr is joined instead.
+func StartChain(ctx context.Context, addr string, cfg chain.Config, interval time.Duration) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	url := "http://" + net.JoinHostPort(host, port)
+
+	l, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		// the validator and the blobber of a sandbox share the chain
+		if resp, herr := http.Get(url + "/v1/healthcheck"); herr == nil {
+			resp.Body.Close()
+			logging.Logger.Info("[devchain]joined", zap.String("url", url))
+			return url, nil
+		}
+		return "", err
+	}
+
+	c, err := chain.New(cfg)
+	if err != nil {
+		l.Close()
+		return "", err
+	}
+	r := mux.NewRouter()
+	RegisterChainHandlers(r, c, url)
+
+	srv := &http.Server{Handler: r}
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			logging.Logger.Error("[devchain]serve", zap.Error(err))
+		}
+	}()
+	go c.Run(ctx, interval)
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	logging.Logger.Info("[devchain]started", zap.String("url", url), zap.String("chain_id", cfg.ChainID))
+	return url, nil
+}
+
+// SetupChain starts the chain of the local sandbox on addr, or joins the one
+// the blobber or the validator started there, and points the block worker of
+// the configuration at it.
+func SetupChain(addr, chainID, signatureScheme string) error {
+	cfg := chain.DefaultConfig()
+	if chainID != "" {
+		cfg.ChainID = chainID
+	}
+	if signatureScheme != "" {
+		cfg.SignatureScheme = signatureScheme
+	}
+
+	url, err := StartChain(context.Background(), addr, cfg, time.Second)
+	if err != nil {
+		return err
+	}
+	viper.Set("block_worker", url)
+	return nil
+}
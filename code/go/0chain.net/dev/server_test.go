@@ -0,0 +1,94 @@
+package dev
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/dev/chain"
+	"github.com/0chain/gosdk/core/transaction"
+	"github.com/0chain/gosdk/core/zcncrypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logging.Logger = zap.NewNop()
+}
+
+func getJSON(t *testing.T, url string, v interface{}) int {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	return resp.StatusCode
+}
+
+func TestChainServer(t *testing.T) {
+	c, err := chain.New(chain.DefaultConfig())
+	require.NoError(t, err)
+	s := NewChainServer(c)
+	defer s.Close()
+
+	var network map[string][]string
+	require.Equal(t, http.StatusOK, getJSON(t, s.URL+"/network", &network))
+	require.Equal(t, []string{s.URL}, network["miners"])
+	require.Equal(t, []string{s.URL}, network["sharders"])
+
+	w, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	txn := &transaction.Transaction{
+		Version:          "1.0",
+		ClientID:         w.ClientID,
+		PublicKey:        w.ClientKey,
+		ToClientID:       chain.FaucetSCAddress,
+		ChainID:          c.Config().ChainID,
+		TransactionData:  `{"name":"pour","input":{}}`,
+		CreationDate:     int64(common.Now()),
+		TransactionType:  transaction.TxnTypeSmartContract,
+		TransactionNonce: 1,
+	}
+	require.NoError(t, txn.ComputeHashAndSign(func(hash string) (string, error) {
+		return w.Sign(hash, "bls0chain")
+	}))
+	body, err := json.Marshal(txn)
+	require.NoError(t, err)
+	resp, err := http.Post(s.URL+"/v1/transaction/put", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var confirmation map[string]json.RawMessage
+	require.Equal(t, http.StatusOK, getJSON(t, s.URL+"/v1/transaction/get/confirmation?content=lfb&hash="+txn.Hash, &confirmation))
+	require.NotContains(t, confirmation, "confirmation", "the transaction is not sealed yet")
+	require.Contains(t, confirmation, "latest_finalized_block")
+
+	resp, err = http.Post(s.URL+"/_dev/round", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, getJSON(t, s.URL+"/v1/transaction/get/confirmation?content=lfb&hash="+txn.Hash, &confirmation))
+	require.Contains(t, confirmation, "confirmation")
+
+	var balance chain.Client
+	require.Equal(t, http.StatusOK, getJSON(t, s.URL+"/v1/client/get/balance?client_id="+w.ClientID, &balance))
+	require.Equal(t, c.Config().FaucetPour, balance.Balance)
+	require.EqualValues(t, 1, balance.Nonce)
+
+	var round int64
+	require.Equal(t, http.StatusOK, getJSON(t, s.URL+"/v1/current-round", &round))
+	require.EqualValues(t, 1, round)
+
+	sc := s.URL + "/v1/screst/" + chain.StorageSCAddress
+	var config struct {
+		Fields map[string]string `json:"fields"`
+	}
+	require.Equal(t, http.StatusOK, getJSON(t, sc+"/storage-config", &config))
+	require.Equal(t, "720", config.Fields["max_challenge_completion_rounds"])
+
+	var failure map[string]string
+	require.Equal(t, http.StatusBadRequest, getJSON(t, sc+"/allocation?allocation=unknown", &failure))
+	require.Equal(t, "allocation_not_found", failure["code"])
+}
@@ -1,11 +1,151 @@
 package sharder
 
 import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/dev/chain"
 	"github.com/gorilla/mux"
 )
 
-func RegisterHandlers(s *mux.Router) {
-	// s.HandleFunc("/v1/file/upload/{allocation}", uploadAndUpdateFile).Methods(http.MethodPut, http.MethodPost)
-	// s.HandleFunc("/v1/file/referencepath/{allocation}", getReference).Methods(http.MethodGet)
-	// s.HandleFunc("/v1/connection/commit/{allocation}", commitWrite).Methods(http.MethodPost)
+// RegisterHandlers registers the sharder APIs of the chain: the blocks, the
+// balances, the confirmations and the REST API of the storage smart contract.
+func RegisterHandlers(s *mux.Router, c *chain.Chain) {
+	h := &handler{chain: c}
+
+	s.HandleFunc("/v1/healthcheck", common.ToJSONResponse(h.healthCheck)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/current-round", common.ToJSONResponse(h.currentRound)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/block/get", common.ToJSONResponse(h.getBlock)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/block/get/latest_finalized", common.ToJSONResponse(h.latestFinalizedBlock)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/client/get/balance", common.ToJSONResponse(h.getBalance)).Methods(http.MethodGet)
+	s.HandleFunc("/v1/transaction/get/confirmation", common.ToJSONResponse(h.getConfirmation)).Methods(http.MethodGet)
+
+	sc := s.PathPrefix("/v1/screst/" + chain.StorageSCAddress).Subrouter()
+	sc.HandleFunc("/allocation", common.ToJSONResponse(h.getAllocation)).Methods(http.MethodGet)
+	sc.HandleFunc("/expired-allocations", common.ToJSONResponse(h.getExpiredAllocations)).Methods(http.MethodGet)
+	sc.HandleFunc("/getReadPoolStat", common.ToJSONResponse(h.getReadPoolStat)).Methods(http.MethodGet)
+	sc.HandleFunc("/latestreadmarker", common.ToJSONResponse(h.getLatestReadMarker)).Methods(http.MethodGet)
+	sc.HandleFunc("/openchallenges", common.ToJSONResponse(h.getOpenChallenges)).Methods(http.MethodGet)
+	sc.HandleFunc("/getchallenge", common.ToJSONResponse(h.getChallenge)).Methods(http.MethodGet)
+	sc.HandleFunc("/getBlobber", common.ToJSONResponse(h.getBlobber)).Methods(http.MethodGet)
+	sc.HandleFunc("/get_validator", common.ToJSONResponse(h.getValidator)).Methods(http.MethodGet)
+	sc.HandleFunc("/storage-config", common.ToJSONResponse(h.getStorageConfig)).Methods(http.MethodGet)
+}
+
+type handler struct {
+	chain *chain.Chain
+}
+
+func (h *handler) healthCheck(ctx context.Context, r *http.Request) (interface{}, error) {
+	return map[string]int64{"round": h.chain.CurrentRound()}, nil
+}
+
+func (h *handler) currentRound(ctx context.Context, r *http.Request) (interface{}, error) {
+	return h.chain.CurrentRound(), nil
+}
+
+func (h *handler) getBlock(ctx context.Context, r *http.Request) (interface{}, error) {
+	round, err := strconv.ParseInt(r.FormValue("round"), 10, 64)
+	if err != nil {
+		return nil, common.NewError("invalid_parameters", "round should be a number")
+	}
+	header, ok := h.chain.Block(round)
+	if !ok {
+		return nil, common.NewErrorf("block_not_found", "no block of round %v", round)
+	}
+	return map[string]interface{}{"header": header}, nil
+}
+
+func (h *handler) latestFinalizedBlock(ctx context.Context, r *http.Request) (interface{}, error) {
+	return h.chain.LatestBlock(), nil
+}
+
+func (h *handler) getBalance(ctx context.Context, r *http.Request) (interface{}, error) {
+	return h.chain.Client(r.FormValue("client_id")), nil
+}
+
+// getConfirmation only serves the latest finalized block until the
+// transaction is sealed, which the clients poll for.
+func (h *handler) getConfirmation(ctx context.Context, r *http.Request) (interface{}, error) {
+	resp := map[string]interface{}{"latest_finalized_block": h.chain.LatestBlock()}
+	if confirmation, ok := h.chain.Confirmation(r.FormValue("hash")); ok {
+		resp["confirmation"] = confirmation
+	}
+	return resp, nil
+}
+
+func (h *handler) getAllocation(ctx context.Context, r *http.Request) (interface{}, error) {
+	a, ok := h.chain.Allocation(r.FormValue("allocation"))
+	if !ok {
+		return nil, common.NewError("allocation_not_found", "allocation not found")
+	}
+	return a, nil
+}
+
+func (h *handler) getExpiredAllocations(ctx context.Context, r *http.Request) (interface{}, error) {
+	return h.chain.ExpiredAllocations(r.FormValue("blobber_id")), nil
+}
+
+func (h *handler) getReadPoolStat(ctx context.Context, r *http.Request) (interface{}, error) {
+	return map[string]uint64{"balance": h.chain.ReadPool(r.FormValue("client_id"))}, nil
+}
+
+func (h *handler) getLatestReadMarker(ctx context.Context, r *http.Request) (interface{}, error) {
+	rm := h.chain.LatestReadMarker(r.FormValue("allocation"), r.FormValue("blobber"), r.FormValue("client"))
+	if rm == nil {
+		return &chain.ReadMarker{}, nil
+	}
+	return rm, nil
+}
+
+func (h *handler) getOpenChallenges(ctx context.Context, r *http.Request) (interface{}, error) {
+	blobberID := r.FormValue("blobber")
+	var from int64
+	if v := r.FormValue("from"); v != "" {
+		var err error
+		if from, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, common.NewError("invalid_parameters", "from should be a number")
+		}
+	}
+	var limit int
+	if v := r.FormValue("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			return nil, common.NewError("invalid_parameters", "limit should be a number")
+		}
+	}
+	return map[string]interface{}{
+		"blobber_id": blobberID,
+		"challenges": h.chain.OpenChallenges(blobberID, from, limit),
+	}, nil
+}
+
+func (h *handler) getChallenge(ctx context.Context, r *http.Request) (interface{}, error) {
+	ch, ok := h.chain.Challenge(r.FormValue("blobber"), r.FormValue("challenge"))
+	if !ok {
+		return nil, common.NewError("challenge_not_found", "challenge not found")
+	}
+	return ch, nil
+}
+
+func (h *handler) getBlobber(ctx context.Context, r *http.Request) (interface{}, error) {
+	b, ok := h.chain.Blobber(r.FormValue("blobber_id"))
+	if !ok {
+		return nil, common.NewError("blobber_not_found", "blobber not found")
+	}
+	return b, nil
+}
+
+func (h *handler) getValidator(ctx context.Context, r *http.Request) (interface{}, error) {
+	v, ok := h.chain.Validator(r.FormValue("validator_id"))
+	if !ok {
+		return nil, common.NewError("validator_not_found", "validator not found")
+	}
+	return v, nil
+}
+
+func (h *handler) getStorageConfig(ctx context.Context, r *http.Request) (interface{}, error) {
+	return map[string]interface{}{"fields": h.chain.StorageConfig()}, nil
 }
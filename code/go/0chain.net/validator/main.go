@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -19,9 +20,12 @@ import (
 	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
 	. "github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/metrics"
 	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
 	"github.com/0chain/blobber/code/go/0chain.net/core/util"
+	"github.com/0chain/blobber/code/go/0chain.net/dev"
 	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/storage"
 
@@ -53,6 +57,7 @@ func main() {
 	hostname := flag.String("hostname", "", "hostname")
 	configDir := flag.String("config_dir", "./config", "config_dir")
 	hostUrl := flag.String("hosturl", "", "register url on blockchain instead of [schema://hostname+port] if it has value")
+	devChain := flag.String("dev-chain", "", "start, or join, an in-process chain simulator on this address (e.g. 127.0.0.1:9091) instead of connecting to block_worker")
 
 	flag.Parse()
 
@@ -80,6 +85,33 @@ func main() {
 	config.Configuration.NumDelegates = viper.GetInt("num_delegates")
 	config.Configuration.ServiceCharge = viper.GetFloat64("service_charge")
 	config.Configuration.HealthCheckWorkerFreq = viper.GetDuration("healthcheck.frequency")
+	config.Configuration.BatchMaxSize = viper.GetInt("challenge_batch.max_size")
+	config.Configuration.BatchConcurrency = viper.GetInt("challenge_batch.concurrency")
+	config.Configuration.LookupCacheTTL = viper.GetDuration("chain_lookups.cache_ttl")
+	config.Configuration.LookupCacheMaxEntries = viper.GetInt("chain_lookups.cache_max_entries")
+	config.Configuration.MinSharderAgreement = viper.GetInt("chain_lookups.min_sharder_agreement")
+	config.Configuration.MetricsAddress = viper.GetString("metrics.address")
+	config.Configuration.Tracing = tracing.Config{
+		Enabled:     viper.GetBool("tracing.enabled"),
+		Exporter:    viper.GetString("tracing.exporter"),
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		Insecure:    viper.GetBool("tracing.insecure"),
+		File:        viper.GetString("tracing.file"),
+		SampleRatio: viper.GetFloat64("tracing.sample_ratio"),
+	}
+
+	shutdownTracing, err := tracing.Setup("validator", config.Configuration.Tracing)
+	if err != nil {
+		log.Fatal("tracing:", err)
+	}
+	go func() {
+		<-common.GetRootContext().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			Logger.Error("Error flushing the traces", zap.Error(err))
+		}
+	}()
 
 	//address := publicIP + ":" + portString
 	address := ":" + *portString
@@ -93,7 +125,7 @@ func main() {
 
 	fmt.Printf("[+] %-24s    %s\n", "setup configs", "[OK]")
 
-	err := readKeysFromAws()
+	err = readKeysFromAws()
 	if err != nil {
 		err = readKeysFromFile(keysFile)
 		if err != nil {
@@ -131,6 +163,12 @@ func main() {
 
 	prepare(node.Self.ID)
 
+	if *devChain != "" {
+		if err := dev.SetupChain(*devChain, config.Configuration.ChainID, config.Configuration.SignatureScheme); err != nil {
+			Logger.Panic("dev chain: " + err.Error())
+		}
+	}
+
 	config.SetServerChainID(config.Configuration.ChainID)
 	common.SetupRootContext(node.GetNodeContext())
 	serverChain = chain.NewChainFromConfig()
@@ -185,6 +223,13 @@ func main() {
 
 	initHandlers(r)
 
+	if addr := config.Configuration.MetricsAddress; addr != "" {
+		go func() {
+			err := metrics.Serve(addr)
+			logging.Logger.Error("metrics server shut down", zap.Error(err))
+		}()
+	}
+
 	fmt.Printf("[+] %-24s    %s\n", "start server on "+address, "[OK]")
 	Logger.Info("Ready to listen to the requests")
 	startTime = time.Now().UTC()
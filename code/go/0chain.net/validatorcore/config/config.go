@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/0chain/blobber/code/go/0chain.net/core/config"
+	"github.com/0chain/blobber/code/go/0chain.net/core/tracing"
 	"github.com/spf13/viper"
 )
 
@@ -13,6 +14,18 @@ func SetupDefaultConfig() {
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("delegate_wallet", "")
 	viper.SetDefault("num_delegates", 100)
+	viper.SetDefault("challenge_batch.max_size", 50)
+	viper.SetDefault("challenge_batch.concurrency", 8)
+	viper.SetDefault("chain_lookups.cache_ttl", "5m")
+	viper.SetDefault("chain_lookups.cache_max_entries", 10000)
+	viper.SetDefault("chain_lookups.min_sharder_agreement", 1)
+	viper.SetDefault("metrics.address", "127.0.0.1:9103")
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", tracing.ExporterStdout)
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.file", "")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 }
 
 /*SetupConfig - setup the configuration system */
@@ -47,6 +60,22 @@ type Config struct {
 	ServiceCharge float64 `json:"service_charge"`
 	// HealthCheckInterval for blobber.
 	HealthCheckWorkerFreq time.Duration
+	// BatchMaxSize is the maximum number of challenges in a batch validation request.
+	BatchMaxSize int
+	// BatchConcurrency is the number of challenges of a batch verified at the same time.
+	BatchConcurrency int
+	// LookupCacheTTL is how long the allocations and challenges fetched from the chain are cached.
+	LookupCacheTTL time.Duration
+	// LookupCacheMaxEntries is the maximum number of chain lookups cached.
+	LookupCacheMaxEntries int
+	// MinSharderAgreement is the number of sharders that must return the same
+	// allocation and challenge before a ticket is signed. 1 trusts a single sharder.
+	MinSharderAgreement int
+	// MetricsAddress is the address the metrics are served on, apart from
+	// the API. Empty disables them.
+	MetricsAddress string
+	// Tracing of the validation requests, continuing the traces of the blobbers.
+	Tracing tracing.Config
 }
 
 /*Configuration of the system */
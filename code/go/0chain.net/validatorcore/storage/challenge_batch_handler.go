@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/config"
+	"github.com/remeh/sizedwaitgroup"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/sha3"
+)
+
+// BatchChallengeRequest is a batch of challenge requests of a blobber. Each
+// challenge is encoded the way it is posted to the single challenge endpoint.
+type BatchChallengeRequest struct {
+	Challenges []json.RawMessage `json:"challenges"`
+}
+
+// BatchChallengeResult is the result of a challenge of a batch, either a
+// validation ticket or the error that prevented issuing one.
+type BatchChallengeResult struct {
+	ChallengeID string            `json:"challenge_id"`
+	Ticket      *ValidationTicket `json:"ticket,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// BatchChallengeResponse holds the results of a batch, in the order of the
+// challenges of the request.
+type BatchChallengeResponse struct {
+	Results []*BatchChallengeResult `json:"results"`
+}
+
+type lookupResult struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+// batchLookups shares the chain lookups of the challenges of a batch.
+type batchLookups struct {
+	mu     sync.Mutex
+	allocs map[string]*lookupResult
+	chals  map[string]*lookupResult
+}
+
+func (bl *batchLookups) get(m map[string]*lookupResult, key string, f func() (interface{}, error)) (interface{}, error) {
+	bl.mu.Lock()
+	lr, ok := m[key]
+	if !ok {
+		lr = &lookupResult{}
+		m[key] = lr
+	}
+	bl.mu.Unlock()
+
+	lr.once.Do(func() {
+		lr.value, lr.err = f()
+	})
+	return lr.value, lr.err
+}
+
+func (bl *batchLookups) challenge(ctx context.Context, cr *ChallengeRequest) (*Challenge, error) {
+	v, err := bl.get(bl.chals, cr.ChallengeID, func() (interface{}, error) {
+		return NewChallengeObj(ctx, cr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Challenge), nil
+}
+
+func (bl *batchLookups) allocation(ctx context.Context, allocationID string) (*Allocation, error) {
+	v, err := bl.get(bl.allocs, allocationID, func() (interface{}, error) {
+		return GetProtocolImpl().VerifyAllocationTransaction(ctx, allocationID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Allocation), nil
+}
+
+func batchChallengeHandler(ctx context.Context, r *http.Request) (*BatchChallengeResponse, error) {
+	if r.Method != http.MethodPost {
+		return nil, common.NewError("invalid_method", "Invalid method used for the batch validation URL. Use POST instead")
+	}
+
+	requestHash := r.Header.Get("X-App-Request-Hash")
+	h := sha3.New256()
+	var batch BatchChallengeRequest
+	if err := json.NewDecoder(io.TeeReader(r.Body, h)).Decode(&batch); err != nil {
+		return nil, common.NewError("input_decode_error", "Error in decoding the input."+err.Error())
+	}
+	if requestHash != hex.EncodeToString(h.Sum(nil)) {
+		return nil, common.NewError("invalid_parameters", "Header hash and request hash do not match")
+	}
+
+	if len(batch.Challenges) == 0 {
+		return nil, common.NewError("invalid_parameters", "No challenges in the batch")
+	}
+	if max := config.Configuration.BatchMaxSize; max > 0 && len(batch.Challenges) > max {
+		return nil, common.NewErrorf("invalid_parameters", "Too many challenges in the batch, the limit is %d", max)
+	}
+
+	logging.Logger.Info("Processing batch validation.", zap.Int("challenges", len(batch.Challenges)))
+
+	lookups := &batchLookups{
+		allocs: make(map[string]*lookupResult),
+		chals:  make(map[string]*lookupResult),
+	}
+	results := make([]*BatchChallengeResult, len(batch.Challenges))
+
+	concurrency := config.Configuration.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	swg := sizedwaitgroup.New(concurrency)
+	for i, raw := range batch.Challenges {
+		swg.Add()
+		go func(i int, raw json.RawMessage) {
+			defer swg.Done()
+			results[i] = validateBatchChallenge(ctx, lookups, raw)
+		}(i, raw)
+	}
+	swg.Wait()
+
+	return &BatchChallengeResponse{Results: results}, nil
+}
+
+// validateBatchChallenge verifies a challenge of a batch the way challengeHandler does.
+func validateBatchChallenge(ctx context.Context, lookups *batchLookups, raw json.RawMessage) *BatchChallengeResult {
+	start, outcome := time.Now(), validationError
+	defer func() { observeValidation("batch", outcome, start) }()
+
+	var challengeRequest ChallengeRequest
+	if err := json.Unmarshal(raw, &challengeRequest); err != nil {
+		return &BatchChallengeResult{Error: "Error in decoding the input." + err.Error()}
+	}
+	result := &BatchChallengeResult{ChallengeID: challengeRequest.ChallengeID}
+
+	h := sha3.Sum256(raw)
+	challengeHash := hex.EncodeToString(h[:])
+	vt, err := lru.Get(challengeHash)
+	if retVT, ok := vt.(*ValidationTicket); vt != nil && err == nil && ok {
+		outcome = validationCached
+		result.Ticket = retVT
+		return result
+	}
+
+	challengeObj, err := lookups.challenge(ctx, &challengeRequest)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	allocationObj, err := lookups.allocation(ctx, challengeObj.AllocationID)
+	if err != nil {
+		logging.Logger.Error("Error verifying the allocation from BC", zap.String("allocation_id", challengeObj.AllocationID), zap.Error(err))
+		result.Error = common.NewError("invalid_parameters", "Allocation could not be verified. "+err.Error()).Error()
+		return result
+	}
+
+	var ticket interface{}
+	err = challengeRequest.VerifyChallenge(challengeObj, allocationObj)
+	if err != nil {
+		updateStats(false)
+		outcome = validationFailed
+		ticket, err = InvalidValidationTicket(challengeObj, err)
+	} else {
+		updateStats(true)
+		outcome = validationPassed
+		ticket, err = ValidValidationTicket(challengeObj, challengeRequest.ChallengeID, challengeHash)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Ticket = ticket.(*ValidationTicket)
+	return result
+}
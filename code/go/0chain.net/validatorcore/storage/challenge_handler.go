@@ -21,6 +21,9 @@ import (
 var lru = cache.NewLRUCache(10000)
 
 func challengeHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	start, result := time.Now(), validationError
+	defer func() { observeValidation("single", result, start) }()
+
 	challengeRequest, challengeHash, err := NewChallengeRequest(r)
 	if err != nil {
 		return nil, err
@@ -31,6 +34,7 @@ func challengeHandler(ctx context.Context, r *http.Request) (interface{}, error)
 	vt, err := lru.Get(challengeHash)
 	retVT, ok := vt.(*ValidationTicket)
 	if vt != nil && err == nil && ok {
+		result = validationCached
 		return retVT, nil
 	}
 
@@ -50,10 +54,12 @@ func challengeHandler(ctx context.Context, r *http.Request) (interface{}, error)
 	err = challengeRequest.VerifyChallenge(challengeObj, allocationObj)
 	if err != nil {
 		updateStats(false)
+		result = validationFailed
 		return InvalidValidationTicket(challengeObj, err)
 	}
 
 	updateStats(true)
+	result = validationPassed
 
 	return ValidValidationTicket(challengeObj, challengeRequest.ChallengeID, challengeHash)
 }
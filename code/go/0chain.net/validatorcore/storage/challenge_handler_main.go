@@ -10,11 +10,22 @@ import (
 
 func ChallengeHandler(ctx context.Context, r *http.Request) (interface{}, error) {
 	res, err := challengeHandler(ctx, r)
+	addLastTransaction(res)
 
-	if len(Last5Transactions) >= 5 {
-		Last5Transactions = Last5Transactions[1:]
+	return res, err
+}
+
+func BatchChallengeHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	res, err := batchChallengeHandler(ctx, r)
+	if err != nil {
+		return nil, err
 	}
-	Last5Transactions = append(Last5Transactions, res)
 
-	return res, err
+	for _, result := range res.Results {
+		if result.Ticket != nil {
+			addLastTransaction(result.Ticket)
+		}
+	}
+
+	return res, nil
 }
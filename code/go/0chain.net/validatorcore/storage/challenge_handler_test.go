@@ -6,12 +6,20 @@ package storage_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
+	"github.com/0chain/blobber/code/go/0chain.net/core/encryption"
 	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/config"
 	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/storage"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/storage/writemarker"
 
+	"github.com/0chain/gosdk/constants"
+	"github.com/0chain/gosdk/core/zcncrypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -73,3 +81,124 @@ func TestChallengeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBatchChallengeHandler(t *testing.T) {
+	logging.Logger = zap.New(nil) // FIXME to avoid complains
+	config.Configuration.BatchMaxSize = 2
+
+	tests := []struct {
+		name       string
+		body       string
+		method     string
+		hash       string
+		wantErrMsg string
+	}{
+		{
+			name:       "wrong request method",
+			body:       "{}",
+			method:     "GET",
+			wantErrMsg: "Invalid method used for the batch validation URL",
+		},
+		{
+			name:       "invalid body",
+			body:       "body",
+			method:     "POST",
+			wantErrMsg: "Error in decoding the input.",
+		},
+		{
+			name:       "hash mismatch",
+			body:       `{"challenges":[{}]}`,
+			method:     "POST",
+			hash:       "840eb7aa2a9935de63366bacbe9d97e978a859e93dc792a0334de60ed52f8e90",
+			wantErrMsg: "Header hash and request hash do not match",
+		},
+		{
+			name:       "empty batch",
+			body:       `{"challenges":[]}`,
+			method:     "POST",
+			wantErrMsg: "No challenges in the batch",
+		},
+		{
+			name:       "too many challenges",
+			body:       `{"challenges":[{},{},{}]}`,
+			method:     "POST",
+			wantErrMsg: "Too many challenges in the batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, "url", bytes.NewBuffer([]byte(tt.body)))
+			hash := tt.hash
+			if hash == "" {
+				hash = encryption.Hash(tt.body)
+			}
+			req.Header.Set("X-App-Request-Hash", hash)
+
+			got, err := storage.BatchChallengeHandler(context.TODO(), req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErrMsg)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestBatchChallengeHandler_Valid(t *testing.T) {
+	require.NoError(t, setupModelsTest(t))
+	node.Self.ID = "validator"
+	config.Configuration.BatchMaxSize = 2
+	config.Configuration.BatchConcurrency = 2
+	config.Configuration.LookupCacheTTL = 0
+	config.Configuration.MinSharderAgreement = 2
+	allSharders := transaction.MakeSCRestAPICallAllSharders
+	defer func() {
+		transaction.MakeSCRestAPICallAllSharders = allSharders
+		config.Configuration.MinSharderAgreement = 1
+	}()
+
+	client, err := zcncrypto.NewSignatureScheme("bls0chain").GenerateKeys()
+	require.NoError(t, err)
+	wm := writemarker.WriteMarker{AllocationID: "alloc", ClientID: client.ClientID, Timestamp: 10}
+	wm.Signature, err = client.Sign(encryption.Hash(wm.GetHashData()), "bls0chain")
+	require.NoError(t, err)
+
+	transaction.MakeSCRestAPICallAllSharders = func(_ context.Context, _, path string, params map[string]string) (map[string][]byte, error) {
+		var resp interface{} = &storage.Allocation{ID: params["allocation"]}
+		if path == "/getchallenge" {
+			resp = &storage.Challenge{
+				ID:           params["challenge"],
+				BlobberID:    params["blobber"],
+				AllocationID: "alloc",
+				Timestamp:    10,
+				Validators:   []*storage.StorageNode{{ID: "validator"}},
+			}
+		}
+		b, err := json.Marshal(resp)
+		return map[string][]byte{"s1": b, "s2": b}, err
+	}
+
+	var batch storage.BatchChallengeRequest
+	for _, id := range []string{"c1", "c2"} {
+		raw, err := json.Marshal(&storage.ChallengeRequest{
+			ChallengeID:  id,
+			WriteMarkers: []*writemarker.WriteMarkerEntity{{WM: &wm, ClientPublicKey: client.ClientKey}},
+		})
+		require.NoError(t, err)
+		batch.Challenges = append(batch.Challenges, raw)
+	}
+	body, err := json.Marshal(&batch)
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodPost, "url", bytes.NewBuffer(body))
+	req.Header.Set("X-App-Request-Hash", encryption.Hash(string(body)))
+
+	ctx := context.WithValue(context.TODO(), constants.ContextKeyClient, "blobber-batch")
+	got, err := storage.BatchChallengeHandler(ctx, req)
+	require.NoError(t, err)
+	results := got.(*storage.BatchChallengeResponse).Results
+	require.Len(t, results, 2)
+	for i, id := range []string{"c1", "c2"} {
+		require.Empty(t, results[i].Error)
+		require.Equal(t, id, results[i].Ticket.ChallengeID)
+		require.True(t, results[i].Ticket.Result)
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/config"
+)
+
+// LookupCacheStats are the counters of the cache of the chain lookups.
+type LookupCacheStats struct {
+	Entries        int
+	Hits           int64
+	Misses         int64
+	Expired        int64
+	Evicted        int64
+	Disagreements  int64
+	AgreementFails int64
+}
+
+// lookupCache keeps the allocations and challenges fetched from the sharders
+// for a while. Only the successful lookups are cached, a challenge not found
+// yet may show up on the sharders a moment later.
+type lookupCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	stats   LookupCacheStats
+	now     func() time.Time
+}
+
+type lookupCacheEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+func newLookupCache() *lookupCache {
+	return &lookupCache{
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+var chainLookups = newLookupCache()
+
+func allocationLookupKey(allocationID string) string {
+	return "allocation:" + allocationID
+}
+
+func challengeLookupKey(blobberID, challengeID string) string {
+	return "challenge:" + blobberID + ":" + challengeID
+}
+
+func (c *lookupCache) enabled() bool {
+	return config.Configuration.LookupCacheTTL > 0 && config.Configuration.LookupCacheMaxEntries > 0
+}
+
+func (c *lookupCache) get(key string) (interface{}, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	e := el.Value.(*lookupCacheEntry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		c.stats.Expired++
+		c.stats.Misses++
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	c.stats.Hits++
+	return e.value, true
+}
+
+func (c *lookupCache) add(key string, value interface{}) {
+	if !c.enabled() {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(config.Configuration.LookupCacheTTL)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*lookupCacheEntry)
+		e.value, e.expires = value, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&lookupCacheEntry{key: key, value: value, expires: expires})
+
+	for c.lru.Len() > config.Configuration.LookupCacheMaxEntries {
+		c.remove(c.lru.Back())
+		c.stats.Evicted++
+	}
+}
+
+func (c *lookupCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*lookupCacheEntry)
+	delete(c.entries, e.key)
+}
+
+func (c *lookupCache) recordAgreement(disagreement, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if disagreement {
+		c.stats.Disagreements++
+	}
+	if failed {
+		c.stats.AgreementFails++
+	}
+}
+
+func (c *lookupCache) getStats() LookupCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// GetLookupCacheStats returns the counters of the cache of the chain lookups.
+func GetLookupCacheStats() LookupCacheStats {
+	return chainLookups.getStats()
+}
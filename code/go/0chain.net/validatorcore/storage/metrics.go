@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Results of the validations of the challenges.
+const (
+	validationPassed = "passed"
+	validationFailed = "failed"
+	// validationError is a challenge that could not be validated, e.g. for a
+	// failed chain lookup.
+	validationError = "error"
+	// validationCached is a challenge whose ticket was issued before.
+	validationCached = "cached"
+)
+
+var validationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "validator_challenge_validation_seconds",
+	Help:    "Duration of the validations of the challenges, by endpoint and result.",
+	Buckets: []float64{.1, .25, .5, 1, 2, 5, 10, 30, 60},
+}, []string{"endpoint", "result"})
+
+func observeValidation(endpoint, result string, start time.Time) {
+	validationSeconds.WithLabelValues(endpoint, result).Observe(time.Since(start).Seconds())
+}
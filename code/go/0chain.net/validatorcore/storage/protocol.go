@@ -28,11 +28,15 @@ type StorageNode struct {
 // ValidatorProtocolImpl - implementation of the storage protocol
 type ValidatorProtocolImpl struct {
 	ServerChain *chain.Chain
+	// Lookups caches the allocations and challenges fetched from the chain.
+	Lookups *lookupCache
 }
 
 func GetProtocolImpl() *ValidatorProtocolImpl {
 	return &ValidatorProtocolImpl{
-		ServerChain: chain.GetServerChain()}
+		ServerChain: chain.GetServerChain(),
+		Lookups:     chainLookups,
+	}
 }
 
 // func (sp *ValidatorProtocolImpl) AddChallenge(ctx context.Context) (string, error) {
@@ -67,37 +71,62 @@ func GetProtocolImpl() *ValidatorProtocolImpl {
 // 	return txn.Hash, nil
 // }
 
+// VerifyAllocationTransaction returns the allocation, from the lookup cache if
+// it was fetched lately. If an agreement of sharders is required, the
+// allocation is fetched from all of them instead of verifying its transaction.
 func (sp *ValidatorProtocolImpl) VerifyAllocationTransaction(ctx context.Context, allocationID string) (*Allocation, error) {
-	t, err := transaction.VerifyTransaction(allocationID, sp.ServerChain)
-	if err != nil {
-		return nil, common.NewError("invalid_allocation", "Invalid Allocation id. Allocation not found in blockchain. "+err.Error())
+	key := allocationLookupKey(allocationID)
+	if v, ok := sp.Lookups.get(key); ok {
+		return v.(*Allocation), nil
 	}
+
+	var allocationBytes []byte
+	if minAgreement := config.Configuration.MinSharderAgreement; minAgreement > 1 {
+		params := map[string]string{"allocation": allocationID}
+		b, err := makeSCRestAPICallWithAgreement(ctx, "/allocation", params, minAgreement, canonicalAllocation)
+		if err != nil {
+			return nil, common.NewError("invalid_allocation", "Allocation could not be agreed on by the sharders. "+err.Error())
+		}
+		allocationBytes = b
+	} else {
+		t, err := transaction.VerifyTransaction(allocationID, sp.ServerChain)
+		if err != nil {
+			return nil, common.NewError("invalid_allocation", "Invalid Allocation id. Allocation not found in blockchain. "+err.Error())
+		}
+		allocationBytes = []byte(t.TransactionOutput)
+	}
+
 	var allocationObj Allocation
-	err = json.Unmarshal([]byte(t.TransactionOutput), &allocationObj)
+	err := json.Unmarshal(allocationBytes, &allocationObj)
 	if err != nil {
 		return nil, common.NewError("transaction_output_decode_error", "Error decoding the allocation transaction output."+err.Error())
 	}
+
+	sp.Lookups.add(key, &allocationObj)
 	return &allocationObj, nil
 }
 
+// VerifyChallengeTransaction returns the challenge of the calling blobber,
+// from the lookup cache if it was fetched lately.
 func (sp *ValidatorProtocolImpl) VerifyChallengeTransaction(ctx context.Context, challengeRequest *ChallengeRequest) (*Challenge, error) {
 	blobberID := ctx.Value(constants.ContextKeyClient).(string)
 	if blobberID == "" {
 		return nil, common.NewError("invalid_client", "Call from an invalid client")
 	}
-	params := make(map[string]string)
-	params["blobber"] = blobberID
-	params["challenge"] = challengeRequest.ChallengeID
-	challengeBytes, err := transaction.MakeSCRestAPICall(transaction.STORAGE_CONTRACT_ADDRESS, "/getchallenge", params)
 
-	if err != nil {
-		return nil, common.NewError("invalid_challenge", "Invalid challenge id. Challenge not found in blockchain. "+err.Error())
-	}
-	var challengeObj Challenge
-	err = json.Unmarshal(challengeBytes, &challengeObj)
-	if err != nil {
-		return nil, common.NewError("transaction_output_decode_error", "Error decoding the challenge output."+err.Error())
+	key := challengeLookupKey(blobberID, challengeRequest.ChallengeID)
+	v, ok := sp.Lookups.get(key)
+	if !ok {
+		challengeObj, err := sp.fetchChallenge(ctx, blobberID, challengeRequest.ChallengeID)
+		if err != nil {
+			return nil, err
+		}
+		sp.Lookups.add(key, challengeObj)
+		v = challengeObj
 	}
+	// the cached challenge is shared, it is only read
+	challengeObj := v.(*Challenge)
+
 	foundValidator := false
 	for _, validator := range challengeObj.Validators {
 		if validator.ID == node.Self.ID {
@@ -113,6 +142,32 @@ func (sp *ValidatorProtocolImpl) VerifyChallengeTransaction(ctx context.Context,
 		return nil, common.NewError("invalid_challenge", "Challenge is meant for a different blobber")
 	}
 
+	return challengeObj, nil
+}
+
+func (sp *ValidatorProtocolImpl) fetchChallenge(ctx context.Context, blobberID, challengeID string) (*Challenge, error) {
+	params := make(map[string]string)
+	params["blobber"] = blobberID
+	params["challenge"] = challengeID
+
+	var (
+		challengeBytes []byte
+		err            error
+	)
+	if minAgreement := config.Configuration.MinSharderAgreement; minAgreement > 1 {
+		challengeBytes, err = makeSCRestAPICallWithAgreement(ctx, "/getchallenge", params, minAgreement, canonicalChallenge)
+	} else {
+		challengeBytes, err = transaction.MakeSCRestAPICall(ctx, transaction.STORAGE_CONTRACT_ADDRESS, "/getchallenge", params)
+	}
+	if err != nil {
+		return nil, common.NewError("invalid_challenge", "Invalid challenge id. Challenge not found in blockchain. "+err.Error())
+	}
+
+	var challengeObj Challenge
+	err = json.Unmarshal(challengeBytes, &challengeObj)
+	if err != nil {
+		return nil, common.NewError("transaction_output_decode_error", "Error decoding the challenge output."+err.Error())
+	}
 	return &challengeObj, nil
 }
 
@@ -0,0 +1,134 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/node"
+	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/config"
+	"github.com/0chain/blobber/code/go/0chain.net/validatorcore/storage"
+
+	"github.com/0chain/gosdk/constants"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVerifyChallengeTransaction_LookupCache(t *testing.T) {
+	logging.Logger = zap.NewNop()
+	node.Self.ID = "validator"
+	config.Configuration.LookupCacheTTL = time.Minute
+	config.Configuration.LookupCacheMaxEntries = 2
+	config.Configuration.MinSharderAgreement = 1
+	defer func() {
+		transaction.MakeSCRestAPICall = transaction.MakeSCRestAPICallNoHandler
+		config.Configuration.LookupCacheTTL = 0
+	}()
+
+	calls := 0
+	transaction.MakeSCRestAPICall = func(_ context.Context, _, _ string, params map[string]string) ([]byte, error) {
+		calls++
+		return json.Marshal(&storage.Challenge{
+			ID:         params["challenge"],
+			BlobberID:  params["blobber"],
+			Validators: []*storage.StorageNode{{ID: "validator"}},
+		})
+	}
+
+	ctx := context.WithValue(context.TODO(), constants.ContextKeyClient, "blobber-cache")
+	get := func(id string) {
+		c, err := storage.GetProtocolImpl().VerifyChallengeTransaction(ctx, &storage.ChallengeRequest{ChallengeID: id})
+		require.NoError(t, err)
+		require.Equal(t, id, c.ID)
+	}
+
+	before := storage.GetLookupCacheStats()
+	get("c1")
+	get("c1")
+	require.Equal(t, 1, calls)
+
+	// c1 is evicted once the cache is full
+	get("c2")
+	get("c3")
+	get("c1")
+	require.Equal(t, 4, calls)
+
+	stats := storage.GetLookupCacheStats()
+	require.Equal(t, before.Hits+1, stats.Hits)
+	require.Equal(t, before.Evicted+2, stats.Evicted)
+	require.Equal(t, 2, stats.Entries)
+
+	// the challenge cached for a blobber is not served to another one, it is
+	// fetched for the other blobber
+	c, err := storage.GetProtocolImpl().VerifyChallengeTransaction(
+		context.WithValue(context.TODO(), constants.ContextKeyClient, "other"),
+		&storage.ChallengeRequest{ChallengeID: "c1"})
+	require.NoError(t, err)
+	require.Equal(t, "other", c.BlobberID)
+	require.Equal(t, 5, calls)
+}
+
+func TestVerifyChallengeTransaction_SharderAgreement(t *testing.T) {
+	logging.Logger = zap.NewNop()
+	node.Self.ID = "validator"
+	config.Configuration.LookupCacheTTL = 0
+	config.Configuration.MinSharderAgreement = 2
+	allSharders := transaction.MakeSCRestAPICallAllSharders
+	defer func() {
+		transaction.MakeSCRestAPICallAllSharders = allSharders
+		config.Configuration.MinSharderAgreement = 1
+	}()
+
+	challenge := func(seed int64) []byte {
+		b, _ := json.Marshal(&storage.Challenge{
+			ID:           "c",
+			BlobberID:    "blobber-agreement",
+			RandomNumber: seed,
+			Validators:   []*storage.StorageNode{{ID: "validator"}},
+		})
+		return b
+	}
+	ctx := context.WithValue(context.TODO(), constants.ContextKeyClient, "blobber-agreement")
+	req := &storage.ChallengeRequest{ChallengeID: "c"}
+
+	tests := []struct {
+		name      string
+		responses map[string][]byte
+		wantSeed  int64
+		wantErr   bool
+	}{
+		{
+			name:      "agreed",
+			responses: map[string][]byte{"s1": challenge(1), "s2": challenge(1), "s3": challenge(2)},
+			wantSeed:  1,
+		},
+		{
+			name:      "single sharder",
+			responses: map[string][]byte{"s1": challenge(1), "s2": []byte(`{"error":"not found"}`)},
+			wantErr:   true,
+		},
+		{
+			name:      "split",
+			responses: map[string][]byte{"s1": challenge(1), "s2": challenge(1), "s3": challenge(2), "s4": challenge(2)},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction.MakeSCRestAPICallAllSharders = func(_ context.Context, _, _ string, _ map[string]string) (map[string][]byte, error) {
+				return tt.responses, nil
+			}
+			c, err := storage.GetProtocolImpl().VerifyChallengeTransaction(ctx, req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSeed, c.RandomNumber)
+		})
+	}
+}
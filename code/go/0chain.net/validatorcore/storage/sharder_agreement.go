@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/0chain/blobber/code/go/0chain.net/core/common"
+	"github.com/0chain/blobber/code/go/0chain.net/core/logging"
+	"github.com/0chain/blobber/code/go/0chain.net/core/transaction"
+	"go.uber.org/zap"
+)
+
+// canonicalAllocation keeps the fields of an allocation that do not change
+// over its life, so sharders a few rounds apart still agree on it.
+func canonicalAllocation(b []byte) (string, error) {
+	var a Allocation
+	if err := json.Unmarshal(b, &a); err != nil {
+		return "", err
+	}
+	if a.ID == "" {
+		return "", common.NewError("invalid_allocation", "Allocation without id")
+	}
+	c, err := json.Marshal(struct {
+		ID             string `json:"id"`
+		DataShards     int    `json:"data_shards"`
+		ParityShards   int    `json:"parity_shards"`
+		Owner          string `json:"owner_id"`
+		OwnerPublicKey string `json:"owner_public_key"`
+	}{a.ID, a.DataShards, a.ParityShards, a.Owner, a.OwnerPublicKey})
+	return string(c), err
+}
+
+func canonicalChallenge(b []byte) (string, error) {
+	var c Challenge
+	if err := json.Unmarshal(b, &c); err != nil {
+		return "", err
+	}
+	if c.ID == "" {
+		return "", common.NewError("invalid_challenge", "Challenge without id")
+	}
+	cb, err := json.Marshal(&c)
+	return string(cb), err
+}
+
+// agreedResponse returns the response given by most sharders, if at least
+// minAgreement of them gave it. The responses are compared by their
+// canonical form, the ones that can not be decoded are not counted.
+func agreedResponse(responses map[string][]byte, minAgreement int, canonical func([]byte) (string, error)) ([]byte, error) {
+	counts := make(map[string]int)
+	bodies := make(map[string][]byte)
+	for _, b := range responses {
+		key, err := canonical(b)
+		if err != nil {
+			continue
+		}
+		counts[key]++
+		bodies[key] = b
+	}
+
+	var best string
+	maxCount, tie := 0, false
+	for key, n := range counts {
+		switch {
+		case n > maxCount:
+			best, maxCount, tie = key, n, false
+		case n == maxCount:
+			tie = true
+		}
+	}
+
+	disagreement := len(counts) > 1
+	failed := maxCount < minAgreement || tie
+	chainLookups.recordAgreement(disagreement, failed)
+	if disagreement {
+		logging.Logger.Warn("Sharders disagree on a lookup",
+			zap.Int("answers", len(counts)),
+			zap.Int("responses", len(responses)))
+	}
+	if failed {
+		return nil, common.NewErrorf("sharder_agreement_failed",
+			"%d of %d sharders agree, %d required", maxCount, len(responses), minAgreement)
+	}
+	return bodies[best], nil
+}
+
+// makeSCRestAPICallWithAgreement makes the SC REST API call on all sharders
+// and returns the response at least minAgreement of them agree on.
+func makeSCRestAPICallWithAgreement(ctx context.Context, path string, params map[string]string, minAgreement int, canonical func([]byte) (string, error)) ([]byte, error) {
+	responses, err := transaction.MakeSCRestAPICallAllSharders(ctx, transaction.STORAGE_CONTRACT_ADDRESS, path, params)
+	if err != nil {
+		return nil, err
+	}
+	return agreedResponse(responses, minAgreement, canonical)
+}
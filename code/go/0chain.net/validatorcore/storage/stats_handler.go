@@ -9,7 +9,27 @@ import (
 	"sync"
 )
 
-var Last5Transactions []interface{}
+var (
+	Last5Transactions []interface{}
+	last5Mutex        sync.Mutex
+)
+
+// addLastTransaction keeps the result among the last 5 transactions shown by
+// the stats page.
+func addLastTransaction(res interface{}) {
+	last5Mutex.Lock()
+	defer last5Mutex.Unlock()
+	if len(Last5Transactions) >= 5 {
+		Last5Transactions = Last5Transactions[1:]
+	}
+	Last5Transactions = append(Last5Transactions, res)
+}
+
+func getLastTransactions() []interface{} {
+	last5Mutex.Lock()
+	defer last5Mutex.Unlock()
+	return append([]interface{}(nil), Last5Transactions...)
+}
 
 type Stats struct {
 	TotalChallenges      int
@@ -48,6 +68,7 @@ func getStats() Stats {
 
 func statsHandler(w http.ResponseWriter, r *http.Request) {
 	result := getStats()
+	lookups := GetLookupCacheStats()
 
 	statsHTML := `
 	<!DOCTYPE html>
@@ -93,11 +114,46 @@ func statsHandler(w http.ResponseWriter, r *http.Request) {
 				<td>` + fmt.Sprintf("%d", result.FailedChallenges) + `</td>
 			</tr>
 		</table>
+		<h1>Chain Lookups</h1>
+		<table>
+			<tr>
+				<th>Statistic</th>
+				<th>Count</th>
+			</tr>
+			<tr>
+				<td>Cached Entries</td>
+				<td>` + fmt.Sprintf("%d", lookups.Entries) + `</td>
+			</tr>
+			<tr>
+				<td>Cache Hits</td>
+				<td>` + fmt.Sprintf("%d", lookups.Hits) + `</td>
+			</tr>
+			<tr>
+				<td>Cache Misses</td>
+				<td>` + fmt.Sprintf("%d", lookups.Misses) + `</td>
+			</tr>
+			<tr>
+				<td>Expired Entries</td>
+				<td>` + fmt.Sprintf("%d", lookups.Expired) + `</td>
+			</tr>
+			<tr>
+				<td>Evicted Entries</td>
+				<td>` + fmt.Sprintf("%d", lookups.Evicted) + `</td>
+			</tr>
+			<tr>
+				<td>Sharder Disagreements</td>
+				<td>` + fmt.Sprintf("%d", lookups.Disagreements) + `</td>
+			</tr>
+			<tr>
+				<td>Failed Sharder Agreements</td>
+				<td>` + fmt.Sprintf("%d", lookups.AgreementFails) + `</td>
+			</tr>
+		</table>
 	 <div class="transactions">
             <h2>Last 5 Transactions</h2>
             <ul>
     `
-	for _, transaction := range Last5Transactions {
+	for _, transaction := range getLastTransactions() {
 		jsonData, err := json.Marshal(transaction)
 		if err != nil {
 			statsHTML += "<li>Failed to marshal transaction</li>"
@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE allocations ADD COLUMN redeem_paused boolean DEFAULT false NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE auth_tickets (
+    allocation_id character varying(64) NOT NULL,
+    ticket_hash character varying(64) NOT NULL,
+    owner_id character varying(64) NOT NULL,
+    client_id character varying(64) DEFAULT ''::character varying NOT NULL,
+    file_path_hash character varying(64) DEFAULT ''::character varying NOT NULL,
+    file_name text DEFAULT ''::text NOT NULL,
+    ref_type character varying(1) DEFAULT ''::character varying NOT NULL,
+    expiration bigint DEFAULT 0 NOT NULL,
+    max_downloads bigint DEFAULT 0 NOT NULL,
+    max_bytes bigint DEFAULT 0 NOT NULL,
+    downloads bigint DEFAULT 0 NOT NULL,
+    bytes_downloaded bigint DEFAULT 0 NOT NULL,
+    revoked boolean DEFAULT false NOT NULL,
+    revoked_at timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (allocation_id, ticket_hash)
+);
+
+ALTER TABLE auth_tickets OWNER TO blobber_user;
+
+CREATE INDEX idx_auth_tickets_revoked ON auth_tickets (revoked) WHERE revoked;
+-- +goose StatementEnd
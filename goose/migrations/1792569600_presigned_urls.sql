@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE presigned_url_revocations (
+    allocation_id character varying(64) NOT NULL,
+    url_id character varying(64) NOT NULL,
+    revoked_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (allocation_id, url_id)
+);
+
+ALTER TABLE presigned_url_revocations OWNER TO blobber_user;
+-- +goose StatementEnd
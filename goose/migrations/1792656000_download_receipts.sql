@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE download_receipts (
+    id bigserial PRIMARY KEY,
+    allocation_id character varying(64) NOT NULL,
+    blobber_id character varying(64) NOT NULL,
+    client_id character varying(64) NOT NULL,
+    path_hash character varying(64) NOT NULL,
+    start_block bigint NOT NULL,
+    num_blocks bigint NOT NULL,
+    read_counter bigint NOT NULL,
+    "timestamp" bigint NOT NULL,
+    signature text NOT NULL,
+    created_at timestamp with time zone
+);
+
+ALTER TABLE download_receipts OWNER TO blobber_user;
+
+CREATE INDEX idx_download_receipts_client ON download_receipts (allocation_id, client_id, id);
+-- +goose StatementEnd
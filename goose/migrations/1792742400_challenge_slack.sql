@@ -0,0 +1,5 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE challenge_timing ADD COLUMN validation_slack bigint DEFAULT 0 NOT NULL;
+ALTER TABLE challenge_timing ADD COLUMN submission_slack bigint DEFAULT 0 NOT NULL;
+-- +goose StatementEnd
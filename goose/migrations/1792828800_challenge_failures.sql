@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE challenge_failures (
+    challenge_id character varying(64) PRIMARY KEY,
+    allocation_id character varying(64) NOT NULL,
+    category character varying(32) NOT NULL,
+    message text,
+    evidence jsonb,
+    created_at bigint NOT NULL
+);
+
+ALTER TABLE challenge_failures OWNER TO blobber_user;
+
+CREATE INDEX idx_challenge_failures_allocation ON challenge_failures (allocation_id, category);
+CREATE INDEX idx_challenge_failures_created_at ON challenge_failures (created_at);
+-- +goose StatementEnd
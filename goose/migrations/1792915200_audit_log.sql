@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE audit_log (
+    id bigserial PRIMARY KEY,
+    operation character varying(32) NOT NULL,
+    client_id character varying(64),
+    ip character varying(64),
+    allocation_id character varying(64),
+    paths jsonb,
+    result character varying(16) NOT NULL,
+    message text,
+    write_marker_root character varying(64),
+    created_at bigint NOT NULL,
+    prev_hash character varying(64) NOT NULL,
+    hash character varying(64) NOT NULL
+);
+
+ALTER TABLE audit_log OWNER TO blobber_user;
+
+CREATE INDEX idx_audit_log_allocation ON audit_log (allocation_id, created_at);
+CREATE INDEX idx_audit_log_client ON audit_log (client_id, created_at);
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+-- the log is append-only
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+-- +goose StatementEnd
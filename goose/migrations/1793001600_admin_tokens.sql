@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE admin_tokens (
+    id bigserial PRIMARY KEY,
+    name character varying(64) NOT NULL,
+    hash character varying(64) NOT NULL UNIQUE,
+    scopes jsonb NOT NULL,
+    revoked_at bigint NOT NULL DEFAULT 0,
+    expires_at bigint NOT NULL,
+    last_used_at bigint NOT NULL DEFAULT 0,
+    created_at bigint NOT NULL
+);
+
+ALTER TABLE admin_tokens OWNER TO blobber_user;
+
+CREATE INDEX idx_admin_tokens_name ON admin_tokens (name);
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE client_access_rules (
+    subject_type character varying(8) NOT NULL,
+    subject character varying(64) NOT NULL,
+    action character varying(8) NOT NULL,
+    reason text,
+    expires_at bigint NOT NULL DEFAULT 0,
+    created_at bigint NOT NULL,
+    PRIMARY KEY (subject_type, subject)
+);
+
+ALTER TABLE client_access_rules OWNER TO blobber_user;
+
+CREATE TABLE client_quota_overrides (
+    client_id character varying(64) PRIMARY KEY,
+    upload_limit_monthly bigint,
+    block_limit_daily bigint,
+    block_limit_monthly bigint,
+    commit_limit_daily bigint,
+    commit_limit_monthly bigint,
+    reason text,
+    expires_at bigint NOT NULL DEFAULT 0,
+    created_at bigint NOT NULL
+);
+
+ALTER TABLE client_quota_overrides OWNER TO blobber_user;
+-- +goose StatementEnd
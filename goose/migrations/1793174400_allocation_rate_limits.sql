@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE allocation_rate_limits (
+    allocation_id character varying(64) PRIMARY KEY,
+    rps double precision,
+    upload_bps bigint,
+    download_bps bigint,
+    reason text,
+    created_at bigint NOT NULL
+);
+
+ALTER TABLE allocation_rate_limits OWNER TO blobber_user;
+-- +goose StatementEnd
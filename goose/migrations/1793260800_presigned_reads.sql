@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE presigned_reads (
+    id bigserial PRIMARY KEY,
+    allocation_id character varying(64) NOT NULL,
+    owner_id character varying(64) NOT NULL,
+    url_id character varying(64) NOT NULL,
+    path_hash character varying(64) NOT NULL,
+    start_block bigint NOT NULL,
+    num_blocks bigint NOT NULL,
+    settled boolean NOT NULL DEFAULT false,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX idx_presigned_reads_owner_created ON presigned_reads (owner_id, created_at);
+CREATE INDEX idx_presigned_reads_unsettled ON presigned_reads (allocation_id, owner_id) WHERE NOT settled;
+
+ALTER TABLE presigned_reads OWNER TO blobber_user;
+-- +goose StatementEnd
@@ -0,0 +1,4 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE read_markers ADD COLUMN deferred_since bigint DEFAULT 0 NOT NULL;
+-- +goose StatementEnd
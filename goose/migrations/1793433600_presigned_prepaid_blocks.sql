@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE presigned_reads;
+
+CREATE TABLE presigned_balances (
+    allocation_id character varying(64) PRIMARY KEY,
+    blocks bigint NOT NULL DEFAULT 0
+);
+
+ALTER TABLE presigned_balances OWNER TO blobber_user;
+
+CREATE TABLE presigned_daily_blocks (
+    owner_id character varying(64) NOT NULL,
+    day date NOT NULL,
+    blocks bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner_id, day)
+);
+
+ALTER TABLE presigned_daily_blocks OWNER TO blobber_user;
+-- +goose StatementEnd